@@ -0,0 +1,30 @@
+resource "agentsmith_claude_hook" "guard" {
+  scope   = "project"
+  name    = "guard.sh"
+  event   = "PreToolUse"
+  matcher = "Bash"
+
+  content = <<-EOT
+    #!/bin/sh
+    if grep -q 'rm -rf'; then
+      echo 'Refusing to run rm -rf' >&2
+      exit 2
+    fi
+  EOT
+}
+
+# Assert that the guard hook denies rm -rf before it is rolled out.
+check "guard_denies_rm_rf" {
+  data "agentsmith_claude_hook_run" "rm_rf" {
+    command    = agentsmith_claude_hook.guard.command
+    event      = "PreToolUse"
+    tool_name  = "Bash"
+    tool_input = jsonencode({ command = "rm -rf /" })
+    timeout    = 10
+  }
+
+  assert {
+    condition     = data.agentsmith_claude_hook_run.rm_rf.blocked
+    error_message = "The guard hook did not block rm -rf: ${data.agentsmith_claude_hook_run.rm_rf.stderr}"
+  }
+}
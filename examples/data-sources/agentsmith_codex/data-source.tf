@@ -27,22 +27,22 @@ output "codex_config" {
     active_profile = data.agentsmith_codex.example.active_profile
     source_files   = data.agentsmith_codex.example.source_files
     environment = {
-      codex_home                   = data.agentsmith_codex.example.environment.codex_home
-      openai_base_url              = data.agentsmith_codex.example.environment.openai_base_url
-      sandbox_network_disabled     = data.agentsmith_codex.example.environment.codex_sandbox_network_disabled
+      codex_home               = data.agentsmith_codex.example.environment.codex_home
+      openai_base_url          = data.agentsmith_codex.example.environment.openai_base_url
+      sandbox_network_disabled = data.agentsmith_codex.example.environment.codex_sandbox_network_disabled
     }
     effective_config = {
       # Configuration merged from all sources with precedence order:
       # project > user > system
-      provider         = data.agentsmith_codex.example.effective_config.provider
-      model           = data.agentsmith_codex.example.effective_config.model
-      temperature     = data.agentsmith_codex.example.effective_config.temperature
-      max_tokens      = data.agentsmith_codex.example.effective_config.max_tokens
-      system_prompt   = data.agentsmith_codex.example.effective_config.system_prompt
-      auto_commit     = data.agentsmith_codex.example.effective_config.auto_commit
-      auto_test       = data.agentsmith_codex.example.effective_config.auto_test
-      editor          = data.agentsmith_codex.example.effective_config.editor
-      shell           = data.agentsmith_codex.example.effective_config.shell
+      provider      = data.agentsmith_codex.example.effective_config.provider
+      model         = data.agentsmith_codex.example.effective_config.model
+      temperature   = data.agentsmith_codex.example.effective_config.temperature
+      max_tokens    = data.agentsmith_codex.example.effective_config.max_tokens
+      system_prompt = data.agentsmith_codex.example.effective_config.system_prompt
+      auto_commit   = data.agentsmith_codex.example.effective_config.auto_commit
+      auto_test     = data.agentsmith_codex.example.effective_config.auto_test
+      editor        = data.agentsmith_codex.example.effective_config.editor
+      shell         = data.agentsmith_codex.example.effective_config.shell
     }
   }
 }
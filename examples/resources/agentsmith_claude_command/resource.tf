@@ -1,6 +1,23 @@
-resource "agentsmith_claude_command" "example" {
-  name        = "my-custom-command"
-  description = "This is a custom command for Claude."
-  command     = "echo 'Hello from my-custom-command'"
-  subagent    = "default" # or the name of a specific subagent
+# Invoked as /review-pr 123 high
+resource "agentsmith_claude_command" "review_pr" {
+  scope         = "project"
+  name          = "review-pr"
+  description   = "Review a pull request"
+  argument_hint = "[pr-number] [priority]"
+  allowed_tools = ["Bash(gh pr view:*)", "Bash(gh pr diff:*)", "Read"]
+
+  body = <<-EOT
+    Review pull request #$1 with priority $2.
+    Focus on correctness, security and test coverage.
+  EOT
+}
+
+# Invoked as /frontend:component Button
+resource "agentsmith_claude_command" "component" {
+  scope     = "project"
+  namespace = "frontend"
+  name      = "component"
+  model     = "sonnet"
+
+  body = "Create a React component named $ARGUMENTS following the conventions in src/components.\n"
 }
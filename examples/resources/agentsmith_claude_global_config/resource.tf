@@ -1,8 +1,17 @@
+data "agentsmith_mcp_stdio" "memory" {
+  command = "npx"
+  args    = ["-y", "@modelcontextprotocol/server-memory"]
+}
+
 resource "agentsmith_claude_global_config" "example" {
-  # This resource manages the global ~/.claude/config.json file.
+  # This resource manages preferences and user-scope MCP servers in ~/.claude.json.
+  # Claude Code's runtime state in that file is left untouched.
   # It is a singleton resource; only one should be defined.
 
-  model             = "claude-3-opus-20240229"
-  output_style      = "pretty"
-  cleanup_period_days = 30
+  theme                   = "dark"
+  preferred_notif_channel = "terminal_bell"
+
+  mcp_servers = {
+    memory = data.agentsmith_mcp_stdio.memory.json
+  }
 }
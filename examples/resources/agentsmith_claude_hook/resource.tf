@@ -1,5 +1,27 @@
-resource "agentsmith_claude_hook" "example" {
-  name    = "pre-prompt"
-  command = "echo 'A new prompt is about to be sent.'"
-  subagent = "default" # or the name of a specific subagent
+# Writes .claude/hooks/lint.sh and registers it in .claude/settings.json, so Claude Code
+# runs it after every file edit. Other hooks in the settings file are left intact.
+resource "agentsmith_claude_hook" "lint" {
+  scope   = "project"
+  name    = "lint.sh"
+  event   = "PostToolUse"
+  matcher = "Edit|Write"
+  timeout = 60
+
+  content = <<-EOT
+    #!/bin/sh
+    make lint
+  EOT
+}
+
+# Registers a user hook in the project's local settings only.
+resource "agentsmith_claude_hook" "notify" {
+  scope          = "user"
+  name           = "notify.sh"
+  event          = "Stop"
+  settings_scope = "local"
+
+  content = <<-EOT
+    #!/bin/sh
+    notify-send "Claude Code finished"
+  EOT
 }
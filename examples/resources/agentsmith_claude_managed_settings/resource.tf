@@ -0,0 +1,22 @@
+data "agentsmith_mcp_remote" "docs" {
+  url       = "https://mcp.example.com/mcp"
+  transport = "streamable-http"
+}
+
+resource "agentsmith_claude_managed_settings" "policy" {
+  # Writes managed-settings.json and managed-mcp.json under the system root.
+  # Defaults to /etc/claude-code on Linux; override to build images or to test.
+  system_root = "/etc/claude-code"
+
+  # Managed policy files are owned entirely by the platform team.
+  merge_strategy = "replace_all"
+
+  permissions {
+    disable_bypass_permissions_mode = "disable"
+    deny                            = ["Read(./.env)", "Read(./secrets/**)", "Bash(curl:*)"]
+  }
+
+  mcp_servers = {
+    docs = data.agentsmith_mcp_remote.docs.json
+  }
+}
@@ -0,0 +1,18 @@
+data "agentsmith_mcp_stdio" "memory" {
+  command = "npx"
+  args    = ["-y", "@modelcontextprotocol/server-memory"]
+}
+
+# Writes <workdir>/.mcp.json, the project-scoped MCP servers shared with the team.
+resource "agentsmith_claude_mcp_json" "example" {
+  mcp_servers = {
+    memory = data.agentsmith_mcp_stdio.memory.json
+
+    # Servers can also be written directly in Claude Code's dialect.
+    docs = jsonencode({
+      type    = "http"
+      url     = "https://mcp.example.com/mcp"
+      headers = { Authorization = "Bearer $${DOCS_TOKEN}" }
+    })
+  }
+}
@@ -0,0 +1,12 @@
+# Each module can contribute its own rules to the shared project settings file.
+resource "agentsmith_claude_permission_rule" "make_test" {
+  scope = "project"
+  list  = "allow"
+  rule  = "Bash(make test:*)"
+}
+
+resource "agentsmith_claude_permission_rule" "env_files" {
+  scope = "project"
+  list  = "deny"
+  rule  = "Read(./.env)"
+}
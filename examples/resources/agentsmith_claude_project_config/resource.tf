@@ -0,0 +1,14 @@
+data "agentsmith_mcp_remote" "docs" {
+  url       = "https://mcp.example.com/mcp"
+  transport = "streamable-http"
+}
+
+# Manages the entry for the provider's working directory under `projects` in ~/.claude.json.
+resource "agentsmith_claude_project_config" "example" {
+  allowed_tools             = ["Bash(npm run test:*)"]
+  has_trust_dialog_accepted = true
+
+  mcp_servers = {
+    docs = data.agentsmith_mcp_remote.docs.json
+  }
+}
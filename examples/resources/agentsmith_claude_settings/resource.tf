@@ -3,12 +3,48 @@ resource "agentsmith_claude_settings" "project" {
   # Scope can be "project", "user", or "local".
   scope = "project"
 
+  # Keep keys written by Claude Code or teammates that are not managed here.
+  merge_strategy = "preserve_unknown"
+
+  # Keep a copy of the previous file at settings.json.bak on every write.
+  backup_on_write = true
+
   model        = "claude-3-5-sonnet-20240620"
   output_style = "concise"
 
-  permissions = {
-    default_mode = "ask"
-    allow        = ["read", "write"]
-    deny         = ["network"]
+  permissions {
+    default_mode = "acceptEdits"
+    allow        = ["Bash(npm run test:*)", "Read(./src/**)", "WebFetch(domain:docs.example.com)"]
+    ask          = ["Bash(git push:*)"]
+    deny         = ["Read(./.env)", "Read(./secrets/**)", "Bash(curl:*)"]
+  }
+
+  # Settings without a dedicated attribute are deep-merged into the file.
+  extra_settings = jsonencode({
+    spinnerTipsEnabled   = false
+    companyAnnouncements = ["Run `make check` before pushing."]
+    enabledPlugins       = { "formatter@acme-tools" = true }
+  })
+}
+
+resource "agentsmith_claude_settings" "hooks" {
+  scope = "local"
+
+  hooks {
+    event   = "PreToolUse"
+    matcher = "Bash"
+
+    hook {
+      command = "./scripts/validate-command.sh"
+      timeout = 30
+    }
+  }
+
+  hooks {
+    event = "Stop"
+
+    hook {
+      command = "notify-send 'Claude Code finished'"
+    }
   }
 }
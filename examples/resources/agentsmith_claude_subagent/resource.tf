@@ -1,15 +1,20 @@
 resource "agentsmith_claude_subagent" "example" {
-  name = "my-subagent"
+  scope       = "project"
+  name        = "code-reviewer"
+  description = "Reviews changes for correctness and style. Use after every code change."
+  model       = "sonnet"
 
-  # The persona is a markdown-formatted string that defines
-  # the subagent's personality and instructions.
-  persona = <<-EOT
-    # My Subagent Persona
+  # Restrict the reviewer to read-only tools. Omit to inherit every tool.
+  tools = ["Read", "Grep", "Glob"]
 
-    You are a helpful assistant that specializes in writing Terraform code.
-    Always provide clear explanations for the code you generate.
-  EOT
+  # Frontmatter keys without a dedicated attribute.
+  extra_frontmatter = {
+    permissionMode = "plan"
+  }
 
-  # Tools are other commands or agents this subagent can use.
-  tools = ["default:read-file", "default:write-file"]
+  # The prompt forms the markdown body of the subagent file.
+  prompt = <<-EOT
+    You are a senior engineer reviewing a change.
+    Point out bugs first, then style issues, and keep the review short.
+  EOT
 }
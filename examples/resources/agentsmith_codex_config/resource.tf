@@ -13,39 +13,39 @@ provider "agentsmith" {
 
 # Example 1: User-level Codex configuration file
 resource "agentsmith_codex_config" "user_config" {
-  scope                       = "user"
-  create_directories          = true
+  scope                      = "user"
+  create_directories         = true
   backup_on_write            = true
   allow_sensitive_env_writes = false
   validate_strict            = true
-  
+
   # Basic configuration
-  profile                    = "default"
-  model                     = "gpt-4o"
-  model_provider           = "openai"
-  model_context_window     = 128000
-  model_max_output_tokens  = 4096
-  approval_policy          = "ask"
-  sandbox_mode             = "safe"
-  file_opener              = "code"
-  hide_agent_reasoning     = false
-  show_raw_agent_reasoning = false
-  model_reasoning_effort   = "medium"
-  model_reasoning_summary  = "auto"
-  model_verbosity         = "normal"
+  profile                            = "default"
+  model                              = "gpt-4o"
+  model_provider                     = "openai"
+  model_context_window               = 128000
+  model_max_output_tokens            = 4096
+  approval_policy                    = "ask"
+  sandbox_mode                       = "safe"
+  file_opener                        = "code"
+  hide_agent_reasoning               = false
+  show_raw_agent_reasoning           = false
+  model_reasoning_effort             = "medium"
+  model_reasoning_summary            = "auto"
+  model_verbosity                    = "normal"
   model_supports_reasoning_summaries = true
-  project_doc_max_bytes   = 1048576
-  
+  project_doc_max_bytes              = 1048576
+
   # Notification settings
   notify = ["desktop", "sound"]
-  
+
   # TUI settings
   tui = {
-    theme = "dark"
+    theme             = "dark"
     show_line_numbers = "true"
-    tab_size = "2"
+    tab_size          = "2"
   }
-  
+
   # Sandbox workspace configuration
   sandbox_workspace_write {
     writable_roots = [
@@ -59,13 +59,13 @@ resource "agentsmith_codex_config" "user_config" {
       "id_rsa*"
     ]
   }
-  
+
   # History settings
   history {
     keep_last_n = 100
     auto_save   = true
   }
-  
+
   # Shell environment policy
   shell_environment_policy {
     inherit_from_parent = true
@@ -80,78 +80,68 @@ resource "agentsmith_codex_config" "user_config" {
       "OPENAI_API_KEY"
     ]
   }
-  
+
   # Model providers configuration
   model_providers {
     name        = "openai"
     description = "OpenAI GPT models"
-    
+
     models {
-      name              = "gpt-4o"
-      supports_tools    = true
-      supports_vision   = true
-      context_window    = 128000
-      max_output_tokens = 4096
+      name                    = "gpt-4o"
+      supports_tools          = true
+      supports_vision         = true
+      context_window          = 128000
+      max_output_tokens       = 4096
       reasoning_effort_levels = ["low", "medium", "high"]
     }
-    
+
     models {
-      name              = "gpt-4o-mini"
-      supports_tools    = true
-      supports_vision   = true
-      context_window    = 128000
-      max_output_tokens = 16384
+      name                    = "gpt-4o-mini"
+      supports_tools          = true
+      supports_vision         = true
+      context_window          = 128000
+      max_output_tokens       = 16384
       reasoning_effort_levels = ["low", "medium"]
     }
-    
+
     authentication {
-      type      = "api_key"
-      env_var   = "OPENAI_API_KEY"
-      required  = true
+      type     = "api_key"
+      env_var  = "OPENAI_API_KEY"
+      required = true
     }
-    
+
     request_options {
       timeout = 60
       retries = 3
     }
   }
-  
+
   # MCP servers configuration
   mcp_servers {
-    name        = "filesystem"
-    description = "File system operations"
-    
-    transport {
-      type    = "stdio"
-      command = "mcp-filesystem-server"
-      args    = ["--safe-mode"]
-    }
+    id                  = "filesystem"
+    command             = "mcp-filesystem-server"
+    args                = ["--safe-mode"]
+    cwd                 = "~/Projects"
+    startup_timeout_sec = 20
   }
-  
+
   mcp_servers {
-    name        = "database"
-    description = "Database operations"
-    
-    transport {
-      type    = "stdio" 
-      command = "mcp-database-server"
-      args    = ["--host", "localhost", "--port", "5432"]
-    }
-    
-    environment {
-      DB_PASSWORD = "sensitive_value"
-    }
+    id                   = "docs"
+    url                  = "https://mcp.example.com/mcp"
+    bearer_token_env_var = "DOCS_MCP_TOKEN"
+    tool_timeout_sec     = 120
+    disabled_tools       = ["delete_page"]
   }
-  
+
   # Profiles configuration
   profiles {
     name = "development"
-    
-    model                = "gpt-4o-mini"
-    approval_policy      = "auto"
-    sandbox_mode         = "permissive"
+
+    model                  = "gpt-4o-mini"
+    approval_policy        = "on-request"
+    sandbox_mode           = "workspace-write"
     model_reasoning_effort = "low"
-    
+
     # Override some settings for development
     sandbox_workspace_write {
       writable_roots = [
@@ -161,57 +151,48 @@ resource "agentsmith_codex_config" "user_config" {
       network_access = true
     }
   }
-  
+
   profiles {
     name = "production"
-    
-    model           = "gpt-4o"
-    approval_policy = "strict"
-    sandbox_mode   = "restricted"
-    model_reasoning_effort = "high"
-    
+
+    model                   = "gpt-4o"
+    approval_policy         = "untrusted"
+    sandbox_mode            = "read-only"
+    model_reasoning_effort  = "high"
+    model_reasoning_summary = "detailed"
+    hide_agent_reasoning    = true
+
     # Strict settings for production
-    sandbox_workspace_write {
-      writable_roots = [
-        "~/prod-workspace"
-      ]
-      network_access = false
-      exclude_patterns = [
-        "*",
-        "!*.txt",
-        "!*.md"
-      ]
-    }
-    
     shell_environment_policy {
-      inherit_from_parent = false
-      allowed_env_vars = [
-        "PATH"
-      ]
+      inherit      = "core"
+      include_only = ["PATH", "HOME"]
     }
   }
 }
 
 # Example 2: Project-level Codex configuration
 resource "agentsmith_codex_config" "project_config" {
-  scope              = "project" 
+  scope              = "project"
   path               = ".codex/config.toml"
   create_directories = true
   merge_strategy     = "merge"
-  
+
+  # MCP servers configured outside Terraform are removed; other tables merge entry by entry
+  owned_tables = ["mcp_servers"]
+
   # Override settings for this project
   profile         = "terraform"
-  model          = "gpt-4o"
+  model           = "gpt-4o"
   approval_policy = "ask"
-  
+
   # Project-specific TUI settings
   tui = {
-    theme           = "solarized-dark"
+    theme             = "solarized-dark"
     show_line_numbers = "true"
-    tab_size        = "4"
-    word_wrap       = "true"
+    tab_size          = "4"
+    word_wrap         = "true"
   }
-  
+
   # Project-specific sandbox settings
   sandbox_workspace_write {
     writable_roots = [
@@ -227,35 +208,32 @@ resource "agentsmith_codex_config" "project_config" {
       "*.secret"
     ]
   }
-  
+
   # Project-specific MCP servers
   mcp_servers {
     name = "terraform"
-    
+
     transport {
       type    = "stdio"
       command = "mcp-terraform-server"
       args    = ["--workspace", "development"]
     }
   }
-  
+
   # Terraform-specific profile
   profiles {
     name = "terraform"
-    
-    model               = "gpt-4o"
-    approval_policy     = "ask"
+
+    model                 = "gpt-4o"
+    approval_policy       = "on-request"
     project_doc_max_bytes = 2097152 # 2MB for larger Terraform docs
-    
+
     sandbox_workspace_write {
       writable_roots = [
         ".",
         "./modules"
       ]
-      exclude_patterns = [
-        "*.tfstate*",
-        ".terraform/"
-      ]
+      exclude_slash_tmp = true
     }
   }
 }
@@ -263,17 +241,17 @@ resource "agentsmith_codex_config" "project_config" {
 # Example 3: System-level configuration
 resource "agentsmith_codex_config" "system_config" {
   scope                      = "system"
-  path                      = "/etc/codex/config.toml"
-  create_directories        = true
+  path                       = "/etc/codex/config.toml"
+  create_directories         = true
   allow_sensitive_env_writes = false
-  validate_strict           = true
-  keep_file_on_destroy      = true
-  
+  validate_strict            = true
+  keep_file_on_destroy       = true
+
   # System-wide defaults
-  approval_policy    = "strict"
-  sandbox_mode      = "safe"
-  model_verbosity   = "quiet"
-  
+  approval_policy = "strict"
+  sandbox_mode    = "safe"
+  model_verbosity = "quiet"
+
   # System-wide security settings
   sandbox_workspace_write {
     writable_roots = [
@@ -289,7 +267,7 @@ resource "agentsmith_codex_config" "system_config" {
       "*.p12"
     ]
   }
-  
+
   # Restrictive shell environment
   shell_environment_policy {
     inherit_from_parent = false
@@ -307,7 +285,7 @@ resource "agentsmith_codex_config" "system_config" {
       "*_PASSWORD"
     ]
   }
-  
+
   # System-wide history settings
   history {
     keep_last_n = 50
@@ -320,7 +298,7 @@ output "codex_config_paths" {
   description = "Paths to the created Codex configuration files"
   value = {
     user_config    = agentsmith_codex_config.user_config.resolved_path
-    project_config = agentsmith_codex_config.project_config.resolved_path  
+    project_config = agentsmith_codex_config.project_config.resolved_path
     system_config  = agentsmith_codex_config.system_config.resolved_path
   }
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+# A module can contribute its own MCP servers to the user's config.toml without owning the file.
+data "agentsmith_mcp_stdio" "filesystem" {
+  command = "mcp-filesystem-server"
+  args    = ["--safe-mode"]
+}
+
+resource "agentsmith_codex_mcp_server" "filesystem" {
+  scope       = "home"
+  id          = "filesystem"
+  server_json = data.agentsmith_mcp_stdio.filesystem.json
+
+  startup_timeout_sec = 20
+}
+
+# The transport can also be set directly.
+resource "agentsmith_codex_mcp_server" "docs" {
+  scope                = "project"
+  id                   = "docs"
+  url                  = "https://mcp.example.com/mcp"
+  bearer_token_env_var = "DOCS_MCP_TOKEN"
+  disabled_tools       = ["delete_page"]
+}
@@ -0,0 +1,13 @@
+# A module can contribute its own model provider to the user's config.toml without owning the file.
+resource "agentsmith_codex_model_provider" "azure" {
+  scope    = "home"
+  id       = "azure"
+  name     = "Azure OpenAI"
+  base_url = "https://my-resource.openai.azure.com/openai"
+  env_key  = "AZURE_OPENAI_API_KEY"
+  wire_api = "responses"
+
+  query_params = {
+    "api-version" = "2025-04-01-preview"
+  }
+}
@@ -0,0 +1,15 @@
+# Writes $CODEX_HOME/notify.sh and points notify at it, so Codex runs it whenever a turn
+# completes. The rest of config.toml is left intact.
+resource "agentsmith_codex_notify" "desktop" {
+  scope = "home"
+  name  = "notify.sh"
+  args  = ["Codex"]
+
+  # Codex appends the JSON payload describing the event after args.
+  content = <<-EOT
+    #!/bin/sh
+    title="$1"
+    message=$(printf '%s' "$2" | jq -r '."last-assistant-message" // "Turn complete"')
+    notify-send "$title" "$message"
+  EOT
+}
@@ -0,0 +1,15 @@
+# A module can contribute its own profile to the user's config.toml without owning the file.
+resource "agentsmith_codex_profile" "deep" {
+  scope = "home"
+  name  = "deep"
+
+  model                   = "o3"
+  model_provider          = agentsmith_codex_model_provider.azure.id
+  approval_policy         = "on-request"
+  model_reasoning_effort  = "high"
+  model_reasoning_summary = "detailed"
+
+  sandbox_workspace_write {
+    network_access = true
+  }
+}
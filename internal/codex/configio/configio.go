@@ -7,10 +7,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	toml "github.com/pelletier/go-toml/v2"
+
+	"terraform-provider-agentsmith/internal/fileio"
 )
 
 // KnownTopLevelKeys is the set of recognized TOML keys for Codex config.
@@ -100,27 +104,282 @@ func ValidateUnknownKeys(m map[string]any) (unknown []string) {
 	return
 }
 
-// MergePreserveUnknown overlays desired onto existing for keys present in desired only, preserving all other keys.
-func MergePreserveUnknown(existing, desired map[string]any) map[string]any {
-	out := map[string]any{}
+// MergePreserveUnknown overlays desired onto existing, preserving every key that desired does not
+// set. Tables merge recursively, so a server declared in mcp_servers leaves the other servers
+// alone, and keys set by hand inside a managed table are kept. Tables whose dotted path is in
+// owned are replaced wholesale by desired instead.
+//
+// The key paths written are recorded for owner in the __agentsmith marker table, so that keys
+// owner wrote before but no longer sets are removed rather than left behind.
+func MergePreserveUnknown(existing, desired map[string]any, owner string, owned ...string) map[string]any {
+	out, keys := MergeKeyPaths(existing, desired, OwnedKeys(existing, owner), owned...)
+
+	// ensure internal marker table exists
+	ensureAgentsmithMarker(out, owner, keys)
+	return out
+}
+
+// MergeKeyPaths merges as MergePreserveUnknown does, for documents that cannot carry the
+// __agentsmith marker table, such as JSON settings files. The key paths written before are
+// passed as prior, and the key paths desired writes are returned for the caller to keep.
+func MergeKeyPaths(existing, desired map[string]any, prior [][]string, owned ...string) (map[string]any, [][]string) {
+	wholesale := make(map[string]bool, len(owned))
+	for _, p := range owned {
+		wholesale[p] = true
+	}
+	out := mergeTables("", existing, desired, wholesale)
+
+	keys := KeyPaths(desired, owned...)
+	for _, p := range prior {
+		if !overlapsKeyPaths(p, keys) {
+			out = DeleteKeyPath(out, p)
+		}
+	}
+	return out, keys
+}
+
+// KeyPaths returns the sorted key paths of the values set in desired, descending into tables
+// unless their dotted path is in owned.
+func KeyPaths(desired map[string]any, owned ...string) [][]string {
+	wholesale := make(map[string]bool, len(owned))
+	for _, p := range owned {
+		wholesale[p] = true
+	}
+	keys := ownedKeyPaths(nil, desired, wholesale)
+	slices.SortFunc(keys, slices.Compare)
+	return keys
+}
+
+func mergeTables(path string, existing, desired map[string]any, owned map[string]bool) map[string]any {
+	out := make(map[string]any, len(existing)+len(desired))
 	for k, v := range existing {
 		out[k] = v
 	}
 	for k, v := range desired {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		want, wantTable := asTOMLTable(v)
+		have, haveTable := asTOMLTable(out[k])
+		if wantTable && haveTable && !owned[p] {
+			out[k] = mergeTables(p, have, want, owned)
+			continue
+		}
 		out[k] = v
 	}
-	// ensure internal marker table exists
-	ensureAgentsmithMarker(out)
 	return out
 }
 
-func ensureAgentsmithMarker(m map[string]any) {
-	t, _ := m["__agentsmith"].(map[string]any)
-	if t == nil {
-		t = map[string]any{}
+// Managed returns the parts of existing that MergePreserveUnknown would manage when merging
+// desired into it: the keys that desired sets, recursing into tables that merge, and tables whose
+// dotted path is in owned as a whole.
+func Managed(existing, desired map[string]any, owned ...string) map[string]any {
+	wholesale := make(map[string]bool, len(owned))
+	for _, p := range owned {
+		wholesale[p] = true
+	}
+	return managedTables("", existing, desired, wholesale)
+}
+
+func managedTables(path string, existing, desired map[string]any, owned map[string]bool) map[string]any {
+	out := map[string]any{}
+	for k, v := range desired {
+		have, ok := existing[k]
+		if !ok {
+			continue
+		}
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		want, wantTable := asTOMLTable(v)
+		haveTable, isTable := asTOMLTable(have)
+		if wantTable && isTable && !owned[p] {
+			out[k] = managedTables(p, haveTable, want, owned)
+			continue
+		}
+		out[k] = have
+	}
+	return out
+}
+
+// asTOMLTable returns v as a table, accepting the map[string]string that string maps are built as.
+func asTOMLTable(v any) (map[string]any, bool) {
+	switch t := v.(type) {
+	case map[string]any:
+		return t, true
+	case map[string]string:
+		out := make(map[string]any, len(t))
+		for k, s := range t {
+			out[k] = s
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// OwnedKeys returns the key paths recorded for owner in the __agentsmith marker table of m.
+func OwnedKeys(m map[string]any, owner string) [][]string {
+	marker, _ := m["__agentsmith"].(map[string]any)
+	owners, _ := marker["owned_keys"].(map[string]any)
+	var recorded []string
+	switch v := owners[owner].(type) {
+	case []string:
+		recorded = v
+	case []any:
+		for _, r := range v {
+			if s, ok := r.(string); ok {
+				recorded = append(recorded, s)
+			}
+		}
+	}
+
+	var out [][]string
+	for _, s := range recorded {
+		if key, end, err := parseTOMLKey([]byte(s), 0); err == nil && end == len(s) {
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// Owned returns the parts of m at the key paths recorded for owner, and whether any are recorded.
+func Owned(m map[string]any, owner string) (map[string]any, bool) {
+	keys := OwnedKeys(m, owner)
+	out := map[string]any{}
+	for _, key := range keys {
+		copyKeyPath(out, m, key)
+	}
+	return out, len(keys) > 0
+}
+
+// RemoveOwned returns m without the keys recorded for owner in the __agentsmith marker table,
+// dropping tables that become empty, and without the record itself. The marker table is removed
+// once no owner is recorded in it. m is not modified.
+func RemoveOwned(m map[string]any, owner string) map[string]any {
+	keys := OwnedKeys(m, owner)
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	for _, key := range keys {
+		out = DeleteKeyPath(out, key)
+	}
+	if _, ok := out["__agentsmith"]; ok {
+		ensureAgentsmithMarker(out, owner, nil)
+		if _, ok := out["__agentsmith"].(map[string]any)["owned_keys"]; !ok {
+			delete(out, "__agentsmith")
+		}
+	}
+	return out
+}
+
+// ownedKeyPaths returns the key paths of the values set in desired below path. Tables are
+// descended into unless they are owned wholesale or empty.
+func ownedKeyPaths(path []string, desired map[string]any, owned map[string]bool) [][]string {
+	var out [][]string
+	for k, v := range desired {
+		p := append(append([]string{}, path...), k)
+		if t, ok := asTOMLTable(v); ok && len(t) > 0 && !owned[strings.Join(p, ".")] {
+			out = append(out, ownedKeyPaths(p, t, owned)...)
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// overlapsKeyPaths reports whether key is one of keys, or a table above or below one of them.
+func overlapsKeyPaths(key []string, keys [][]string) bool {
+	for _, k := range keys {
+		if hasTOMLPrefix(key, k) || hasTOMLPrefix(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteKeyPath returns m without the value at key, dropping tables that become empty. Tables
+// along the path are copied rather than modified.
+func DeleteKeyPath(m map[string]any, key []string) map[string]any {
+	v, ok := m[key[0]]
+	if !ok {
+		return m
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	if len(key) == 1 {
+		delete(out, key[0])
+		return out
+	}
+	t, ok := asTOMLTable(v)
+	if !ok {
+		return m
+	}
+	if t = DeleteKeyPath(t, key[1:]); len(t) == 0 {
+		delete(out, key[0])
+	} else {
+		out[key[0]] = t
+	}
+	return out
+}
+
+// copyKeyPath copies the value at key in src, if any, into dst.
+func copyKeyPath(dst, src map[string]any, key []string) {
+	v, ok := src[key[0]]
+	if !ok {
+		return
+	}
+	if len(key) == 1 {
+		dst[key[0]] = v
+		return
+	}
+	t, ok := asTOMLTable(v)
+	if !ok {
+		return
+	}
+	sub, _ := dst[key[0]].(map[string]any)
+	if sub == nil {
+		sub = map[string]any{}
+		dst[key[0]] = sub
+	}
+	copyKeyPath(sub, t, key[1:])
+}
+
+func ensureAgentsmithMarker(m map[string]any, owner string, keys [][]string) {
+	t := map[string]any{}
+	if prior, ok := m["__agentsmith"].(map[string]any); ok {
+		for k, v := range prior {
+			t[k] = v
+		}
 	}
 	t["managed"] = true
 	t["updated_at"] = time.Now().UTC().Format(time.RFC3339)
+
+	owners := map[string]any{}
+	if prior, ok := t["owned_keys"].(map[string]any); ok {
+		for k, v := range prior {
+			owners[k] = v
+		}
+	}
+	recorded := make([]string, len(keys))
+	for i, key := range keys {
+		recorded[i] = formatTOMLKey(key...)
+	}
+	sort.Strings(recorded)
+	if len(recorded) > 0 {
+		owners[owner] = recorded
+	} else {
+		delete(owners, owner)
+	}
+	if len(owners) > 0 {
+		t["owned_keys"] = owners
+	} else {
+		delete(t, "owned_keys")
+	}
 	m["__agentsmith"] = t
 }
 
@@ -134,81 +393,23 @@ func MarshalTOML(m map[string]any) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// AtomicWrite writes bytes to a temp file and renames it to the path. If backup is true and the file exists,
-// creates a .bak copy first. Mode string is parsed in base-8 (e.g., "0600").
+// AtomicWrite writes bytes through fileio.AtomicWrite, prepending headerComment when set. If backup is true
+// and the file exists, creates a .bak copy first. Mode string is parsed in base-8 (e.g., "0600"); when
+// empty, an existing file keeps its mode and new files get 0600.
 func AtomicWrite(path string, data []byte, mode string, backup bool, createDirs bool, headerComment string) error {
-	dir := filepath.Dir(path)
-	if createDirs {
-		if err := os.MkdirAll(dir, 0o755); err != nil {
-			return fmt.Errorf("mkdir: %w", err)
-		}
-	}
-	// Optional backup
-	if backup {
-		if _, err := os.Stat(path); err == nil {
-			bak := path + ".bak"
-			if err := copyFile(path, bak); err != nil {
-				return fmt.Errorf("backup write: %w", err)
-			}
+	opts := fileio.WriteOptions{Mode: 0o600, KeepMode: true, Backup: backup, CreateDirs: createDirs}
+	if strings.TrimSpace(mode) != "" {
+		parsed, err := fileio.ParseMode(mode)
+		if err != nil {
+			return err
 		}
+		opts.Mode, opts.KeepMode = parsed, false
 	}
 	// Prepend header comment if requested
 	if strings.TrimSpace(headerComment) != "" {
 		data = append([]byte(headerComment+"\n"), data...)
 	}
-
-	tmp := path + ".tmp"
-	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
-	if err != nil {
-		return err
-	}
-	if _, err := f.Write(data); err != nil {
-		_ = f.Close()
-		return err
-	}
-	if err := f.Sync(); err != nil {
-		_ = f.Close()
-		return err
-	}
-	if err := f.Close(); err != nil {
-		return err
-	}
-	if err := os.Rename(tmp, path); err != nil {
-		return err
-	}
-	// Apply mode if provided
-	if strings.TrimSpace(mode) != "" {
-		// strip potential 0x or 0 prefix by parsing base 8 explicitly
-		var parsed os.FileMode
-		// Accept form like "0600" or "600"
-		if strings.HasPrefix(mode, "0") {
-			mode = strings.TrimLeft(mode, "0")
-		}
-		if mode == "" {
-			mode = "600"
-		}
-		// naive parse
-		var v uint64
-		for _, r := range mode {
-			if r < '0' || r > '7' {
-				return fmt.Errorf("invalid file_mode: %s", mode)
-			}
-			v = v*8 + uint64(r-'0')
-		}
-		parsed = os.FileMode(v)
-		if err := os.Chmod(path, parsed); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func copyFile(src, dst string) error {
-	in, err := os.ReadFile(src)
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(dst, in, 0o600)
+	return fileio.AtomicWrite(path, data, opts)
 }
 
 // SHA256Hex computes SHA256 hex of input string.
@@ -0,0 +1,269 @@
+package configio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergePreserveUnknown(t *testing.T) {
+	existing := map[string]any{
+		"model":   "o3",
+		"unknown": true,
+		"mcp_servers": map[string]any{
+			"local": map[string]any{"command": "local-server"},
+			"docs":  map[string]any{"command": "old", "env": map[string]any{"TOKEN": "secret"}},
+		},
+		"history": map[string]any{"persistence": "none", "max_bytes": int64(10)},
+	}
+	desired := map[string]any{
+		"model": "gpt-5",
+		"mcp_servers": map[string]any{
+			"docs": map[string]any{"command": "docs", "args": []string{"--stdio"}},
+		},
+		"history": map[string]any{"persistence": "save-all"},
+		"tui":     map[string]string{"theme": "dark"},
+	}
+
+	testCases := []struct {
+		name     string
+		owned    []string
+		expected map[string]any
+	}{
+		{
+			name: "tables merge per entry",
+			expected: map[string]any{
+				"model":   "gpt-5",
+				"unknown": true,
+				"mcp_servers": map[string]any{
+					"local": map[string]any{"command": "local-server"},
+					"docs":  map[string]any{"command": "docs", "args": []string{"--stdio"}, "env": map[string]any{"TOKEN": "secret"}},
+				},
+				"history": map[string]any{"persistence": "save-all", "max_bytes": int64(10)},
+				"tui":     map[string]string{"theme": "dark"},
+			},
+		},
+		{
+			name:  "owned tables are replaced",
+			owned: []string{"mcp_servers", "history"},
+			expected: map[string]any{
+				"model":   "gpt-5",
+				"unknown": true,
+				"mcp_servers": map[string]any{
+					"docs": map[string]any{"command": "docs", "args": []string{"--stdio"}},
+				},
+				"history": map[string]any{"persistence": "save-all"},
+				"tui":     map[string]string{"theme": "dark"},
+			},
+		},
+		{
+			name:  "owned entries are replaced",
+			owned: []string{"mcp_servers.docs"},
+			expected: map[string]any{
+				"model":   "gpt-5",
+				"unknown": true,
+				"mcp_servers": map[string]any{
+					"local": map[string]any{"command": "local-server"},
+					"docs":  map[string]any{"command": "docs", "args": []string{"--stdio"}},
+				},
+				"history": map[string]any{"persistence": "save-all", "max_bytes": int64(10)},
+				"tui":     map[string]string{"theme": "dark"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := MergePreserveUnknown(existing, desired, "test", tc.owned...)
+			if _, ok := merged["__agentsmith"]; !ok {
+				t.Error("Expected the __agentsmith marker table")
+			}
+			delete(merged, "__agentsmith")
+			if !reflect.DeepEqual(merged, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, merged)
+			}
+		})
+	}
+
+	if _, ok := existing["__agentsmith"]; ok {
+		t.Error("Expected existing to be left unmodified")
+	}
+}
+
+func TestMergePreserveUnknown_ownedKeys(t *testing.T) {
+	existing := map[string]any{
+		"model":           "o3",
+		"approval_policy": "never",
+		"mcp_servers": map[string]any{
+			"local": map[string]any{"command": "local-server"},
+			"docs":  map[string]any{"command": "docs", "args": []any{"--stdio"}, "env": map[string]any{"TOKEN": "secret"}},
+			"old":   map[string]any{"command": "old"},
+		},
+		"tui": map[string]any{"theme": "dark"},
+		"__agentsmith": map[string]any{
+			"managed": true,
+			"owned_keys": map[string]any{
+				"test":  []any{"approval_policy", "mcp_servers.docs.args", "mcp_servers.docs.command", "mcp_servers.old.command", "model", "tui"},
+				"other": []any{"tui.theme"},
+			},
+		},
+	}
+	desired := map[string]any{
+		"model":       "gpt-5",
+		"mcp_servers": map[string]any{"docs": map[string]any{"command": "docs"}},
+		"tui":         map[string]any{"theme": "light"},
+	}
+
+	merged := MergePreserveUnknown(existing, desired, "test")
+	marker := merged["__agentsmith"].(map[string]any)
+	delete(merged, "__agentsmith")
+	expected := map[string]any{
+		"model": "gpt-5",
+		"mcp_servers": map[string]any{
+			"local": map[string]any{"command": "local-server"},
+			"docs":  map[string]any{"command": "docs", "env": map[string]any{"TOKEN": "secret"}},
+		},
+		"tui": map[string]any{"theme": "light"},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected keys no longer set to be removed, got %v", merged)
+	}
+
+	owners := marker["owned_keys"].(map[string]any)
+	if !reflect.DeepEqual(owners["test"], []string{"mcp_servers.docs.command", "model", "tui.theme"}) {
+		t.Errorf("Expected the written keys to be recorded, got %v", owners["test"])
+	}
+	if owners["other"] == nil {
+		t.Error("Expected the keys of other owners to be kept")
+	}
+	if _, ok := existing["mcp_servers"].(map[string]any)["old"]; !ok {
+		t.Error("Expected existing to be left unmodified")
+	}
+
+	owned, ok := Owned(existing, "test")
+	if !ok {
+		t.Fatal("Expected recorded keys")
+	}
+	expected = map[string]any{
+		"model":           "o3",
+		"approval_policy": "never",
+		"mcp_servers": map[string]any{
+			"docs": map[string]any{"command": "docs", "args": []any{"--stdio"}},
+			"old":  map[string]any{"command": "old"},
+		},
+		"tui": map[string]any{"theme": "dark"},
+	}
+	if !reflect.DeepEqual(owned, expected) {
+		t.Errorf("Expected the recorded keys, got %v", owned)
+	}
+	if _, ok := Owned(existing, "missing"); ok {
+		t.Error("Expected no keys for an unknown owner")
+	}
+}
+
+func TestMergeKeyPaths(t *testing.T) {
+	existing := map[string]any{
+		"model":       "opus",
+		"env":         map[string]any{"DEBUG": "1", "OLD": "x"},
+		"permissions": map[string]any{"allow": []any{"Bash(make test:*)"}},
+	}
+	desired := map[string]any{
+		"model":       "sonnet",
+		"env":         map[string]any{"DEBUG": "0"},
+		"permissions": map[string]any{"deny": []any{"Read(./.env)"}},
+	}
+
+	merged, keys := MergeKeyPaths(existing, desired, [][]string{{"env", "OLD"}, {"model"}})
+	expected := map[string]any{
+		"model": "sonnet",
+		"env":   map[string]any{"DEBUG": "0"},
+		"permissions": map[string]any{
+			"allow": []any{"Bash(make test:*)"},
+			"deny":  []any{"Read(./.env)"},
+		},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected keys written before to be removed and others kept, got %v", merged)
+	}
+	if _, ok := merged["__agentsmith"]; ok {
+		t.Error("Expected no marker table")
+	}
+	if want := [][]string{{"env", "DEBUG"}, {"model"}, {"permissions", "deny"}}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Expected key paths %v, got %v", want, keys)
+	}
+}
+
+func TestRemoveOwned(t *testing.T) {
+	existing := map[string]any{
+		"model": "o3",
+		"profiles": map[string]any{
+			"deep": map[string]any{"model": "o3", "model_reasoning_effort": "high"},
+		},
+		"model_providers": map[string]any{
+			"azure": map[string]any{"env_key": "AZURE_KEY"},
+		},
+	}
+	merged := MergePreserveUnknown(existing, map[string]any{
+		"profiles": map[string]any{"fast": map[string]any{"model": "gpt-5"}},
+	}, "profile.fast", "profiles.fast")
+	merged = MergePreserveUnknown(merged, map[string]any{"model": "gpt-5"}, "other")
+
+	removed := RemoveOwned(merged, "profile.fast")
+	marker := removed["__agentsmith"].(map[string]any)
+	delete(removed, "__agentsmith")
+	expected := map[string]any{
+		"model":           "gpt-5",
+		"profiles":        existing["profiles"],
+		"model_providers": existing["model_providers"],
+	}
+	if !reflect.DeepEqual(removed, expected) {
+		t.Errorf("Expected only the owned table to be removed, got %v", removed)
+	}
+	owners := marker["owned_keys"].(map[string]any)
+	if _, ok := owners["profile.fast"]; ok {
+		t.Error("Expected the record of the owner to be removed")
+	}
+	if _, ok := owners["other"]; !ok {
+		t.Error("Expected the records of other owners to be kept")
+	}
+	if _, ok := merged["profiles"].(map[string]any)["fast"]; !ok {
+		t.Error("Expected merged to be left unmodified")
+	}
+
+	removed = RemoveOwned(RemoveOwned(merged, "profile.fast"), "other")
+	if _, ok := removed["__agentsmith"]; ok {
+		t.Error("Expected the marker table to be removed with the last owner")
+	}
+}
+
+func TestManaged(t *testing.T) {
+	existing := map[string]any{
+		"model":   "o3",
+		"unknown": true,
+		"mcp_servers": map[string]any{
+			"local": map[string]any{"command": "local-server"},
+			"docs":  map[string]any{"command": "old", "env": map[string]any{"TOKEN": "secret"}},
+		},
+	}
+	desired := map[string]any{
+		"model":        "gpt-5",
+		"sandbox_mode": "read-only",
+		"mcp_servers": map[string]any{
+			"docs": map[string]any{"command": "docs"},
+		},
+	}
+
+	managed := Managed(existing, desired)
+	expected := map[string]any{
+		"model":       "o3",
+		"mcp_servers": map[string]any{"docs": map[string]any{"command": "old"}},
+	}
+	if !reflect.DeepEqual(managed, expected) {
+		t.Errorf("Expected %v, got %v", expected, managed)
+	}
+
+	managed = Managed(existing, desired, "mcp_servers")
+	expected["mcp_servers"] = existing["mcp_servers"]
+	if !reflect.DeepEqual(managed, expected) {
+		t.Errorf("Expected owned table to be kept whole, got %v", managed)
+	}
+}
@@ -0,0 +1,743 @@
+package configio
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// EditTOML rewrites the TOML document original so that it decodes to desired. Only the keys and
+// tables whose values differ are touched: changed values are replaced in place, removed keys and
+// tables are cut out together with the comments directly above them, and new ones are inserted
+// next to their siblings. Comments, blank lines, key order and formatting elsewhere are kept.
+//
+// When original is empty, or cannot be edited in place, the whole document is re-encoded with
+// MarshalTOML instead.
+func EditTOML(original []byte, desired map[string]any) ([]byte, error) {
+	want, err := normalizeTOML(desired)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(original)) == 0 {
+		return MarshalTOML(want)
+	}
+
+	var have map[string]any
+	if err := toml.Unmarshal(original, &have); err != nil {
+		return nil, err
+	}
+	items, err := scanTOML(original)
+	if err != nil {
+		return MarshalTOML(want)
+	}
+
+	e := newTOMLEditor(original, items)
+	if err := e.diffTable(nil, have, want); err != nil {
+		return MarshalTOML(want)
+	}
+	out, err := e.apply()
+	if err != nil {
+		return MarshalTOML(want)
+	}
+
+	// Never trade correctness for formatting
+	var got map[string]any
+	if err := toml.Unmarshal(out, &got); err != nil || !reflect.DeepEqual(got, want) {
+		return MarshalTOML(want)
+	}
+	return out, nil
+}
+
+// normalizeTOML round-trips m through the encoder so that its values have the same types as a
+// decoded document, e.g. []string becomes []any and int becomes int64.
+func normalizeTOML(m map[string]any) (map[string]any, error) {
+	b, err := MarshalTOML(m)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]any{}
+	if err := toml.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type tomlItemKind int
+
+const (
+	tomlBlank tomlItemKind = iota
+	tomlComment
+	tomlHeader
+	tomlEntry
+)
+
+// tomlItem is one logical line of a document. Entries with multi-line values span several
+// physical lines.
+type tomlItem struct {
+	kind tomlItemKind
+	// start and end are the byte range of the item, including its line ending.
+	start, end int
+	indent     string
+	// path is the table path of a header, or the full key path of an entry.
+	path []string
+	// section is the path of the table header an entry appears under.
+	section []string
+	// array marks an array of tables header.
+	array bool
+	// opaque marks items inside an array of tables, whose paths do not map onto tables.
+	opaque bool
+	// valueStart and valueEnd are the byte range of an entry's value, without comments.
+	valueStart, valueEnd int
+}
+
+// scanTOML splits src into items. It only checks as much syntax as it needs to find item
+// boundaries; the document is expected to have been parsed successfully already.
+func scanTOML(src []byte) ([]tomlItem, error) {
+	var items []tomlItem
+	var section []string
+	arrays := map[string]bool{}
+	opaque := false
+
+	for pos := 0; pos < len(src); {
+		i := skipTOMLSpace(src, pos)
+		it := tomlItem{start: pos, indent: string(src[pos:i])}
+
+		switch {
+		case i >= len(src) || src[i] == '\n' || src[i] == '\r':
+			it.kind = tomlBlank
+			it.end = tomlLineEnd(src, i)
+		case src[i] == '#':
+			it.kind = tomlComment
+			it.end = tomlLineEnd(src, i)
+		case src[i] == '[':
+			it.kind = tomlHeader
+			it.array = i+1 < len(src) && src[i+1] == '['
+			i++
+			if it.array {
+				i++
+			}
+			path, j, err := parseTOMLKey(src, i)
+			if err != nil {
+				return nil, err
+			}
+			j = skipTOMLSpace(src, j)
+			closing := "]"
+			if it.array {
+				closing = "]]"
+			}
+			if !bytes.HasPrefix(src[j:], []byte(closing)) {
+				return nil, fmt.Errorf("unterminated table header at byte %d", pos)
+			}
+			it.path = path
+			it.end = tomlLineEnd(src, j)
+
+			section = path
+			if it.array {
+				arrays[tomlPathKey(path)] = true
+			}
+			opaque = false
+			for n := 1; n <= len(path); n++ {
+				if arrays[tomlPathKey(path[:n])] {
+					opaque = true
+				}
+			}
+			it.opaque = opaque
+		default:
+			it.kind = tomlEntry
+			key, j, err := parseTOMLKey(src, i)
+			if err != nil {
+				return nil, err
+			}
+			j = skipTOMLSpace(src, j)
+			if j >= len(src) || src[j] != '=' {
+				return nil, fmt.Errorf("expected '=' at byte %d", j)
+			}
+			it.valueStart = skipTOMLSpace(src, j+1)
+			it.valueEnd, it.end, err = scanTOMLValue(src, it.valueStart)
+			if err != nil {
+				return nil, err
+			}
+			it.section = section
+			it.path = append(append([]string{}, section...), key...)
+			it.opaque = opaque
+		}
+
+		items = append(items, it)
+		pos = it.end
+	}
+	return items, nil
+}
+
+var bareTOMLKey = regexp.MustCompile(`^[A-Za-z0-9_-]+`)
+
+// parseTOMLKey parses a dotted key starting at i and returns its parts and the offset after it.
+func parseTOMLKey(src []byte, i int) ([]string, int, error) {
+	var parts []string
+	for {
+		i = skipTOMLSpace(src, i)
+		if i >= len(src) {
+			return nil, i, fmt.Errorf("unexpected end of document in key")
+		}
+		switch src[i] {
+		case '"', '\'':
+			j, err := skipTOMLString(src, i)
+			if err != nil {
+				return nil, i, err
+			}
+			part := string(src[i+1 : j-1])
+			if src[i] == '"' {
+				if unquoted, err := strconv.Unquote(string(src[i:j])); err == nil {
+					part = unquoted
+				}
+			}
+			parts = append(parts, part)
+			i = j
+		default:
+			bare := bareTOMLKey.Find(src[i:])
+			if bare == nil {
+				return nil, i, fmt.Errorf("invalid key at byte %d", i)
+			}
+			parts = append(parts, string(bare))
+			i += len(bare)
+		}
+		i = skipTOMLSpace(src, i)
+		if i >= len(src) || src[i] != '.' {
+			return parts, i, nil
+		}
+		i++
+	}
+}
+
+// scanTOMLValue scans the value starting at i. It returns the end of the value itself and the
+// end of the item, after any trailing comment and the line ending.
+func scanTOMLValue(src []byte, i int) (valueEnd, end int, err error) {
+	depth := 0
+	valueEnd = i
+	for i < len(src) {
+		switch c := src[i]; {
+		case c == '\n':
+			i++
+			if depth == 0 {
+				return valueEnd, i, nil
+			}
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			if i, err = skipTOMLString(src, i); err != nil {
+				return 0, 0, err
+			}
+			valueEnd = i
+		default:
+			switch c {
+			case '[', '{':
+				depth++
+			case ']', '}':
+				depth--
+			}
+			i++
+			valueEnd = i
+		}
+	}
+	if depth != 0 {
+		return 0, 0, fmt.Errorf("unterminated value")
+	}
+	return valueEnd, len(src), nil
+}
+
+// skipTOMLString returns the offset just after the string starting at i.
+func skipTOMLString(src []byte, i int) (int, error) {
+	quote := src[i]
+	if bytes.HasPrefix(src[i:], []byte{quote, quote, quote}) {
+		delim := []byte{quote, quote, quote}
+		for j := i + 3; j < len(src); j++ {
+			if quote == '"' && src[j] == '\\' {
+				j++
+				continue
+			}
+			if bytes.HasPrefix(src[j:], delim) {
+				// Up to two quotes may directly precede the closing delimiter
+				end := j + 3
+				for n := 0; n < 2 && end < len(src) && src[end] == quote; n++ {
+					end++
+				}
+				return end, nil
+			}
+		}
+		return 0, fmt.Errorf("unterminated multi-line string at byte %d", i)
+	}
+	for j := i + 1; j < len(src); j++ {
+		switch {
+		case quote == '"' && src[j] == '\\':
+			j++
+		case src[j] == quote:
+			return j + 1, nil
+		case src[j] == '\n':
+			return 0, fmt.Errorf("unterminated string at byte %d", i)
+		}
+	}
+	return 0, fmt.Errorf("unterminated string at byte %d", i)
+}
+
+func skipTOMLSpace(src []byte, i int) int {
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+func tomlLineEnd(src []byte, i int) int {
+	if j := bytes.IndexByte(src[i:], '\n'); j >= 0 {
+		return i + j + 1
+	}
+	return len(src)
+}
+
+func tomlPathKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+func hasTOMLPrefix(path, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type tomlEdit struct {
+	start, end int
+	text       string
+	seq        int
+}
+
+// tomlEditor collects edits to a scanned document and applies them in one pass.
+type tomlEditor struct {
+	src   []byte
+	items []tomlItem
+	nl    string
+	edits []tomlEdit
+
+	// rootGap is where new root keys were inserted above the first table, which then needs a
+	// blank line before it.
+	rootGap int
+	// newSections holds entries for tables that have no header or entries to attach to yet.
+	newSections     map[string][]string
+	newSectionOrder [][]string
+}
+
+func newTOMLEditor(src []byte, items []tomlItem) *tomlEditor {
+	nl := "\n"
+	if bytes.Contains(src, []byte("\r\n")) {
+		nl = "\r\n"
+	}
+	return &tomlEditor{src: src, items: items, nl: nl, rootGap: -1, newSections: map[string][]string{}}
+}
+
+func (e *tomlEditor) diffTable(path []string, have, want map[string]any) error {
+	keys := make([]string, 0, len(have)+len(want))
+	for k := range have {
+		keys = append(keys, k)
+	}
+	for k := range want {
+		if _, ok := have[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	// Entries before tables, as in the document, so that new entries are not inserted below a
+	// table added at the same offset.
+	sort.Slice(keys, func(i, j int) bool {
+		ti, tj := isTOMLSection(want[keys[i]]), isTOMLSection(want[keys[j]])
+		if ti != tj {
+			return tj
+		}
+		return keys[i] < keys[j]
+	})
+
+	for _, k := range keys {
+		p := append(append([]string{}, path...), k)
+		oldValue, inOld := have[k]
+		newValue, inNew := want[k]
+		switch {
+		case inOld && inNew && reflect.DeepEqual(oldValue, newValue):
+		case !inNew:
+			e.remove(p)
+		case !inOld:
+			if err := e.add(path, k, newValue); err != nil {
+				return err
+			}
+		default:
+			entry := e.entryAt(p)
+			oldTable, oldIsTable := oldValue.(map[string]any)
+			newTable, newIsTable := newValue.(map[string]any)
+			switch {
+			case oldIsTable && newIsTable && entry == nil:
+				if err := e.diffTable(p, oldTable, newTable); err != nil {
+					return err
+				}
+			case entry != nil:
+				value, err := encodeTOMLValue(newValue)
+				if err != nil {
+					return err
+				}
+				e.edit(entry.valueStart, entry.valueEnd, value)
+			default:
+				e.remove(p)
+				if err := e.add(path, k, newValue); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// entryAt returns the entry that defines path, or nil when it is defined by table headers.
+func (e *tomlEditor) entryAt(path []string) *tomlItem {
+	key := tomlPathKey(path)
+	for i := range e.items {
+		it := &e.items[i]
+		if it.kind == tomlEntry && !it.opaque && tomlPathKey(it.path) == key {
+			return it
+		}
+	}
+	return nil
+}
+
+// remove cuts out every table and entry that defines path or a key below it.
+func (e *tomlEditor) remove(path []string) {
+	for i, it := range e.items {
+		switch {
+		case it.kind == tomlHeader && hasTOMLPrefix(it.path, path):
+			e.edit(e.leadingComments(i), e.sectionEnd(i), "")
+		case it.kind == tomlEntry && hasTOMLPrefix(it.path, path) && !hasTOMLPrefix(it.section, path):
+			e.edit(e.leadingComments(i), it.end, "")
+		}
+	}
+}
+
+// add inserts key with value into the table at path.
+func (e *tomlEditor) add(path []string, key string, value any) error {
+	if isTOMLTable(value) || isTOMLArrayOfTables(value) {
+		return e.addSections(path, key, value)
+	}
+	encoded, err := encodeTOMLValue(value)
+	if err != nil {
+		return err
+	}
+
+	// After the last entry of the table's own section
+	if i := e.lastEntry(func(it tomlItem) bool { return tomlPathKey(it.section) == tomlPathKey(path) }); i >= 0 {
+		it := e.items[i]
+		e.insert(it.end, it.indent+formatTOMLKey(key)+" = "+encoded+e.nl)
+		return nil
+	}
+	// Directly below the table's header
+	for _, it := range e.items {
+		if it.kind == tomlHeader && !it.array && !it.opaque && tomlPathKey(it.path) == tomlPathKey(path) {
+			e.insert(it.end, formatTOMLKey(key)+" = "+encoded+e.nl)
+			return nil
+		}
+	}
+	// After the dotted keys that define the table in a parent section
+	if i := e.lastEntry(func(it tomlItem) bool {
+		return len(it.section) < len(path) && hasTOMLPrefix(path, it.section) && hasTOMLPrefix(it.path, path)
+	}); i >= 0 {
+		it := e.items[i]
+		dotted := append(append([]string{}, path[len(it.section):]...), key)
+		e.insert(it.end, it.indent+formatTOMLKey(dotted...)+" = "+encoded+e.nl)
+		return nil
+	}
+	// Above the first table
+	if len(path) == 0 {
+		at := len(e.src)
+		for i, it := range e.items {
+			if it.kind == tomlHeader {
+				at = e.leadingComments(i)
+				e.rootGap = at
+				break
+			}
+		}
+		e.insert(at, formatTOMLKey(key)+" = "+encoded+e.nl)
+		return nil
+	}
+	// In a new header for the table
+	k := tomlPathKey(path)
+	if _, ok := e.newSections[k]; !ok {
+		e.newSectionOrder = append(e.newSectionOrder, path)
+	}
+	e.newSections[k] = append(e.newSections[k], formatTOMLKey(key)+" = "+encoded+e.nl)
+	return nil
+}
+
+// addSections inserts key as new table headers, after the last table below path.
+func (e *tomlEditor) addSections(path []string, key string, value any) error {
+	var blocks []string
+	if err := e.renderTable(&blocks, append(append([]string{}, path...), key), value); err != nil {
+		return err
+	}
+	e.insertBlocks(e.tablesEnd(path), blocks)
+	return nil
+}
+
+// tablesEnd returns the offset after the last table at or below path, or the end of the
+// document.
+func (e *tomlEditor) tablesEnd(path []string) int {
+	at := len(e.src)
+	if len(path) == 0 {
+		return at
+	}
+	for i, it := range e.items {
+		if it.kind == tomlHeader && hasTOMLPrefix(it.path, path) {
+			at = e.sectionEnd(i)
+		}
+	}
+	return at
+}
+
+// insertBlocks inserts table blocks at offset at, separated from their neighbours by blank lines.
+func (e *tomlEditor) insertBlocks(at int, blocks []string) {
+	if len(blocks) == 0 {
+		return
+	}
+	text := strings.Join(blocks, e.nl)
+	before := string(e.src[:at])
+	if before != "" && !strings.HasSuffix(before, e.nl+e.nl) {
+		text = e.nl + text
+	}
+	if at < len(e.src) {
+		text += e.nl
+	}
+	e.insert(at, text)
+}
+
+// renderTable renders value at path as table headers, one block per header.
+func (e *tomlEditor) renderTable(blocks *[]string, path []string, value any) error {
+	if rows, ok := value.([]any); ok {
+		for _, row := range rows {
+			if err := e.renderTableBlock(blocks, path, row.(map[string]any), true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return e.renderTableBlock(blocks, path, value.(map[string]any), false)
+}
+
+func (e *tomlEditor) renderTableBlock(blocks *[]string, path []string, table map[string]any, array bool) error {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	var tables []string
+	for _, k := range keys {
+		if isTOMLTable(table[k]) || isTOMLArrayOfTables(table[k]) {
+			tables = append(tables, k)
+			continue
+		}
+		encoded, err := encodeTOMLValue(table[k])
+		if err != nil {
+			return err
+		}
+		b.WriteString(formatTOMLKey(k) + " = " + encoded + e.nl)
+	}
+	if array {
+		*blocks = append(*blocks, "[["+formatTOMLKey(path...)+"]]"+e.nl+b.String())
+	} else if b.Len() > 0 || len(tables) == 0 {
+		*blocks = append(*blocks, "["+formatTOMLKey(path...)+"]"+e.nl+b.String())
+	}
+	for _, k := range tables {
+		if err := e.renderTable(blocks, append(append([]string{}, path...), k), table[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastEntry returns the index of the last plain entry matching match, or -1.
+func (e *tomlEditor) lastEntry(match func(tomlItem) bool) int {
+	last := -1
+	for i, it := range e.items {
+		if it.kind == tomlEntry && !it.opaque && match(it) {
+			last = i
+		}
+	}
+	return last
+}
+
+// leadingComments returns the start of the comment lines directly above item i.
+func (e *tomlEditor) leadingComments(i int) int {
+	start := e.items[i].start
+	for j := i - 1; j >= 0 && e.items[j].kind == tomlComment; j-- {
+		start = e.items[j].start
+	}
+	return start
+}
+
+// sectionEnd returns the end of the table whose header is item i: the start of the next
+// header, including the comments directly above it, or the end of the document.
+func (e *tomlEditor) sectionEnd(i int) int {
+	for j := i + 1; j < len(e.items); j++ {
+		if e.items[j].kind == tomlHeader {
+			return e.leadingComments(j)
+		}
+	}
+	return len(e.src)
+}
+
+func (e *tomlEditor) insert(at int, text string) {
+	if at == len(e.src) && len(e.src) > 0 && e.src[len(e.src)-1] != '\n' {
+		text = e.nl + text
+	}
+	e.edit(at, at, text)
+}
+
+func (e *tomlEditor) edit(start, end int, text string) {
+	e.edits = append(e.edits, tomlEdit{start: start, end: end, text: text, seq: len(e.edits)})
+}
+
+func (e *tomlEditor) apply() ([]byte, error) {
+	if e.rootGap >= 0 {
+		e.edit(e.rootGap, e.rootGap, e.nl)
+	}
+	for _, path := range e.newSectionOrder {
+		block := "[" + formatTOMLKey(path...) + "]" + e.nl + strings.Join(e.newSections[tomlPathKey(path)], "")
+		at := len(e.src)
+		for i, it := range e.items {
+			if it.kind == tomlHeader && hasTOMLPrefix(it.path, path) {
+				at = e.leadingComments(i)
+				break
+			}
+		}
+		e.insertBlocks(at, []string{block})
+	}
+
+	// Insertions go before a cut starting at the same offset
+	sort.SliceStable(e.edits, func(i, j int) bool {
+		a, b := e.edits[i], e.edits[j]
+		if a.start != b.start {
+			return a.start < b.start
+		}
+		if (a.end == a.start) != (b.end == b.start) {
+			return a.end == a.start
+		}
+		return a.seq < b.seq
+	})
+
+	var out bytes.Buffer
+	cursor := 0
+	for _, ed := range e.edits {
+		if ed.start < cursor {
+			return nil, fmt.Errorf("overlapping edits at byte %d", ed.start)
+		}
+		out.Write(e.src[cursor:ed.start])
+		out.WriteString(ed.text)
+		cursor = ed.end
+	}
+	out.Write(e.src[cursor:])
+
+	// Tables cut from the end of the document leave behind the blank lines that separated them
+	res := out.Bytes()
+	if !bytes.HasSuffix(e.src, []byte(e.nl+e.nl)) && bytes.HasSuffix(res, []byte(e.nl+e.nl)) {
+		res = append(bytes.TrimRight(res, "\r\n"), e.nl...)
+	}
+	return res, nil
+}
+
+// isTOMLSection reports whether v is written as table headers rather than as an entry.
+func isTOMLSection(v any) bool {
+	return isTOMLTable(v) || isTOMLArrayOfTables(v)
+}
+
+func isTOMLTable(v any) bool {
+	_, ok := v.(map[string]any)
+	return ok
+}
+
+func isTOMLArrayOfTables(v any) bool {
+	rows, ok := v.([]any)
+	if !ok || len(rows) == 0 {
+		return false
+	}
+	for _, row := range rows {
+		if !isTOMLTable(row) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatTOMLKey renders a dotted key, quoting the parts that are not bare keys.
+func formatTOMLKey(parts ...string) string {
+	out := make([]string, len(parts))
+	for i, part := range parts {
+		if m := bareTOMLKey.FindString(part); m != "" && m == part {
+			out[i] = part
+			continue
+		}
+		b, err := toml.Marshal(map[string]any{part: 0})
+		if err != nil {
+			out[i] = strconv.Quote(part)
+			continue
+		}
+		out[i] = strings.TrimSuffix(strings.TrimSpace(string(b)), " = 0")
+	}
+	return strings.Join(out, ".")
+}
+
+// encodeTOMLValue renders a value for the right-hand side of an entry. Tables are written as
+// inline tables.
+func encodeTOMLValue(v any) (string, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		if len(t) == 0 {
+			return "{}", nil
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			encoded, err := encodeTOMLValue(t[k])
+			if err != nil {
+				return "", err
+			}
+			parts[i] = formatTOMLKey(k) + " = " + encoded
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	case []any:
+		parts := make([]string, len(t))
+		for i, item := range t {
+			encoded, err := encodeTOMLValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = encoded
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	default:
+		b, err := toml.Marshal(map[string]any{"v": t})
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimPrefix(strings.TrimSpace(string(b)), "v = "), nil
+	}
+}
@@ -0,0 +1,270 @@
+package configio
+
+import (
+	"reflect"
+	"testing"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+func TestEditTOML(t *testing.T) {
+	testCases := []struct {
+		name     string
+		original string
+		desired  map[string]any
+		expected string
+	}{
+		{
+			name:     "empty document is encoded",
+			original: "",
+			desired:  map[string]any{"model": "o3"},
+			expected: "model = 'o3'\n",
+		},
+		{
+			name: "unchanged document is kept byte for byte",
+			original: `# My Codex config
+model   = "o3"    # fast
+
+[history]
+persistence = "none"
+`,
+			desired: map[string]any{
+				"model":   "o3",
+				"history": map[string]any{"persistence": "none"},
+			},
+			expected: `# My Codex config
+model   = "o3"    # fast
+
+[history]
+persistence = "none"
+`,
+		},
+		{
+			name: "values are replaced in place",
+			original: `# My Codex config
+model = "o3" # fast
+notify = [
+  "notify-send", # desktop
+  "Codex",
+]
+`,
+			desired: map[string]any{
+				"model":  "gpt-5",
+				"notify": []string{"say"},
+			},
+			expected: `# My Codex config
+model = 'gpt-5' # fast
+notify = ['say']
+`,
+		},
+		{
+			name: "keys are added next to their siblings",
+			original: `# Preferences
+model = "o3"
+
+# Tables
+[history]
+persistence = "none"
+
+[tui]
+theme = "dark"
+`,
+			desired: map[string]any{
+				"model":           "o3",
+				"approval_policy": "never",
+				"history":         map[string]any{"persistence": "none", "max_bytes": 1024},
+				"tui":             map[string]any{"theme": "dark"},
+			},
+			expected: `# Preferences
+model = "o3"
+approval_policy = 'never'
+
+# Tables
+[history]
+persistence = "none"
+max_bytes = 1024
+
+[tui]
+theme = "dark"
+`,
+		},
+		{
+			name: "root keys go above the first table",
+			original: `[history]
+persistence = "none"
+`,
+			desired: map[string]any{
+				"model":   "o3",
+				"history": map[string]any{"persistence": "none"},
+			},
+			expected: `model = 'o3'
+
+[history]
+persistence = "none"
+`,
+		},
+		{
+			name: "keys and tables are removed with their comments",
+			original: `model = "o3"
+# Old sandbox
+sandbox_mode = "read-only"
+
+[mcp_servers.docs]
+command = "docs"
+
+# Stale server
+[mcp_servers.stale]
+command = "stale"
+env = { TOKEN = "x" }
+
+[mcp_servers.stale.extra]
+flag = true
+
+[tui]
+theme = "dark"
+`,
+			desired: map[string]any{
+				"model":       "o3",
+				"mcp_servers": map[string]any{"docs": map[string]any{"command": "docs"}},
+				"tui":         map[string]any{"theme": "dark"},
+			},
+			expected: `model = "o3"
+
+[mcp_servers.docs]
+command = "docs"
+
+[tui]
+theme = "dark"
+`,
+		},
+		{
+			name: "new tables go after their siblings",
+			original: `model = "o3"
+
+[mcp_servers.docs]
+command = "docs" # keep
+
+[tui]
+theme = "dark"
+`,
+			desired: map[string]any{
+				"model": "o3",
+				"mcp_servers": map[string]any{
+					"docs":   map[string]any{"command": "docs"},
+					"memory": map[string]any{"command": "npx", "args": []string{"-y", "memory"}, "env": map[string]any{"DEBUG": "1"}},
+				},
+				"tui":     map[string]any{"theme": "dark"},
+				"history": map[string]any{"persistence": "none"},
+			},
+			expected: `model = "o3"
+
+[mcp_servers.docs]
+command = "docs" # keep
+
+[mcp_servers.memory]
+args = ['-y', 'memory']
+command = 'npx'
+
+[mcp_servers.memory.env]
+DEBUG = '1'
+
+[tui]
+theme = "dark"
+
+[history]
+persistence = 'none'
+`,
+		},
+		{
+			name: "dotted keys and inline tables are edited where they are",
+			original: `history.persistence = "none"
+tui = { theme = "dark" }
+`,
+			desired: map[string]any{
+				"history": map[string]any{"persistence": "save-all", "max_bytes": 10},
+				"tui":     map[string]any{"theme": "light"},
+			},
+			expected: `history.persistence = 'save-all'
+history.max_bytes = 10
+tui = { theme = 'light' }
+`,
+		},
+		{
+			name: "arrays of tables are left alone unless changed",
+			original: `[[rules]]
+name = "a"
+
+[[rules]]
+name = "b"
+
+[tui]
+theme = "dark"
+`,
+			desired: map[string]any{
+				"rules": []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}},
+				"tui":   map[string]any{"theme": "light"},
+			},
+			expected: `[[rules]]
+name = "a"
+
+[[rules]]
+name = "b"
+
+[tui]
+theme = 'light'
+`,
+		},
+		{
+			name:     "missing trailing newline",
+			original: `model = "o3"`,
+			desired:  map[string]any{"model": "o3", "sandbox_mode": "read-only"},
+			expected: "model = \"o3\"\nsandbox_mode = 'read-only'\n",
+		},
+		{
+			name:     "new root keys go above new tables",
+			original: "# Team defaults\nmodel = \"o3\"\n",
+			desired: map[string]any{
+				"model":        "o3",
+				"notify":       []string{"/bin/notify"},
+				"__agentsmith": map[string]any{"managed": true},
+			},
+			expected: "# Team defaults\nmodel = \"o3\"\nnotify = ['/bin/notify']\n\n[__agentsmith]\nmanaged = true\n",
+		},
+		{
+			name:     "tables removed from the end leave no trailing blank lines",
+			original: "model = \"o3\"\n\n[mcp_servers.local]\ncommand = \"local-server\"\n\n[__agentsmith]\nmanaged = true\n\n[mcp_servers.docs]\ncommand = 'docs-server'\n",
+			desired: map[string]any{
+				"model":       "o3",
+				"mcp_servers": map[string]any{"local": map[string]any{"command": "local-server"}},
+			},
+			expected: "model = \"o3\"\n\n[mcp_servers.local]\ncommand = \"local-server\"\n",
+		},
+		{
+			name:     "multi-line strings are skipped over",
+			original: "instructions = \"\"\"\n[not_a_table]\nkey = 1\n\"\"\"\nmodel = \"o3\"\n",
+			desired:  map[string]any{"instructions": "[not_a_table]\nkey = 1\n", "model": "gpt-5"},
+			expected: "instructions = \"\"\"\n[not_a_table]\nkey = 1\n\"\"\"\nmodel = 'gpt-5'\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := EditTOML([]byte(tc.original), tc.desired)
+			if err != nil {
+				t.Fatalf("EditTOML returned error: %v", err)
+			}
+			if string(out) != tc.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tc.expected, out)
+			}
+
+			var got map[string]any
+			if err := toml.Unmarshal(out, &got); err != nil {
+				t.Fatalf("Edited document does not parse: %v", err)
+			}
+			want, _ := normalizeTOML(tc.desired)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected document to decode to %v, got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package configio
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// BuiltinModelProviders are the model providers Codex defines without a model_providers entry.
+var BuiltinModelProviders = []string{"openai", "oss"}
+
+// codexEnums are the documented values of the enumerated keys. Keys are relative to the root
+// or to a profile; model provider keys are relative to the provider entry.
+var (
+	codexEnums = map[string][]string{
+		"approval_policy":                  {"untrusted", "on-failure", "on-request", "never"},
+		"sandbox_mode":                     {"read-only", "workspace-write", "danger-full-access"},
+		"model_reasoning_effort":           {"minimal", "low", "medium", "high"},
+		"model_reasoning_summary":          {"auto", "concise", "detailed", "none"},
+		"model_verbosity":                  {"low", "medium", "high"},
+		"file_opener":                      {"vscode", "vscode-insiders", "windsurf", "cursor", "none"},
+		"history.persistence":              {"save-all", "none"},
+		"shell_environment_policy.inherit": {"all", "core", "none"},
+	}
+	modelProviderEnums = map[string][]string{
+		"wire_api": {"chat", "responses"},
+	}
+)
+
+// Issue is a semantic problem in a Codex config, at the key path Path. Reference is set for
+// references to a profile or model provider that is not defined, which another writer of the
+// same file may still add.
+type Issue struct {
+	Path      []string
+	Message   string
+	Reference bool
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", formatTOMLKey(i.Path...), i.Message)
+}
+
+// Validate checks a Codex config for mistakes that still decode: enum values outside the
+// documented sets, a profile or model provider that is referenced but not defined, and custom
+// model providers whose env_key is empty. Issues are sorted by key path.
+func Validate(m map[string]any) []Issue {
+	var issues []Issue
+	add := func(path []string, format string, args ...any) {
+		issues = append(issues, Issue{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	addReference := func(path []string, format string, args ...any) {
+		issues = append(issues, Issue{Path: path, Message: fmt.Sprintf(format, args...), Reference: true})
+	}
+
+	providers, _ := asTOMLTable(m["model_providers"])
+	profiles, _ := asTOMLTable(m["profiles"])
+
+	checkSettings := func(prefix []string, t map[string]any) {
+		for key, allowed := range codexEnums {
+			parts := strings.Split(key, ".")
+			if v, ok := LookupKeyPath(t, parts); ok && !slices.Contains(allowed, fmt.Sprint(v)) {
+				add(append(slices.Clone(prefix), parts...), "%q is not a valid value. Must be one of: %s.", fmt.Sprint(v), strings.Join(allowed, ", "))
+			}
+		}
+		if v, ok := t["model_provider"]; ok {
+			id := fmt.Sprint(v)
+			if _, defined := providers[id]; !defined && !slices.Contains(BuiltinModelProviders, id) {
+				addReference(append(slices.Clone(prefix), "model_provider"), "Model provider %q is not defined in model_providers and is not one of the built-in providers (%s).", id, strings.Join(BuiltinModelProviders, ", "))
+			}
+		}
+	}
+
+	checkSettings(nil, m)
+	if v, ok := m["profile"]; ok {
+		if _, defined := profiles[fmt.Sprint(v)]; !defined {
+			addReference([]string{"profile"}, "Profile %q is not defined in profiles.", fmt.Sprint(v))
+		}
+	}
+	for name, v := range profiles {
+		if profile, ok := asTOMLTable(v); ok {
+			checkSettings([]string{"profiles", name}, profile)
+		}
+	}
+	for id, v := range providers {
+		provider, ok := asTOMLTable(v)
+		if !ok {
+			continue
+		}
+		for key, allowed := range modelProviderEnums {
+			if v, ok := provider[key]; ok && !slices.Contains(allowed, fmt.Sprint(v)) {
+				add([]string{"model_providers", id, key}, "%q is not a valid value. Must be one of: %s.", fmt.Sprint(v), strings.Join(allowed, ", "))
+			}
+		}
+		if v, ok := provider["env_key"]; ok && fmt.Sprint(v) == "" && !slices.Contains(BuiltinModelProviders, id) {
+			add([]string{"model_providers", id, "env_key"}, "env_key is empty. Name the environment variable holding the API key, or leave env_key unset for providers that need none.")
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		return slices.Compare(issues[i].Path, issues[j].Path) < 0
+	})
+	return issues
+}
+
+// LookupKeyPath returns the value at the key path key in m, if any.
+func LookupKeyPath(m map[string]any, key []string) (any, bool) {
+	var v any = m
+	for _, part := range key {
+		t, ok := asTOMLTable(v)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = t[part]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
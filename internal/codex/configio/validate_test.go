@@ -0,0 +1,82 @@
+package configio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   map[string]any
+		expected []string
+	}{
+		{
+			name: "valid config",
+			config: map[string]any{
+				"profile":         "deep",
+				"model_provider":  "openai",
+				"approval_policy": "on-request",
+				"history":         map[string]any{"persistence": "none"},
+				"model_providers": map[string]any{
+					"ollama": map[string]any{"base_url": "http://localhost:11434/v1", "wire_api": "chat"},
+				},
+				"profiles": map[string]any{
+					"deep": map[string]any{"model_provider": "ollama", "model_reasoning_effort": "high"},
+				},
+			},
+		},
+		{
+			name: "undefined references",
+			config: map[string]any{
+				"profile":        "missing",
+				"model_provider": "azure",
+				"profiles": map[string]any{
+					"deep": map[string]any{"model_provider": "ollama"},
+				},
+			},
+			expected: []string{"model_provider", "profile", "profiles.deep.model_provider"},
+		},
+		{
+			name: "enum values",
+			config: map[string]any{
+				"approval_policy":          "auto",
+				"sandbox_mode":             "read-only",
+				"shell_environment_policy": map[string]any{"inherit": "parent"},
+				"model_providers": map[string]any{
+					"azure": map[string]any{"env_key": "AZURE_KEY", "wire_api": "grpc"},
+				},
+				"profiles": map[string]any{
+					"fast": map[string]any{"model_verbosity": "quiet"},
+				},
+			},
+			expected: []string{"approval_policy", "model_providers.azure.wire_api", "profiles.fast.model_verbosity", "shell_environment_policy.inherit"},
+		},
+		{
+			name: "empty env_key",
+			config: map[string]any{
+				"model_providers": map[string]any{
+					"azure":  map[string]any{"env_key": ""},
+					"openai": map[string]any{"env_key": ""},
+					"local":  map[string]any{"base_url": "http://localhost:8080/v1"},
+				},
+			},
+			expected: []string{"model_providers.azure.env_key"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range Validate(tc.config) {
+				got = append(got, formatTOMLKey(issue.Path...))
+				if isReference := issue.Path[len(issue.Path)-1] == "profile" || issue.Path[len(issue.Path)-1] == "model_provider"; issue.Reference != isReference {
+					t.Errorf("Expected Reference to be %t for %s", isReference, issue)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected issues at %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
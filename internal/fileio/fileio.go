@@ -0,0 +1,120 @@
+// Package fileio provides the write path shared by every file-backed resource.
+package fileio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WriteOptions controls how AtomicWrite writes a file.
+type WriteOptions struct {
+	// Mode is applied to the file before it replaces the previous version.
+	Mode os.FileMode
+	// KeepMode applies the permissions of an existing file instead of Mode, so that Mode only
+	// sets the permissions of new files.
+	KeepMode bool
+	// Backup copies an existing file to <path>.bak before it is replaced.
+	Backup bool
+	// CreateDirs creates missing parent directories.
+	CreateDirs bool
+}
+
+// AtomicWrite writes data to a temp file in the target directory, syncs it and renames it over
+// path, so readers only ever observe the previous or the new content. When path is a symlink,
+// the file it points to is replaced and the link is kept.
+func AtomicWrite(path string, data []byte, opts WriteOptions) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	mode := opts.Mode.Perm()
+	if opts.KeepMode {
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	dir := filepath.Dir(path)
+	if opts.CreateDirs {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("mkdir: %w", err)
+		}
+	}
+
+	if opts.Backup {
+		if _, err := os.Stat(path); err == nil {
+			if err := copyFile(path, path+".bak"); err != nil {
+				return fmt.Errorf("backup write: %w", err)
+			}
+		}
+	}
+
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	// Remove the temp file on any failure before the rename
+	defer os.Remove(tmp)
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Chmod(mode); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	// Persist the rename itself; not every platform supports syncing a directory
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+var locks sync.Map
+
+// Lock serializes read-modify-write cycles on a file that several resources share, such as a
+// settings.json edited by both a settings resource and rule resources. It returns the unlock func.
+func Lock(path string) func() {
+	m, _ := locks.LoadOrStore(filepath.Clean(path), &sync.Mutex{})
+	mu := m.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// ParseMode parses an octal permission string such as "0600" or "644".
+func ParseMode(mode string) (os.FileMode, error) {
+	s := strings.TrimSpace(mode)
+	v, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || v > 0o777 {
+		return 0, fmt.Errorf("invalid file_mode: %s", mode)
+	}
+	return os.FileMode(v), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, in, 0o600)
+}
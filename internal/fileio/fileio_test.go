@@ -0,0 +1,174 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestAtomicWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "settings.json")
+
+	// Missing directories are only created when requested
+	if err := AtomicWrite(path, []byte("{}"), WriteOptions{Mode: 0o644}); err == nil {
+		t.Fatal("Expected error when parent directory is missing")
+	}
+
+	if err := AtomicWrite(path, []byte(`{"a":1}`), WriteOptions{Mode: 0o644, CreateDirs: true}); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Error("Expected no backup for a new file")
+	}
+
+	if err := AtomicWrite(path, []byte(`{"a":2}`), WriteOptions{Mode: 0o600, Backup: true}); err != nil {
+		t.Fatalf("Failed to overwrite file: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != `{"a":2}` {
+		t.Errorf("Expected new content, got %q (%v)", data, err)
+	}
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || string(backup) != `{"a":1}` {
+		t.Errorf("Expected backup of previous content, got %q (%v)", backup, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected mode 0600 on existing file, got %o", info.Mode().Perm())
+	}
+
+	// No temp files are left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected only the file and its backup, got %d entries", len(entries))
+	}
+}
+
+func TestAtomicWrite_symlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "settings.json")
+	link := filepath.Join(dir, "settings.json")
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(target, []byte(`{"a":1}`), 0o644); err != nil {
+		t.Fatalf("Failed to write target: %v", err)
+	}
+	if err := os.Symlink(filepath.Join("dotfiles", "settings.json"), link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	if err := AtomicWrite(link, []byte(`{"a":2}`), WriteOptions{Mode: 0o644, Backup: true}); err != nil {
+		t.Fatalf("Failed to write through symlink: %v", err)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("Expected the symlink to be kept, got %v (%v)", info, err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil || string(data) != `{"a":2}` {
+		t.Errorf("Expected the target to be written, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(target + ".bak"); err != nil {
+		t.Errorf("Expected the backup next to the target: %v", err)
+	}
+}
+
+func TestAtomicWrite_keepMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+
+	// New files get Mode
+	if err := AtomicWrite(path, []byte("a = 1\n"), WriteOptions{Mode: 0o600, KeepMode: true}); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("Expected mode 0600 on a new file, got %v (%v)", info, err)
+	}
+
+	// Existing files keep their permissions
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatalf("Failed to chmod file: %v", err)
+	}
+	if err := AtomicWrite(path, []byte("a = 2\n"), WriteOptions{Mode: 0o600, KeepMode: true}); err != nil {
+		t.Fatalf("Failed to overwrite file: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o640 {
+		t.Errorf("Expected mode 0640 to be kept, got %v (%v)", info, err)
+	}
+
+	// Without KeepMode, Mode is applied
+	if err := AtomicWrite(path, []byte("a = 3\n"), WriteOptions{Mode: 0o600}); err != nil {
+		t.Fatalf("Failed to overwrite file: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected mode 0600, got %v (%v)", info, err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	testCases := []struct {
+		mode        string
+		expected    os.FileMode
+		expectError bool
+	}{
+		{mode: "0600", expected: 0o600},
+		{mode: "644", expected: 0o644},
+		{mode: "0755", expected: 0o755},
+		{mode: "0800", expectError: true},
+		{mode: "01777", expectError: true},
+		{mode: "rw-r--r--", expectError: true},
+		{mode: "", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.mode, func(t *testing.T) {
+			mode, err := ParseMode(tc.mode)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q", tc.mode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if mode != tc.expected {
+				t.Errorf("Expected %o, got %o", tc.expected, mode)
+			}
+		})
+	}
+}
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+
+	var wg sync.WaitGroup
+	counter := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Equivalent paths share a lock
+			unlock := Lock(path + "/../" + filepath.Base(path))
+			defer unlock()
+			v := counter
+			counter = v + 1
+		}()
+	}
+	wg.Wait()
+
+	if counter != 50 {
+		t.Errorf("Expected 50 serialized increments, got %d", counter)
+	}
+}
@@ -0,0 +1,117 @@
+// Package frontmatter reads and writes Markdown documents with YAML frontmatter, the format of
+// Claude Code subagents and slash commands.
+package frontmatter
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+const delimiter = "---"
+
+// Document is a Markdown document split into its frontmatter and body.
+type Document struct {
+	// Frontmatter is the YAML between the delimiter lines. It is nil when the document has none.
+	Frontmatter []byte
+	// Body is everything after the closing delimiter line, byte for byte.
+	Body []byte
+}
+
+// HasFrontmatter reports whether the document starts with a frontmatter block.
+func (d Document) HasFrontmatter() bool {
+	return d.Frontmatter != nil
+}
+
+// Parse splits content into its frontmatter and body. Delimiters are only recognized as whole
+// `---` lines, the opening one on the first line, so horizontal rules in the body and `---`
+// inside YAML values are left alone. A document without an opening delimiter is all body.
+func Parse(content []byte) (Document, error) {
+	first, rest, _ := cutLine(content)
+	if !isDelimiter(first) {
+		return Document{Body: content}, nil
+	}
+
+	front := rest
+	offset := 0
+	for {
+		line, next, ok := cutLine(front[offset:])
+		if isDelimiter(line) {
+			return Document{
+				Frontmatter: append([]byte{}, front[:offset]...),
+				Body:        next,
+			}, nil
+		}
+		if !ok {
+			return Document{}, fmt.Errorf("frontmatter is not terminated by a %q line", delimiter)
+		}
+		offset = len(front) - len(next)
+	}
+}
+
+// Decode unmarshals the frontmatter into v. A document without frontmatter leaves v unchanged.
+func (d Document) Decode(v interface{}) error {
+	if err := yaml.Unmarshal(d.Frontmatter, v); err != nil {
+		return fmt.Errorf("failed to parse YAML frontmatter: %w", err)
+	}
+	return nil
+}
+
+// Extra returns the frontmatter keys that are not in known, so callers can keep keys they do
+// not model. It returns nil when there are none.
+func (d Document) Extra(known ...string) (map[string]interface{}, error) {
+	var all map[string]interface{}
+	if err := d.Decode(&all); err != nil {
+		return nil, err
+	}
+	for _, key := range known {
+		delete(all, key)
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	return all, nil
+}
+
+// Marshal renders a document whose frontmatter holds the keys of each value in turn, typically
+// a struct of known keys followed by a map of extra keys. The frontmatter block is omitted when
+// the values have no keys. body is written verbatim.
+func Marshal(body []byte, values ...interface{}) ([]byte, error) {
+	var front bytes.Buffer
+	for _, v := range values {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal YAML frontmatter: %w", err)
+		}
+		if bytes.Equal(data, []byte("{}\n")) {
+			continue
+		}
+		front.Write(data)
+	}
+	if front.Len() == 0 {
+		return body, nil
+	}
+
+	out := make([]byte, 0, front.Len()+len(body)+8)
+	out = append(out, delimiter+"\n"...)
+	out = append(out, front.Bytes()...)
+	out = append(out, delimiter+"\n"...)
+	return append(out, body...), nil
+}
+
+// cutLine returns the first line of b without its line ending, the remainder after the line
+// ending, and whether a line ending was found.
+func cutLine(b []byte) (line, rest []byte, ok bool) {
+	i := bytes.IndexByte(b, '\n')
+	if i < 0 {
+		return b, nil, false
+	}
+	return b[:i], b[i+1:], true
+}
+
+// isDelimiter reports whether line is a `---` delimiter line, allowing a CRLF line ending and
+// trailing whitespace.
+func isDelimiter(line []byte) bool {
+	return string(bytes.TrimRight(line, " \t\r")) == delimiter
+}
@@ -0,0 +1,203 @@
+package frontmatter
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		frontmatter string
+		body        string
+		noFront     bool
+		expectError bool
+	}{
+		{
+			name:        "basic",
+			content:     "---\nname: helper\n---\nPrompt.\n",
+			frontmatter: "name: helper\n",
+			body:        "Prompt.\n",
+		},
+		{
+			name:        "horizontal_rule_in_body",
+			content:     "---\nname: helper\n---\nIntro\n\n---\n\nMore\n",
+			frontmatter: "name: helper\n",
+			body:        "Intro\n\n---\n\nMore\n",
+		},
+		{
+			name:        "dashes_inside_value",
+			content:     "---\ndescription: before --- after\nnote: |\n  a\n  ---x\n---\nBody",
+			frontmatter: "description: before --- after\nnote: |\n  a\n  ---x\n",
+			body:        "Body",
+		},
+		{
+			name:        "body_whitespace_kept",
+			content:     "---\nname: x\n---\n\n  indented\n\n\n",
+			frontmatter: "name: x\n",
+			body:        "\n  indented\n\n\n",
+		},
+		{
+			name:        "crlf_line_endings",
+			content:     "---\r\nname: x\r\n---\r\nBody\r\n",
+			frontmatter: "name: x\r\n",
+			body:        "Body\r\n",
+		},
+		{
+			name:        "empty_frontmatter",
+			content:     "---\n---\nBody",
+			frontmatter: "",
+			body:        "Body",
+		},
+		{
+			name:        "closing_delimiter_at_eof",
+			content:     "---\nname: x\n---",
+			frontmatter: "name: x\n",
+			body:        "",
+		},
+		{
+			name:    "no_frontmatter",
+			content: "Just a prompt.\n---\nwith a rule\n",
+			body:    "Just a prompt.\n---\nwith a rule\n",
+			noFront: true,
+		},
+		{
+			name:    "dashes_not_on_first_line",
+			content: "\n---\nname: x\n---\n",
+			body:    "\n---\nname: x\n---\n",
+			noFront: true,
+		},
+		{
+			name:    "longer_rule_is_not_a_delimiter",
+			content: "-----\nBody",
+			body:    "-----\nBody",
+			noFront: true,
+		},
+		{
+			name:    "empty_document",
+			content: "",
+			body:    "",
+			noFront: true,
+		},
+		{
+			name:        "unterminated",
+			content:     "---\nname: x\nBody\n",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := Parse([]byte(tc.content))
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if doc.HasFrontmatter() == tc.noFront {
+				t.Errorf("Expected HasFrontmatter %v", !tc.noFront)
+			}
+			if string(doc.Frontmatter) != tc.frontmatter {
+				t.Errorf("Expected frontmatter %q, got %q", tc.frontmatter, doc.Frontmatter)
+			}
+			if string(doc.Body) != tc.body {
+				t.Errorf("Expected body %q, got %q", tc.body, doc.Body)
+			}
+		})
+	}
+}
+
+func TestDocument_DecodeAndExtra(t *testing.T) {
+	doc, err := Parse([]byte("---\nname: reviewer\ntools: Read, Grep\npermissionMode: plan\nmaxTurns: 5\n---\nBody"))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	var known struct {
+		Name  string `yaml:"name"`
+		Tools string `yaml:"tools"`
+	}
+	if err := doc.Decode(&known); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if known.Name != "reviewer" || known.Tools != "Read, Grep" {
+		t.Errorf("Unexpected known keys: %+v", known)
+	}
+
+	extra, err := doc.Extra("name", "tools")
+	if err != nil {
+		t.Fatalf("Failed to read extra keys: %v", err)
+	}
+	if len(extra) != 2 || extra["permissionMode"] != "plan" || extra["maxTurns"] != 5 {
+		t.Errorf("Unexpected extra keys: %v", extra)
+	}
+
+	if extra, err := doc.Extra("name", "tools", "permissionMode", "maxTurns"); err != nil || extra != nil {
+		t.Errorf("Expected no extra keys, got %v (%v)", extra, err)
+	}
+
+	// Invalid YAML is reported
+	doc, _ = Parse([]byte("---\nname: a: b\n---\n"))
+	if err := doc.Decode(&known); err == nil {
+		t.Error("Expected error for invalid YAML")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	type known struct {
+		Name  string `yaml:"name,omitempty"`
+		Model string `yaml:"model,omitempty"`
+	}
+
+	testCases := []struct {
+		name     string
+		body     string
+		values   []interface{}
+		expected string
+	}{
+		{
+			name:     "known_and_extra_keys",
+			body:     "Prompt.\n",
+			values:   []interface{}{known{Name: "reviewer"}, map[string]string{"b": "2", "a": "1"}},
+			expected: "---\nname: reviewer\na: \"1\"\nb: \"2\"\n---\nPrompt.\n",
+		},
+		{
+			name:     "no_keys",
+			body:     "Just a body",
+			values:   []interface{}{known{}, map[string]string{}},
+			expected: "Just a body",
+		},
+		{
+			name:     "body_with_rule",
+			body:     "a\n---\nb",
+			values:   []interface{}{known{Model: "opus"}},
+			expected: "---\nmodel: opus\n---\na\n---\nb",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Marshal([]byte(tc.body), tc.values...)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+			if string(out) != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, out)
+			}
+
+			// Rendered documents parse back to the same body
+			doc, err := Parse(out)
+			if err != nil {
+				t.Fatalf("Failed to parse rendered document: %v", err)
+			}
+			if string(doc.Body) != tc.body {
+				t.Errorf("Expected body %q to round-trip, got %q", tc.body, doc.Body)
+			}
+		})
+	}
+}
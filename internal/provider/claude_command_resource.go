@@ -5,21 +5,34 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-agentsmith/internal/frontmatter"
 )
 
 var (
-	_ resource.Resource                = &claudeCommandResource{}
-	_ resource.ResourceWithConfigure   = &claudeCommandResource{}
-	_ resource.ResourceWithImportState = &claudeCommandResource{}
+	_ resource.Resource                   = &claudeCommandResource{}
+	_ resource.ResourceWithConfigure      = &claudeCommandResource{}
+	_ resource.ResourceWithImportState    = &claudeCommandResource{}
+	_ resource.ResourceWithValidateConfig = &claudeCommandResource{}
+)
+
+var (
+	claudeCommandNamePattern      = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+	claudeCommandNamespacePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+(/[A-Za-z0-9_-]+)*$`)
+	claudeCommandPlaceholder      = regexp.MustCompile(`\$(ARGUMENTS|[0-9]+)\b`)
+	claudeCommandMistypedArgs     = regexp.MustCompile(`\$\{?(ARGUMENT|ARGS|ARGV|[Aa]rguments)\}?\b`)
 )
 
 func NewClaudeCommandResource() resource.Resource {
@@ -31,11 +44,25 @@ type claudeCommandResource struct {
 }
 
 type claudeCommandResourceModel struct {
-	ID         types.String `tfsdk:"id"`
-	Scope      types.String `tfsdk:"scope"`
-	Name       types.String `tfsdk:"name"`
-	Content    types.String `tfsdk:"content"`
-	Executable types.Bool   `tfsdk:"executable"`
+	ID           types.String `tfsdk:"id"`
+	Scope        types.String `tfsdk:"scope"`
+	Namespace    types.String `tfsdk:"namespace"`
+	Name         types.String `tfsdk:"name"`
+	Description  types.String `tfsdk:"description"`
+	ArgumentHint types.String `tfsdk:"argument_hint"`
+	AllowedTools types.List   `tfsdk:"allowed_tools"`
+	Model        types.String `tfsdk:"model"`
+	Body         types.String `tfsdk:"body"`
+	Content      types.String `tfsdk:"content"`
+	Executable   types.Bool   `tfsdk:"executable"`
+	fileWriteOptionsModel
+}
+
+type commandFrontmatter struct {
+	Description  string          `yaml:"description,omitempty"`
+	ArgumentHint string          `yaml:"argument-hint,omitempty"`
+	AllowedTools frontmatterList `yaml:"allowed-tools,omitempty"`
+	Model        string          `yaml:"model,omitempty"`
 }
 
 func (r *claudeCommandResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -44,10 +71,10 @@ func (r *claudeCommandResource) Metadata(_ context.Context, req resource.Metadat
 
 func (r *claudeCommandResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages a Claude Code custom slash command. These commands are scripts that can be invoked within a Claude session using `/name`.",
+		Description: "Manages a Claude Code custom slash command. Commands are Markdown files with optional YAML frontmatter, invoked within a Claude session as `/name`, or `/namespace:name` when they live in a subdirectory.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				Description: "A unique identifier for this command resource, composed of the scope and name.",
+				Description: "A unique identifier for this command resource, composed of the scope, namespace and name.",
 				Computed:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
@@ -56,29 +83,104 @@ func (r *claudeCommandResource) Schema(_ context.Context, _ resource.SchemaReque
 			"scope": schema.StringAttribute{
 				Description: "The scope of the command file. Must be either `user` (for `~/.claude/commands`) or `project` (for `<workdir>/.claude/commands`).",
 				Required:    true,
+				Validators:  []validator.String{stringOneOf("user", "project")},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Description: "An optional subdirectory of the commands directory, e.g. `frontend` or `frontend/react`. A command `component` in namespace `frontend` is written to `commands/frontend/component.md` and invoked as `/frontend:component`.",
+				Optional:    true,
+				Validators:  []validator.String{claudeCommandNamespace()},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"name": schema.StringAttribute{
-				Description: "The name of the command, which will also be its filename. This is the name you will use to invoke the command in Claude (e.g., `/my-command`).",
+				Description: "The name of the command, used to invoke it in Claude (e.g., `/my-command`). The file is named `<name>.md`; a trailing `.md` in the name is ignored.",
 				Required:    true,
+				Validators:  []validator.String{claudeCommandName()},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"description": schema.StringAttribute{
+				Description: "A brief description of the command, shown in the `/help` list. Written to the `description` frontmatter key.",
+				Optional:    true,
+			},
+			"argument_hint": schema.StringAttribute{
+				Description: "The arguments the command expects, shown when autocompleting it, e.g. `[pr-number] [priority]`. Written to the `argument-hint` frontmatter key.",
+				Optional:    true,
+			},
+			"allowed_tools": schema.ListAttribute{
+				Description: "Permission rules for the tools the command may use, e.g. `Bash(git status:*)`. Written to the `allowed-tools` frontmatter key.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators:  []validator.List{claudePermissionRules()},
+			},
+			"model": schema.StringAttribute{
+				Description: "The model to run the command with. Written to the `model` frontmatter key.",
+				Optional:    true,
+			},
+			"body": schema.StringAttribute{
+				Description: "The Markdown prompt of the command, written after the frontmatter. Use `$ARGUMENTS` for all arguments or `$1`, `$2`, ... for positional ones. Exactly one of `body` and `content` must be set.",
+				Optional:    true,
+			},
 			"content": schema.StringAttribute{
-				Description: "The script content of the command file. This can be any executable script content, such as a shell script.",
-				Required:    true,
+				Description:        "The raw content of the command file, written verbatim. Cannot be combined with `body` or the frontmatter attributes.",
+				Optional:           true,
+				DeprecationMessage: "Use body together with description, argument_hint, allowed_tools and model instead.",
 			},
 			"executable": schema.BoolAttribute{
-				Description: "Whether to set the executable bit on the command file. Defaults to `true`.",
+				Description: "Whether to set the executable bit on the command file. Execute permission is added wherever `file_mode` grants read permission. Claude Code does not need it. Defaults to `false`.",
 				Optional:    true,
-				Default:     booldefault.StaticBool(true),
+				Default:     booldefault.StaticBool(false),
 				Computed:    true,
 			},
 		},
 	}
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0644")
+}
+
+func (r *claudeCommandResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config claudeCommandResourceModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		return
+	}
+
+	if config.Content.IsNull() && config.Body.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("body"), "Missing command body", "Exactly one of body and content must be set.")
+		return
+	}
+	if !config.Content.IsNull() {
+		for name, v := range map[string]interface{ IsNull() bool }{
+			"body":          config.Body,
+			"description":   config.Description,
+			"argument_hint": config.ArgumentHint,
+			"allowed_tools": config.AllowedTools,
+			"model":         config.Model,
+		} {
+			if !v.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root(name), "Conflicting command attributes", fmt.Sprintf("%s cannot be combined with content, which is written verbatim.", name))
+			}
+		}
+		return
+	}
+
+	if config.Body.IsUnknown() {
+		return
+	}
+	body := config.Body.ValueString()
+	for _, w := range commandPlaceholderWarnings(body) {
+		resp.Diagnostics.AddAttributeWarning(path.Root("body"), "Questionable argument placeholder", w)
+	}
+	if !config.ArgumentHint.IsNull() && !config.ArgumentHint.IsUnknown() && !claudeCommandPlaceholder.MatchString(body) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("argument_hint"),
+			"Unused arguments",
+			"argument_hint is set but body uses neither $ARGUMENTS nor a positional placeholder such as $1, so the arguments are not passed to the prompt.",
+		)
+	}
 }
 
 func (r *claudeCommandResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -89,31 +191,19 @@ func (r *claudeCommandResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	filePath := r.getCommandFilePath(plan.Scope.ValueString(), plan.Name.ValueString())
+	filePath := r.getCommandFilePath(plan.Scope.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString())
 	if filePath == "" {
 		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user' or 'project'")
 		return
 	}
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		resp.Diagnostics.AddError("Failed to create directory", err.Error())
-		return
-	}
-
-	// Write command file
-	fileMode := os.FileMode(0644)
-	if plan.Executable.ValueBool() {
-		fileMode = 0755
-	}
-
-	if err := os.WriteFile(filePath, []byte(plan.Content.ValueString()), fileMode); err != nil {
-		resp.Diagnostics.AddError("Failed to write command file", err.Error())
+	r.writeCommand(&resp.Diagnostics, &plan, filePath)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Set computed attributes
-	plan.ID = types.StringValue(fmt.Sprintf("claude-command-%s-%s", plan.Scope.ValueString(), plan.Name.ValueString()))
+	plan.ID = types.StringValue(claudeCommandID(plan.Scope.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString()))
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -127,7 +217,7 @@ func (r *claudeCommandResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
-	filePath := r.getCommandFilePath(state.Scope.ValueString(), state.Name.ValueString())
+	filePath := r.getCommandFilePath(state.Scope.ValueString(), state.Namespace.ValueString(), state.Name.ValueString())
 	if filePath == "" {
 		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user' or 'project'")
 		return
@@ -152,7 +242,12 @@ func (r *claudeCommandResource) Read(ctx context.Context, req resource.ReadReque
 	}
 
 	// Update state with current file contents and permissions
-	state.Content = types.StringValue(string(data))
+	if !state.Content.IsNull() {
+		state.Content = types.StringValue(string(data))
+	} else if err := r.markdownToModel(&state, data); err != nil {
+		resp.Diagnostics.AddError("Failed to parse command file", err.Error())
+		return
+	}
 	state.Executable = types.BoolValue(fileInfo.Mode()&0111 != 0) // Check if any execute bit is set
 
 	diags = resp.State.Set(ctx, state)
@@ -167,20 +262,14 @@ func (r *claudeCommandResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	filePath := r.getCommandFilePath(plan.Scope.ValueString(), plan.Name.ValueString())
+	filePath := r.getCommandFilePath(plan.Scope.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString())
 	if filePath == "" {
 		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user' or 'project'")
 		return
 	}
 
-	// Write command file
-	fileMode := os.FileMode(0644)
-	if plan.Executable.ValueBool() {
-		fileMode = 0755
-	}
-
-	if err := os.WriteFile(filePath, []byte(plan.Content.ValueString()), fileMode); err != nil {
-		resp.Diagnostics.AddError("Failed to write command file", err.Error())
+	r.writeCommand(&resp.Diagnostics, &plan, filePath)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -196,7 +285,7 @@ func (r *claudeCommandResource) Delete(ctx context.Context, req resource.DeleteR
 		return
 	}
 
-	filePath := r.getCommandFilePath(state.Scope.ValueString(), state.Name.ValueString())
+	filePath := r.getCommandFilePath(state.Scope.ValueString(), state.Namespace.ValueString(), state.Name.ValueString())
 	if filePath == "" {
 		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user' or 'project'")
 		return
@@ -209,15 +298,19 @@ func (r *claudeCommandResource) Delete(ctx context.Context, req resource.DeleteR
 }
 
 func (r *claudeCommandResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import format: scope:name (e.g., "user:my-command" or "project:build")
+	// Import format: scope:[namespace/]name (e.g., "user:my-command" or "project:frontend/component")
 	parts := strings.SplitN(req.ID, ":", 2)
 	if len(parts) != 2 {
-		resp.Diagnostics.AddError("Invalid import ID", "Import ID must be in format 'scope:name' (e.g., 'user:my-command')")
+		resp.Diagnostics.AddError("Invalid import ID", "Import ID must be in format 'scope:name' or 'scope:namespace/name' (e.g., 'user:my-command')")
 		return
 	}
 
 	scope := strings.TrimSpace(parts[0])
-	name := strings.TrimSpace(parts[1])
+	name := strings.TrimSuffix(strings.TrimSpace(parts[1]), ".md")
+	namespace := ""
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		namespace, name = name[:i], name[i+1:]
+	}
 
 	if scope != "user" && scope != "project" {
 		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user' or 'project'")
@@ -228,10 +321,17 @@ func (r *claudeCommandResource) ImportState(ctx context.Context, req resource.Im
 		resp.Diagnostics.AddError("Invalid name", "Name cannot be empty")
 		return
 	}
+	if namespace != "" && !claudeCommandNamespacePattern.MatchString(namespace) {
+		resp.Diagnostics.AddError("Invalid namespace", fmt.Sprintf("Namespace %q must be one or more directory names separated by '/'", namespace))
+		return
+	}
 
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("scope"), scope)...)
+	if namespace != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), namespace)...)
+	}
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("claude-command-%s-%s", scope, name))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), claudeCommandID(scope, namespace, name))...)
 }
 
 func (r *claudeCommandResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -251,20 +351,160 @@ func (r *claudeCommandResource) Configure(_ context.Context, req resource.Config
 	r.client = client
 }
 
-func (r *claudeCommandResource) getCommandFilePath(scope, name string) string {
+func (r *claudeCommandResource) getCommandFilePath(scope, namespace, name string) string {
+	var commandsDir string
 	switch scope {
 	case "user":
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
 			return ""
 		}
-		return filepath.Join(homeDir, ".claude", "commands", name)
+		commandsDir = filepath.Join(homeDir, ".claude", "commands")
 	case "project":
 		if r.client == nil {
 			return ""
 		}
-		return filepath.Join(r.client.workDir, ".claude", "commands", name)
+		commandsDir = filepath.Join(r.client.workDir, ".claude", "commands")
 	default:
 		return ""
 	}
+	return filepath.Join(commandsDir, filepath.FromSlash(namespace), strings.TrimSuffix(name, ".md")+".md")
+}
+
+// getLegacyCommandFilePath returns the path command files were written to before they were given
+// the .md extension, or "" when the command could not have been written there.
+func (r *claudeCommandResource) getLegacyCommandFilePath(scope, namespace, name string) string {
+	if namespace != "" || strings.HasSuffix(name, ".md") {
+		return ""
+	}
+	return strings.TrimSuffix(r.getCommandFilePath(scope, "", name), ".md")
+}
+
+// writeCommand writes the raw content when it is set, and otherwise renders the frontmatter
+// attributes and body.
+func (r *claudeCommandResource) writeCommand(diags *diag.Diagnostics, plan *claudeCommandResourceModel, filePath string) {
+	data := []byte(plan.Content.ValueString())
+	if plan.Content.IsNull() {
+		var err error
+		if data, err = r.modelToMarkdown(plan); err != nil {
+			diags.AddError("Failed to create command content", err.Error())
+			return
+		}
+	}
+
+	if err := plan.writeScript(filePath, data, plan.Executable.ValueBool()); err != nil {
+		diags.AddError("Failed to write command file", err.Error())
+		return
+	}
+
+	// Command files used to be written without the .md extension, which Claude Code ignores.
+	// Remove the old file now that the command lives at its new path.
+	legacyPath := r.getLegacyCommandFilePath(plan.Scope.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString())
+	if legacyPath == "" {
+		return
+	}
+	if info, err := os.Lstat(legacyPath); err == nil && info.Mode().IsRegular() {
+		if err := os.Remove(legacyPath); err != nil {
+			diags.AddError("Failed to remove legacy command file", err.Error())
+		}
+	}
+}
+
+func (r *claudeCommandResource) modelToMarkdown(model *claudeCommandResourceModel) ([]byte, error) {
+	fm := commandFrontmatter{
+		Description:  model.Description.ValueString(),
+		ArgumentHint: model.ArgumentHint.ValueString(),
+		AllowedTools: frontmatterListFrom(model.AllowedTools),
+		Model:        model.Model.ValueString(),
+	}
+	return frontmatter.Marshal([]byte(model.Body.ValueString()), fm)
+}
+
+func (r *claudeCommandResource) markdownToModel(model *claudeCommandResourceModel, content []byte) error {
+	doc, err := frontmatter.Parse(content)
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+
+	var fm commandFrontmatter
+	if err := doc.Decode(&fm); err != nil {
+		return err
+	}
+
+	model.Description = stringValueOrNull(fm.Description)
+	model.ArgumentHint = stringValueOrNull(fm.ArgumentHint)
+	model.AllowedTools = frontmatterListValue(fm.AllowedTools)
+	model.Model = stringValueOrNull(fm.Model)
+	model.Body = types.StringValue(string(doc.Body))
+
+	return nil
+}
+
+// claudeCommandID builds the resource ID; the namespace is part of it so that commands with
+// the same name in different namespaces do not collide.
+func claudeCommandID(scope, namespace, name string) string {
+	name = strings.TrimSuffix(name, ".md")
+	if namespace != "" {
+		name = namespace + "/" + name
+	}
+	return fmt.Sprintf("claude-command-%s-%s", scope, name)
+}
+
+// commandPlaceholderWarnings reports argument placeholders in a command body that Claude Code
+// will not substitute, and gaps in the positional placeholders.
+func commandPlaceholderWarnings(body string) []string {
+	var warnings []string
+
+	for _, m := range claudeCommandMistypedArgs.FindAllString(body, -1) {
+		warnings = append(warnings, fmt.Sprintf("%q is not substituted; use $ARGUMENTS for all arguments", m))
+	}
+
+	positions := make(map[int]struct{})
+	for _, m := range claudeCommandPlaceholder.FindAllStringSubmatch(body, -1) {
+		if m[1] == "ARGUMENTS" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		if n == 0 {
+			warnings = append(warnings, "$0 is not substituted; positional arguments start at $1")
+			continue
+		}
+		positions[n] = struct{}{}
+	}
+
+	var missing []string
+	maxPos := 0
+	for n := range positions {
+		if n > maxPos {
+			maxPos = n
+		}
+	}
+	for n := 1; n < maxPos; n++ {
+		if _, ok := positions[n]; !ok {
+			missing = append(missing, "$"+strconv.Itoa(n))
+		}
+	}
+	if len(missing) > 0 {
+		warnings = append(warnings, fmt.Sprintf("body uses $%d but not %s", maxPos, strings.Join(missing, ", ")))
+	}
+
+	return warnings
+}
+
+func stringValueOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// claudeCommandName returns a validator for command names, which must not contain path
+// separators; use namespace for subdirectories.
+func claudeCommandName() validator.String {
+	return stringMatches(claudeCommandNamePattern, "value must be a command name made of letters, digits, '.', '_' and '-'; use namespace for subdirectories")
+}
+
+// claudeCommandNamespace returns a validator for command namespaces such as `frontend/react`.
+func claudeCommandNamespace() validator.String {
+	return stringMatches(claudeCommandNamespacePattern, "value must be one or more directory names made of letters, digits, '_' and '-', separated by '/'")
 }
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -17,16 +19,30 @@ func TestClaudeCommandResource_getCommandFilePath(t *testing.T) {
 	r := &claudeCommandResource{client: client}
 
 	testCases := []struct {
-		name     string
-		scope    string
-		cmdName  string
-		expected string
+		name      string
+		scope     string
+		namespace string
+		cmdName   string
+		expected  string
 	}{
 		{
 			name:     "project_scope",
 			scope:    "project",
 			cmdName:  "test-cmd",
-			expected: filepath.Join(tempDir, ".claude", "commands", "test-cmd"),
+			expected: filepath.Join(tempDir, ".claude", "commands", "test-cmd.md"),
+		},
+		{
+			name:     "md_extension_not_doubled",
+			scope:    "project",
+			cmdName:  "test-cmd.md",
+			expected: filepath.Join(tempDir, ".claude", "commands", "test-cmd.md"),
+		},
+		{
+			name:      "namespaced",
+			scope:     "project",
+			namespace: "frontend/react",
+			cmdName:   "component",
+			expected:  filepath.Join(tempDir, ".claude", "commands", "frontend", "react", "component.md"),
 		},
 		{
 			name:     "invalid_scope",
@@ -38,7 +54,7 @@ func TestClaudeCommandResource_getCommandFilePath(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := r.getCommandFilePath(tc.scope, tc.cmdName)
+			result := r.getCommandFilePath(tc.scope, tc.namespace, tc.cmdName)
 			if result != tc.expected {
 				t.Errorf("Expected path %q, got %q", tc.expected, result)
 			}
@@ -48,14 +64,140 @@ func TestClaudeCommandResource_getCommandFilePath(t *testing.T) {
 	// Test user scope (requires actual home directory)
 	homeDir, err := os.UserHomeDir()
 	if err == nil {
-		expectedUserPath := filepath.Join(homeDir, ".claude", "commands", "user-cmd")
-		result := r.getCommandFilePath("user", "user-cmd")
+		expectedUserPath := filepath.Join(homeDir, ".claude", "commands", "user-cmd.md")
+		result := r.getCommandFilePath("user", "", "user-cmd")
 		if result != expectedUserPath {
 			t.Errorf("Expected user path %q, got %q", expectedUserPath, result)
 		}
 	}
 }
 
+func TestClaudeCommandResource_writeCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	r := &claudeCommandResource{client: &FileClient{workDir: tempDir}}
+	commandsDir := filepath.Join(tempDir, ".claude", "commands")
+
+	// A file at the path used before the .md extension was added, and a namespace directory
+	// with the same name as a command
+	if err := os.MkdirAll(filepath.Join(commandsDir, "review"), 0755); err != nil {
+		t.Fatalf("Failed to create commands directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(commandsDir, "deploy"), []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to write legacy command: %v", err)
+	}
+
+	for _, name := range []string{"deploy", "review"} {
+		plan := claudeCommandResourceModel{
+			Scope:     types.StringValue("project"),
+			Namespace: types.StringNull(),
+			Name:      types.StringValue(name),
+			Content:   types.StringValue("Deploy the app"),
+		}
+		var diags diag.Diagnostics
+		r.writeCommand(&diags, &plan, r.getCommandFilePath("project", "", name))
+		if diags.HasError() {
+			t.Fatalf("Unexpected error: %v", diags)
+		}
+		if _, err := os.Stat(filepath.Join(commandsDir, name+".md")); err != nil {
+			t.Errorf("Expected the command to be written: %v", err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(commandsDir, "deploy")); !os.IsNotExist(err) {
+		t.Errorf("Expected the legacy command file to be removed, got: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(commandsDir, "review")); err != nil || !info.IsDir() {
+		t.Errorf("Expected the namespace directory to be kept, got: %v", err)
+	}
+}
+
+func TestClaudeCommandResource_markdown(t *testing.T) {
+	r := &claudeCommandResource{}
+
+	testCases := []struct {
+		name     string
+		model    claudeCommandResourceModel
+		expected string
+	}{
+		{
+			name: "all_frontmatter",
+			model: claudeCommandResourceModel{
+				Description:  types.StringValue("Review a pull request"),
+				ArgumentHint: types.StringValue("[pr-number]"),
+				AllowedTools: frontmatterListValue([]string{"Bash(gh pr view:*)", "Read"}),
+				Model:        types.StringValue("sonnet"),
+				Body:         types.StringValue("Review PR #$1.\n"),
+			},
+			expected: "---\ndescription: Review a pull request\nargument-hint: '[pr-number]'\nallowed-tools: Bash(gh pr view:*), Read\nmodel: sonnet\n---\nReview PR #$1.\n",
+		},
+		{
+			name: "body_only",
+			model: claudeCommandResourceModel{
+				AllowedTools: types.ListNull(types.StringType),
+				Body:         types.StringValue("Run the tests.\n"),
+			},
+			expected: "Run the tests.\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := r.modelToMarkdown(&tc.model)
+			if err != nil {
+				t.Fatalf("Failed to render command: %v", err)
+			}
+			if string(data) != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, string(data))
+			}
+
+			var parsed claudeCommandResourceModel
+			if err := r.markdownToModel(&parsed, data); err != nil {
+				t.Fatalf("Failed to parse command: %v", err)
+			}
+			if parsed.Description.ValueString() != tc.model.Description.ValueString() {
+				t.Errorf("Expected description %s, got %s", tc.model.Description, parsed.Description)
+			}
+			if !parsed.AllowedTools.Equal(tc.model.AllowedTools) {
+				t.Errorf("Expected allowed_tools %s, got %s", tc.model.AllowedTools, parsed.AllowedTools)
+			}
+			if !parsed.Body.Equal(tc.model.Body) {
+				t.Errorf("Expected body %s, got %s", tc.model.Body, parsed.Body)
+			}
+		})
+	}
+}
+
+func TestClaudeCommandResource_commandPlaceholderWarnings(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{name: "arguments", body: "Fix issue $ARGUMENTS", expected: 0},
+		{name: "positional", body: "Review PR #$1 with priority $2", expected: 0},
+		{name: "no_placeholders", body: "Run the tests", expected: 0},
+		{name: "mistyped_arguments", body: "Fix issue $ARGS and ${arguments}", expected: 2},
+		{name: "zero_index", body: "Deploy $0", expected: 1},
+		{name: "positional_gap", body: "Compare $1 with $3", expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings := commandPlaceholderWarnings(tc.body)
+			if len(warnings) != tc.expected {
+				t.Errorf("Expected %d warnings, got %v", tc.expected, warnings)
+			}
+		})
+	}
+
+	// Missing positions are listed in numeric order
+	warnings := commandPlaceholderWarnings("Compare $1 with $11")
+	expected := "body uses $11 but not $2, $3, $4, $5, $6, $7, $8, $9, $10"
+	if len(warnings) != 1 || warnings[0] != expected {
+		t.Errorf("Expected %q, got %v", expected, warnings)
+	}
+}
+
 func TestClaudeCommandResource_FileOperations(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -279,6 +421,8 @@ func TestAccClaudeCommandResource_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateId:     "project:test-acc",
+				// Imported commands are read into body and the frontmatter attributes, not content
+				ImportStateVerifyIgnore: []string{"body", "content"},
 			},
 			// Update and Read testing
 			{
@@ -293,6 +437,70 @@ func TestAccClaudeCommandResource_basic(t *testing.T) {
 	})
 }
 
+func TestAccClaudeCommandResource_body(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, ".claude", "commands", "frontend", "component.md")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccClaudeCommandResourceBodyConfig(workDir, "Create a component named $1."),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_command.test", "id", "claude-command-project-frontend/component"),
+					resource.TestCheckResourceAttr("agentsmith_claude_command.test", "executable", "false"),
+					resource.TestCheckResourceAttr("agentsmith_claude_command.test", "allowed_tools.#", "2"),
+					resource.TestCheckResourceAttrWith("agentsmith_claude_command.test", "body", func(string) error {
+						data, err := os.ReadFile(filePath)
+						if err != nil {
+							return err
+						}
+						if !strings.Contains(string(data), "argument-hint: '[name]'\n") {
+							return fmt.Errorf("expected argument-hint frontmatter, got %q", data)
+						}
+						return nil
+					}),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "agentsmith_claude_command.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "project:frontend/component.md",
+			},
+			// Update and Read testing
+			{
+				Config: testAccClaudeCommandResourceBodyConfig(workDir, "Create a React component named $1."),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_command.test", "body", "Create a React component named $1.\n"),
+				),
+			},
+		},
+	})
+}
+
+func testAccClaudeCommandResourceBodyConfig(workDir, body string) string {
+	return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_claude_command" "test" {
+  scope         = "project"
+  namespace     = "frontend"
+  name          = "component"
+  description   = "Scaffold a component"
+  argument_hint = "[name]"
+  allowed_tools = ["Read", "Write(src/components/**)"]
+  body          = <<-EOT
+    %s
+  EOT
+}
+`, workDir, body)
+}
+
 func testAccClaudeCommandResourceConfig(workDir, scope, name, content, executable string) string {
 	return fmt.Sprintf(`
 provider "agentsmith" {
@@ -8,13 +8,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"gopkg.in/yaml.v3"
 	"io/fs"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strconv"
 	"strings"
+	"terraform-provider-agentsmith/internal/frontmatter"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -46,23 +45,23 @@ type claudeDataSourceModel struct {
 
 // claudeSettingsModel maps the settings nested attribute to a Go type.
 type claudeSettingsModel struct {
-	APIKeyHelper               types.String      `tfsdk:"api_key_helper"`
-	CleanupPeriodDays          types.Int64       `tfsdk:"cleanup_period_days"`
-	Env                        types.Map         `tfsdk:"env"`
-	IncludeCoAuthoredBy        types.Bool        `tfsdk:"include_co_authored_by"`
-	Permissions                *permissionsModel `tfsdk:"permissions"`
-	Hooks                      types.Map         `tfsdk:"hooks"`
-	DisableAllHooks            types.Bool        `tfsdk:"disable_all_hooks"`
-	Model                      types.String      `tfsdk:"model"`
-	StatusLine                 *statusLineModel  `tfsdk:"status_line"`
-	OutputStyle                types.String      `tfsdk:"output_style"`
-	ForceLoginMethod           types.String      `tfsdk:"force_login_method"`
-	ForceLoginOrgUUID          types.String      `tfsdk:"force_login_org_uuid"`
-	EnableAllProjectMcpServers types.Bool        `tfsdk:"enable_all_project_mcp_servers"`
-	EnabledMcpjsonServers      []types.String    `tfsdk:"enabled_mcpjson_servers"`
-	DisabledMcpjsonServers     []types.String    `tfsdk:"disabled_mcpjson_servers"`
-	AwsAuthRefresh             types.String      `tfsdk:"aws_auth_refresh"`
-	AwsCredentialExport        types.String      `tfsdk:"aws_credential_export"`
+	APIKeyHelper               types.String              `tfsdk:"api_key_helper"`
+	CleanupPeriodDays          types.Int64               `tfsdk:"cleanup_period_days"`
+	Env                        types.Map                 `tfsdk:"env"`
+	IncludeCoAuthoredBy        types.Bool                `tfsdk:"include_co_authored_by"`
+	Permissions                *permissionsModel         `tfsdk:"permissions"`
+	Hooks                      []claudeSettingsHookModel `tfsdk:"hooks"`
+	DisableAllHooks            types.Bool                `tfsdk:"disable_all_hooks"`
+	Model                      types.String              `tfsdk:"model"`
+	StatusLine                 *statusLineModel          `tfsdk:"status_line"`
+	OutputStyle                types.String              `tfsdk:"output_style"`
+	ForceLoginMethod           types.String              `tfsdk:"force_login_method"`
+	ForceLoginOrgUUID          types.String              `tfsdk:"force_login_org_uuid"`
+	EnableAllProjectMcpServers types.Bool                `tfsdk:"enable_all_project_mcp_servers"`
+	EnabledMcpjsonServers      []types.String            `tfsdk:"enabled_mcpjson_servers"`
+	DisabledMcpjsonServers     []types.String            `tfsdk:"disabled_mcpjson_servers"`
+	AwsAuthRefresh             types.String              `tfsdk:"aws_auth_refresh"`
+	AwsCredentialExport        types.String              `tfsdk:"aws_credential_export"`
 }
 
 // permissionsModel maps the permissions nested attribute to a Go type.
@@ -139,12 +138,14 @@ type globalConfigModel struct {
 
 // subagentModel maps the subagents nested attribute to a Go type.
 type subagentModel struct {
-	Path        types.String `tfsdk:"path"`
-	Name        types.String `tfsdk:"name"`
-	Model       types.String `tfsdk:"model"`
-	Description types.String `tfsdk:"description"`
-	Color       types.String `tfsdk:"color"`
-	Prompt      types.String `tfsdk:"prompt"`
+	Path             types.String `tfsdk:"path"`
+	Name             types.String `tfsdk:"name"`
+	Model            types.String `tfsdk:"model"`
+	Description      types.String `tfsdk:"description"`
+	Color            types.String `tfsdk:"color"`
+	Tools            types.List   `tfsdk:"tools"`
+	ExtraFrontmatter types.Map    `tfsdk:"extra_frontmatter"`
+	Prompt           types.String `tfsdk:"prompt"`
 }
 
 // commandModel maps the commands nested attribute to a Go type.
@@ -226,10 +227,26 @@ func (d *claudeDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 							},
 						},
 					},
-					"hooks": schema.MapAttribute{
-						Description: "A map of custom commands to run before or after tool executions (e.g., `PreToolUse`).",
-						ElementType: types.MapType{ElemType: types.StringType},
+					"hooks": schema.ListNestedAttribute{
+						Description: "Custom commands to run at points in the Claude Code lifecycle, one entry per event and matcher.",
 						Computed:    true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"event":   schema.StringAttribute{Description: "The hook event (e.g., `PreToolUse`).", Computed: true},
+								"matcher": schema.StringAttribute{Description: "The pattern matching tool names for this entry.", Computed: true},
+								"hook": schema.ListNestedAttribute{
+									Description: "The ordered list of hook commands to execute.",
+									Computed:    true,
+									NestedObject: schema.NestedAttributeObject{
+										Attributes: map[string]schema.Attribute{
+											"type":    schema.StringAttribute{Description: "The type of hook, e.g., `command`.", Computed: true},
+											"command": schema.StringAttribute{Description: "The bash command to execute.", Computed: true},
+											"timeout": schema.Int64Attribute{Description: "How long the command may run, in seconds.", Computed: true},
+										},
+									},
+								},
+							},
+						},
 					},
 					"disable_all_hooks": schema.BoolAttribute{
 						Description: "If true, disables all configured hooks.",
@@ -334,7 +351,7 @@ func (d *claudeDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				},
 			},
 			"global_config": schema.SingleNestedAttribute{
-				Description: "Global configuration settings from `~/.claude/config.json`.",
+				Description: "Global configuration settings from `~/.claude.json` (or `$CLAUDE_CONFIG_DIR/.claude.json`). Null when the file does not exist.",
 				Computed:    true,
 				Attributes: map[string]schema.Attribute{
 					"auto_updates": schema.BoolAttribute{
@@ -360,12 +377,14 @@ func (d *claudeDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Computed:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
-						"path":        schema.StringAttribute{Description: "The absolute path to the subagent's markdown file.", Computed: true},
-						"name":        schema.StringAttribute{Description: "The name of the subagent.", Computed: true},
-						"model":       schema.StringAttribute{Description: "The model the subagent is configured to use.", Computed: true},
-						"description": schema.StringAttribute{Description: "A description of the subagent's purpose.", Computed: true},
-						"color":       schema.StringAttribute{Description: "The color used for the subagent in the UI.", Computed: true},
-						"prompt":      schema.StringAttribute{Description: "The full instructional prompt for the subagent.", Computed: true},
+						"path":              schema.StringAttribute{Description: "The absolute path to the subagent's markdown file.", Computed: true},
+						"name":              schema.StringAttribute{Description: "The name of the subagent.", Computed: true},
+						"model":             schema.StringAttribute{Description: "The model the subagent is configured to use.", Computed: true},
+						"description":       schema.StringAttribute{Description: "A description of the subagent's purpose.", Computed: true},
+						"color":             schema.StringAttribute{Description: "The color used for the subagent in the UI.", Computed: true},
+						"tools":             schema.ListAttribute{Description: "The tools the subagent may use. Null when the subagent inherits every tool.", ElementType: types.StringType, Computed: true},
+						"extra_frontmatter": schema.MapAttribute{Description: "Frontmatter keys other than name, model, description, color and tools. Values that are not strings are rendered as YAML.", ElementType: types.StringType, Computed: true},
+						"prompt":            schema.StringAttribute{Description: "The full instructional prompt for the subagent.", Computed: true},
 					},
 				},
 			},
@@ -430,6 +449,12 @@ func (d *claudeDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	// Read the global state file
+	state.GlobalConfig = d.readGlobalConfig(&resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Set ID
 	state.ID = types.StringValue("claude-settings")
 
@@ -483,18 +508,8 @@ func (d *claudeDataSource) getSettingsFilePaths(diagnostics *diag.Diagnostics) [
 	}
 
 	// Enterprise settings
-	var enterprisePath string
-	switch runtime.GOOS {
-	case "darwin":
-		enterprisePath = "/Library/Application Support/ClaudeCode/managed-settings.json"
-	case "linux":
-		enterprisePath = "/etc/claude-code/managed-settings.json"
-	case "windows":
-		enterprisePath = "C:\\ProgramData\\ClaudeCode\\managed-settings.json"
-	}
-	if enterprisePath != "" {
-		paths = append(paths, enterprisePath)
-	}
+	enterprisePath := filepath.Join(claudeManagedSystemRoot(), "managed-settings.json")
+	paths = append(paths, enterprisePath)
 
 	return paths
 }
@@ -561,19 +576,12 @@ func (d *claudeDataSource) populateSettingsFromMap(ctx context.Context, diags *d
 		settingsModel.Env = types.MapNull(types.StringType)
 	}
 
-	if val, ok := data["hooks"].(map[string]interface{}); ok {
-		if len(val) == 0 {
-			settingsModel.Hooks = types.MapNull(types.MapType{ElemType: types.StringType})
-		} else {
-			hooksMap, d := types.MapValueFrom(ctx, types.MapType{ElemType: types.StringType}, val)
-			if d.HasError() {
-				diags.Append(d...)
-				return
-			}
-			settingsModel.Hooks = hooksMap
+	if val, ok := data["hooks"]; ok {
+		hooks, ok := hooksFromSettings(val, nil)
+		if !ok {
+			diags.AddWarning("Unsupported hooks format", "The `hooks` key in the settings file has an unexpected shape and was ignored.")
 		}
-	} else {
-		settingsModel.Hooks = types.MapNull(types.MapType{ElemType: types.StringType})
+		settingsModel.Hooks = hooks
 	}
 
 	if val, ok := data["enabledMcpjsonServers"].([]interface{}); ok {
@@ -673,25 +681,27 @@ func (d *claudeDataSource) readSubagents(ctx context.Context, diags *diag.Diagno
 					return nil // Continue walking
 				}
 
-				parts := strings.SplitN(string(content), "---", 3)
-				if len(parts) < 3 {
-					diags.AddWarning(fmt.Sprintf("Invalid format for subagent file: %s", path), "Missing YAML frontmatter separators.")
+				doc, err := frontmatter.Parse(content)
+				if err != nil {
+					diags.AddWarning(fmt.Sprintf("Invalid format for subagent file: %s", path), err.Error())
 					return nil // Continue walking
 				}
 
-				var frontmatter subagentFrontmatter
-				if err := yaml.Unmarshal([]byte(parts[1]), &frontmatter); err != nil {
+				fm, extra, err := parseSubagentFrontmatter(doc)
+				if err != nil {
 					diags.AddWarning(fmt.Sprintf("Failed to parse YAML frontmatter for: %s", path), err.Error())
 					return nil // Continue walking
 				}
 
 				subagents = append(subagents, subagentModel{
-					Path:        types.StringValue(path),
-					Name:        types.StringValue(frontmatter.Name),
-					Model:       types.StringValue(frontmatter.Model),
-					Description: types.StringValue(frontmatter.Description),
-					Color:       types.StringValue(frontmatter.Color),
-					Prompt:      types.StringValue(strings.TrimSpace(parts[2])),
+					Path:             types.StringValue(path),
+					Name:             types.StringValue(fm.Name),
+					Model:            types.StringValue(fm.Model),
+					Description:      types.StringValue(fm.Description),
+					Color:            types.StringValue(fm.Color),
+					Tools:            frontmatterListValue(fm.Tools),
+					ExtraFrontmatter: subagentExtraFrontmatterValue(extra),
+					Prompt:           types.StringValue(string(doc.Body)),
 				})
 			}
 			return nil
@@ -752,6 +762,27 @@ func (d *claudeDataSource) readHookFiles(ctx context.Context, diags *diag.Diagno
 	return hooks
 }
 
+func (d *claudeDataSource) readGlobalConfig(diags *diag.Diagnostics) *globalConfigModel {
+	filePath := claudeStateFilePath()
+	config, err := readSettingsJSON(filePath)
+	if os.IsNotExist(err) || filePath == "" {
+		return nil
+	}
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Error reading global config file: %s", filePath), err.Error())
+		return nil
+	}
+
+	var model claudeGlobalConfigResourceModel
+	(&claudeGlobalConfigResource{}).configToModel(&model, config, true)
+	return &globalConfigModel{
+		AutoUpdates:           model.AutoUpdates,
+		PreferredNotifChannel: model.PreferredNotifChannel,
+		Theme:                 model.Theme,
+		Verbose:               model.Verbose,
+	}
+}
+
 func (d *claudeDataSource) getDiscoveryDirs(diags *diag.Diagnostics, dirName string) []string {
 	userHomeDir, err := os.UserHomeDir()
 	if err != nil {
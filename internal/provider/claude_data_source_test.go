@@ -12,6 +12,7 @@ import (
 func TestAccClaudeDataSource_fileDiscovery(t *testing.T) {
 	homeDir := t.TempDir()
 	t.Setenv("HOME", homeDir)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
 
 	projectDir := t.TempDir()
 
@@ -21,9 +22,10 @@ func TestAccClaudeDataSource_fileDiscovery(t *testing.T) {
 	os.MkdirAll(userAgentsDir, 0755)
 	os.MkdirAll(userCommandsDir, 0755)
 
-	userSubagentContent := "---\nname: User Agent\nmodel: test-model\n---\nUser agent prompt."
+	userSubagentContent := "---\nname: User Agent\nmodel: test-model\ntools: Read, Grep\npermissionMode: plan\n---\nUser agent prompt."
 	os.WriteFile(filepath.Join(userAgentsDir, "user_agent.md"), []byte(userSubagentContent), 0644)
 	os.WriteFile(filepath.Join(userCommandsDir, "user_command"), []byte("user command content"), 0644)
+	os.WriteFile(filepath.Join(homeDir, ".claude.json"), []byte(`{"theme":"dark","numStartups":2}`), 0600)
 
 	// --- Create Project-level files ---
 	projectAgentsDir := filepath.Join(projectDir, ".claude", "agents")
@@ -54,7 +56,14 @@ func TestAccClaudeDataSource_fileDiscovery(t *testing.T) {
 					// Check specific subagent content
 					resource.TestCheckResourceAttr("data.agentsmith_claude.test", "subagents.0.name", "User Agent"),
 					resource.TestCheckResourceAttr("data.agentsmith_claude.test", "subagents.0.model", "test-model"),
+					resource.TestCheckResourceAttr("data.agentsmith_claude.test", "subagents.0.tools.#", "2"),
+					resource.TestCheckResourceAttr("data.agentsmith_claude.test", "subagents.0.extra_frontmatter.permissionMode", "plan"),
+					resource.TestCheckNoResourceAttr("data.agentsmith_claude.test", "subagents.1.tools.#"),
 					resource.TestCheckResourceAttr("data.agentsmith_claude.test", "subagents.1.name", "Project Agent"),
+
+					// Check the global state file
+					resource.TestCheckResourceAttr("data.agentsmith_claude.test", "global_config.theme", "dark"),
+					resource.TestCheckNoResourceAttr("data.agentsmith_claude.test", "global_config.verbose"),
 				),
 			},
 		},
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// frontmatterList is a list-valued frontmatter key such as a subagent's `tools` or a command's
+// `allowed-tools`. Claude Code writes these as a comma-separated string; a YAML sequence is
+// accepted as well. Commas inside parentheses, as in `Bash(git commit -m "a, b")`, do not
+// separate entries.
+type frontmatterList []string
+
+func (l frontmatterList) MarshalYAML() (interface{}, error) {
+	return strings.Join(l, ", "), nil
+}
+
+func (l *frontmatterList) UnmarshalYAML(node *yaml.Node) error {
+	var items []string
+	switch node.Kind {
+	case yaml.SequenceNode:
+		if err := node.Decode(&items); err != nil {
+			return err
+		}
+	case yaml.ScalarNode:
+		items = splitFrontmatterList(node.Value)
+	default:
+		return fmt.Errorf("expected a comma-separated string or a list")
+	}
+	*l = items
+	return nil
+}
+
+// splitFrontmatterList splits s on the commas that are not inside parentheses.
+func splitFrontmatterList(s string) []string {
+	var items []string
+	depth, start := 0, 0
+	add := func(item string) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				add(s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(s[start:])
+	return items
+}
+
+// frontmatterListValue returns the list as a Terraform value, or null when it is empty.
+func frontmatterListValue(items frontmatterList) types.List {
+	if len(items) == 0 {
+		return types.ListNull(types.StringType)
+	}
+	elems := make([]attr.Value, len(items))
+	for i, item := range items {
+		elems[i] = types.StringValue(item)
+	}
+	return types.ListValueMust(types.StringType, elems)
+}
+
+// frontmatterListFrom returns the string elements of a Terraform list.
+func frontmatterListFrom(list types.List) frontmatterList {
+	var items frontmatterList
+	for _, v := range list.Elements() {
+		if item, ok := v.(types.String); ok {
+			items = append(items, item.ValueString())
+		}
+	}
+	return items
+}
+
+// frontmatterValue decodes a frontmatter value given as YAML, so that values such as `5`, `true`
+// or `[a, b]` keep their type in the file. Empty values and values that are not valid YAML are
+// kept as strings.
+func frontmatterValue(s string) interface{} {
+	if strings.TrimSpace(s) == "" {
+		return s
+	}
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
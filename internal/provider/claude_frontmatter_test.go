@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFrontmatterList_YAML(t *testing.T) {
+	testCases := []struct {
+		name     string
+		yaml     string
+		expected frontmatterList
+	}{
+		{name: "comma_separated", yaml: "tools: Read, Grep, Glob", expected: frontmatterList{"Read", "Grep", "Glob"}},
+		{name: "sequence", yaml: "tools:\n  - Read\n  - Grep", expected: frontmatterList{"Read", "Grep"}},
+		{name: "commas_in_specifier", yaml: `tools: Bash(git commit -m "a, b"), Read`, expected: frontmatterList{`Bash(git commit -m "a, b")`, "Read"}},
+		{name: "empty_entries", yaml: "tools: Read, , Grep,", expected: frontmatterList{"Read", "Grep"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var doc struct {
+				Tools frontmatterList `yaml:"tools"`
+			}
+			if err := yaml.Unmarshal([]byte(tc.yaml), &doc); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(doc.Tools, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, doc.Tools)
+			}
+		})
+	}
+
+	out, err := yaml.Marshal(struct {
+		Tools frontmatterList `yaml:"tools,omitempty"`
+	}{Tools: frontmatterList{"Read", "Grep"}})
+	if err != nil || string(out) != "tools: Read, Grep\n" {
+		t.Errorf("Expected comma-separated output, got %q (%v)", out, err)
+	}
+
+	if v := frontmatterListValue(nil); !v.IsNull() {
+		t.Errorf("Expected null list for no entries, got %v", v)
+	}
+	if items := frontmatterListFrom(frontmatterListValue(frontmatterList{"Read"})); !reflect.DeepEqual(items, frontmatterList{"Read"}) {
+		t.Errorf("Expected list to round-trip, got %v", items)
+	}
+}
+
+func TestFrontmatterValue(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected interface{}
+	}{
+		{value: "plan", expected: "plan"},
+		{value: "5", expected: 5},
+		{value: "true", expected: true},
+		{value: "'true'", expected: "true"},
+		{value: "[Read, Grep]", expected: []interface{}{"Read", "Grep"}},
+		{value: "", expected: ""},
+		{value: "[unclosed", expected: "[unclosed"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			if got := frontmatterValue(tc.value); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %#v, got %#v", tc.expected, got)
+			}
+		})
+	}
+}
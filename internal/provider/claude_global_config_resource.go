@@ -5,21 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-agentsmith/internal/fileio"
 )
 
 var (
 	_ resource.Resource                = &claudeGlobalConfigResource{}
 	_ resource.ResourceWithConfigure   = &claudeGlobalConfigResource{}
 	_ resource.ResourceWithImportState = &claudeGlobalConfigResource{}
+	_ resource.ResourceWithModifyPlan  = &claudeGlobalConfigResource{}
 )
 
 func NewClaudeGlobalConfigResource() resource.Resource {
@@ -36,6 +37,9 @@ type claudeGlobalConfigResourceModel struct {
 	PreferredNotifChannel types.String `tfsdk:"preferred_notif_channel"`
 	Theme                 types.String `tfsdk:"theme"`
 	Verbose               types.Bool   `tfsdk:"verbose"`
+	McpServers            types.Map    `tfsdk:"mcp_servers"`
+	ExtraSettings         types.String `tfsdk:"extra_settings"`
+	fileWriteOptionsModel
 }
 
 func (r *claudeGlobalConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -44,7 +48,7 @@ func (r *claudeGlobalConfigResource) Metadata(_ context.Context, req resource.Me
 
 func (r *claudeGlobalConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages the Claude Code global configuration file at `~/.claude/config.json`. This resource is a singleton; only one should be defined.",
+		Description: "Manages preferences and user-scope MCP servers in the Claude Code global state file, `~/.claude.json` (or `$CLAUDE_CONFIG_DIR/.claude.json`). Claude Code also keeps runtime state in this file; every key this resource does not manage is preserved. This resource is a singleton; only one should be defined.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "A static identifier for this singleton resource.",
@@ -56,8 +60,6 @@ func (r *claudeGlobalConfigResource) Schema(_ context.Context, _ resource.Schema
 			"auto_updates": schema.BoolAttribute{
 				Description: "DEPRECATED. This field is no longer used. Use the `DISABLE_AUTOUPDATER` environment variable instead.",
 				Optional:    true,
-				Default:     booldefault.StaticBool(true),
-				Computed:    true,
 			},
 			"preferred_notif_channel": schema.StringAttribute{
 				Description: "The preferred channel for receiving notifications. Valid values are `iterm2`, `iterm2_with_bell`, `terminal_bell`, or `notifications_disabled`.",
@@ -68,41 +70,58 @@ func (r *claudeGlobalConfigResource) Schema(_ context.Context, _ resource.Schema
 				Optional:    true,
 			},
 			"verbose": schema.BoolAttribute{
-				Description: "If true, shows full bash and command outputs. Defaults to `false`.",
+				Description: "If true, shows full bash and command outputs.",
 				Optional:    true,
-				Default:     booldefault.StaticBool(false),
-				Computed:    true,
 			},
 		},
 	}
+	resp.Schema.Attributes["mcp_servers"] = schema.MapAttribute{
+		Description: "A map of MCP server name to its JSON definition, written to the user-scope `mcpServers` key. Values can be the `json` output of the `agentsmith_mcp_stdio` and `agentsmith_mcp_remote` data sources. Only the servers named here are managed; servers added with `claude mcp add` are kept.",
+		ElementType: types.StringType,
+		Optional:    true,
+	}
+	resp.Schema.Attributes["extra_settings"] = extraSettingsAttribute("`~/.claude.json`")
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0600")
 }
 
-func (r *claudeGlobalConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+// ModifyPlan checks extra_settings against the planned values, including the MCP servers.
+func (r *claudeGlobalConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
 	var plan claudeGlobalConfigResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	filePath := r.getGlobalConfigFilePath()
+	typed := r.modelToConfig(&plan)
+	if servers, err := claudeMCPServersValue(ctx, &resp.Diagnostics, plan.McpServers); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("mcp_servers"), "Invalid MCP server definition", err.Error())
+	} else if servers != nil {
+		typed["mcpServers"] = servers
+	}
+	validateExtraSettings(&resp.Diagnostics, plan.ExtraSettings, typed)
+}
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		resp.Diagnostics.AddError("Failed to create directory", err.Error())
+func (r *claudeGlobalConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan claudeGlobalConfigResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Create global config JSON
-	configData := r.modelToConfig(&plan)
-	jsonData, err := json.MarshalIndent(configData, "", "  ")
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to marshal JSON", err.Error())
+	filePath := r.getGlobalConfigFilePath()
+	if filePath == "" {
+		resp.Diagnostics.AddError("Failed to locate global config file", "Unable to determine the user home directory")
 		return
 	}
+	defer fileio.Lock(filePath)()
 
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
-		resp.Diagnostics.AddError("Failed to write global config file", err.Error())
+	r.writeConfig(ctx, &resp.Diagnostics, &plan, nil, filePath)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -123,52 +142,50 @@ func (r *claudeGlobalConfigResource) Read(ctx context.Context, req resource.Read
 
 	filePath := r.getGlobalConfigFilePath()
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	// Read and parse global config file
+	configData, err := readSettingsJSON(filePath)
+	if os.IsNotExist(err) {
 		resp.State.RemoveResource(ctx)
 		return
 	}
-
-	// Read and parse global config file
-	data, err := os.ReadFile(filePath)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read global config file", err.Error())
 		return
 	}
 
-	var configData map[string]interface{}
-	if err := json.Unmarshal(data, &configData); err != nil {
-		resp.Diagnostics.AddError("Failed to parse global config JSON", err.Error())
-		return
+	// A freshly imported resource adopts the values it finds; otherwise only the attributes
+	// already in state are refreshed.
+	imported, d := req.Private.GetKey(ctx, claudeSettingsImportedKey)
+	resp.Diagnostics.Append(d...)
+	if len(imported) > 0 {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, nil)...)
 	}
-
-	// Update state with current file contents
-	r.configToModel(&state, configData)
+	state.McpServers = refreshOwnedMCPServers(ctx, &resp.Diagnostics, state.McpServers, configData)
+	r.configToModel(&state, configData, len(imported) > 0)
 
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
 }
 
 func (r *claudeGlobalConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan claudeGlobalConfigResourceModel
+	var plan, state claudeGlobalConfigResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	filePath := r.getGlobalConfigFilePath()
-
-	// Create global config JSON
-	configData := r.modelToConfig(&plan)
-	jsonData, err := json.MarshalIndent(configData, "", "  ")
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to marshal JSON", err.Error())
+	if filePath == "" {
+		resp.Diagnostics.AddError("Failed to locate global config file", "Unable to determine the user home directory")
 		return
 	}
+	defer fileio.Lock(filePath)()
 
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
-		resp.Diagnostics.AddError("Failed to write global config file", err.Error())
+	r.writeConfig(ctx, &resp.Diagnostics, &plan, &state, filePath)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -177,17 +194,27 @@ func (r *claudeGlobalConfigResource) Update(ctx context.Context, req resource.Up
 }
 
 func (r *claudeGlobalConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	filePath := r.getGlobalConfigFilePath()
+	var state claudeGlobalConfigResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
-		resp.Diagnostics.AddError("Failed to delete global config file", err.Error())
+	filePath := r.getGlobalConfigFilePath()
+	if filePath == "" {
 		return
 	}
+	defer fileio.Lock(filePath)()
+
+	// The file holds Claude Code's runtime state, so only the managed keys are removed
+	r.writeConfig(ctx, &resp.Diagnostics, nil, &state, filePath)
 }
 
 func (r *claudeGlobalConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Import uses a fixed ID since there's only one global config
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), "claude-global-config")...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, []byte("true"))...)
 }
 
 func (r *claudeGlobalConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -208,11 +235,65 @@ func (r *claudeGlobalConfigResource) Configure(_ context.Context, req resource.C
 }
 
 func (r *claudeGlobalConfigResource) getGlobalConfigFilePath() string {
-	homeDir, err := os.UserHomeDir()
+	return claudeStateFilePath()
+}
+
+// renderConfig returns the keys managed by model: the typed attributes, the user-scope MCP
+// servers and extra_settings.
+func (r *claudeGlobalConfigResource) renderConfig(ctx context.Context, diags *diag.Diagnostics, model *claudeGlobalConfigResourceModel) (map[string]interface{}, error) {
+	config := r.modelToConfig(model)
+	servers, err := claudeMCPServersValue(ctx, diags, model.McpServers)
+	if err != nil {
+		return nil, err
+	}
+	if servers != nil {
+		config["mcpServers"] = servers
+	}
+	return mergeExtraSettings(config, model.ExtraSettings)
+}
+
+// writeConfig replaces the keys managed by prior with those managed by plan and writes the file,
+// preserving every other key. A nil plan removes the managed keys.
+func (r *claudeGlobalConfigResource) writeConfig(ctx context.Context, diags *diag.Diagnostics, plan, prior *claudeGlobalConfigResourceModel, filePath string) {
+	existing, err := readSettingsJSON(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		diags.AddError("Failed to read global config file", err.Error())
+		return
+	}
+	if existing == nil {
+		if plan == nil {
+			return
+		}
+		existing = make(map[string]interface{})
+	}
+
+	var previous, desired map[string]interface{}
+	if prior != nil {
+		if previous, err = r.renderConfig(ctx, diags, prior); err != nil {
+			diags.AddError("Failed to create global config data", err.Error())
+			return
+		}
+	}
+	opts := prior
+	if plan != nil {
+		if desired, err = r.renderConfig(ctx, diags, plan); err != nil {
+			diags.AddError("Failed to create global config data", err.Error())
+			return
+		}
+		opts = plan
+	}
+	if diags.HasError() {
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(applyOwnedSettings(existing, previous, desired), "", "  ")
 	if err != nil {
-		return ""
+		diags.AddError("Failed to marshal JSON", err.Error())
+		return
+	}
+	if err := opts.writeFile(filePath, jsonData, 0600); err != nil {
+		diags.AddError("Failed to write global config file", err.Error())
 	}
-	return filepath.Join(homeDir, ".claude", "config.json")
 }
 
 func (r *claudeGlobalConfigResource) modelToConfig(model *claudeGlobalConfigResourceModel) map[string]interface{} {
@@ -234,28 +315,25 @@ func (r *claudeGlobalConfigResource) modelToConfig(model *claudeGlobalConfigReso
 	return config
 }
 
-func (r *claudeGlobalConfigResource) configToModel(model *claudeGlobalConfigResourceModel, config map[string]interface{}) {
-	if val, ok := config["autoUpdates"].(bool); ok {
-		model.AutoUpdates = types.BoolValue(val)
-	} else {
-		model.AutoUpdates = types.BoolNull()
+// configToModel refreshes the attributes of model that are set from config. Claude Code writes
+// preferences such as theme itself, so unset attributes stay null unless ownAll is set.
+func (r *claudeGlobalConfigResource) configToModel(model *claudeGlobalConfigResourceModel, config map[string]interface{}, ownAll bool) {
+	// Keys owned by extra_settings are refreshed there and hidden from the typed attributes
+	if extra, err := parseExtraSettings(model.ExtraSettings); err == nil && extra != nil {
+		model.ExtraSettings = refreshExtraSettings(model.ExtraSettings, config)
+		config = stripExtraSettings(config, extra)
 	}
 
-	if val, ok := config["preferredNotifChannel"].(string); ok {
-		model.PreferredNotifChannel = types.StringValue(val)
-	} else {
-		model.PreferredNotifChannel = types.StringNull()
+	if ownAll || !model.AutoUpdates.IsNull() {
+		model.AutoUpdates = jsonBoolValue(config, "autoUpdates")
 	}
-
-	if val, ok := config["theme"].(string); ok {
-		model.Theme = types.StringValue(val)
-	} else {
-		model.Theme = types.StringNull()
+	if ownAll || !model.PreferredNotifChannel.IsNull() {
+		model.PreferredNotifChannel = jsonStringValue(config, "preferredNotifChannel")
 	}
-
-	if val, ok := config["verbose"].(bool); ok {
-		model.Verbose = types.BoolValue(val)
-	} else {
-		model.Verbose = types.BoolNull()
+	if ownAll || !model.Theme.IsNull() {
+		model.Theme = jsonStringValue(config, "theme")
+	}
+	if ownAll || !model.Verbose.IsNull() {
+		model.Verbose = jsonBoolValue(config, "verbose")
 	}
 }
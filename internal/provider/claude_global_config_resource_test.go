@@ -1,32 +1,116 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestClaudeGlobalConfigResource_getGlobalConfigFilePath(t *testing.T) {
 	r := &claudeGlobalConfigResource{}
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
 
 	result := r.getGlobalConfigFilePath()
 
-	// Should return a valid path to ~/.claude/global-config.json
+	// Should return a valid path to ~/.claude.json
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		t.Skip("Cannot get user home directory, skipping test")
 		return
 	}
 
-	expected := filepath.Join(homeDir, ".claude", "global-config.json")
+	expected := filepath.Join(homeDir, ".claude.json")
 	if result != expected {
 		t.Errorf("Expected path %q, got %q", expected, result)
 	}
+
+	// CLAUDE_CONFIG_DIR relocates the state file
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	if result := r.getGlobalConfigFilePath(); result != filepath.Join(configDir, ".claude.json") {
+		t.Errorf("Expected path in CLAUDE_CONFIG_DIR, got %q", result)
+	}
+}
+
+func TestClaudeGlobalConfigResource_writeConfig(t *testing.T) {
+	r := &claudeGlobalConfigResource{}
+	ctx := context.Background()
+	configPath := filepath.Join(t.TempDir(), ".claude.json")
+
+	// Claude Code keeps runtime state and servers added with `claude mcp add` in the same file
+	existing := `{"numStartups":12,"oauthAccount":{"emailAddress":"dev@example.com"},"theme":"light","mcpServers":{"manual":{"type":"stdio","command":"manual-server"}},"projects":{"/src/app":{"allowedTools":[]}}}`
+	if err := os.WriteFile(configPath, []byte(existing), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	servers, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{
+		"memory": `{"command":"npx","args":["-y","@modelcontextprotocol/server-memory"],"transport":"stdio"}`,
+	})
+	plan := claudeGlobalConfigResourceModel{
+		AutoUpdates: types.BoolValue(true),
+		Theme:       types.StringValue("dark"),
+		Verbose:     types.BoolValue(false),
+		McpServers:  servers,
+	}
+
+	var diags diag.Diagnostics
+	r.writeConfig(ctx, &diags, &plan, nil, configPath)
+	if diags.HasError() {
+		t.Fatalf("Failed to write config: %v", diags.Errors())
+	}
+
+	config, err := readSettingsJSON(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if config["theme"] != "dark" || config["numStartups"] != float64(12) {
+		t.Errorf("Expected theme to be set and runtime state kept, got %v", config)
+	}
+	if _, ok := config["projects"].(map[string]interface{})["/src/app"]; !ok {
+		t.Error("Expected project entries to be kept")
+	}
+	mcpServers := config["mcpServers"].(map[string]interface{})
+	if len(mcpServers) != 2 || mcpServers["memory"].(map[string]interface{})["type"] != "stdio" {
+		t.Errorf("Expected managed server next to the manual one, got %v", mcpServers)
+	}
+	if info, err := os.Stat(configPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// Only servers owned by the prior state are refreshed
+	state := plan
+	state.McpServers = refreshOwnedMCPServers(ctx, &diags, plan.McpServers, config)
+	if len(state.McpServers.Elements()) != 1 {
+		t.Errorf("Expected only the managed server to be refreshed, got %v", state.McpServers)
+	}
+
+	// Removing the resource strips the managed keys and keeps the file
+	r.writeConfig(ctx, &diags, nil, &plan, configPath)
+	if diags.HasError() {
+		t.Fatalf("Failed to remove config: %v", diags.Errors())
+	}
+	config, err = readSettingsJSON(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if _, ok := config["theme"]; ok {
+		t.Error("Expected theme to be removed")
+	}
+	if _, ok := config["oauthAccount"]; !ok {
+		t.Error("Expected runtime state to be kept")
+	}
+	mcpServers = config["mcpServers"].(map[string]interface{})
+	if _, ok := mcpServers["memory"]; ok || mcpServers["manual"] == nil {
+		t.Errorf("Expected only the managed server to be removed, got %v", mcpServers)
+	}
 }
 
 func TestClaudeGlobalConfigResource_modelToConfig(t *testing.T) {
@@ -167,10 +251,20 @@ func TestClaudeGlobalConfigResource_configToModel(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			var model claudeGlobalConfigResourceModel
-			r.configToModel(&model, tc.config)
+			r.configToModel(&model, tc.config, true)
 			tc.validate(t, &model)
 		})
 	}
+
+	// Values written by Claude Code are not adopted by unset attributes
+	model := claudeGlobalConfigResourceModel{Verbose: types.BoolValue(true)}
+	r.configToModel(&model, map[string]interface{}{"theme": "dark", "verbose": false}, false)
+	if !model.Theme.IsNull() || !model.AutoUpdates.IsNull() {
+		t.Errorf("Expected unset attributes to stay null, got %+v", model)
+	}
+	if model.Verbose.IsNull() || model.Verbose.ValueBool() {
+		t.Errorf("Expected drift in verbose to be reported, got %v", model.Verbose)
+	}
 }
 
 func TestClaudeGlobalConfigResource_JSONOperations(t *testing.T) {
@@ -337,6 +431,13 @@ func TestAccClaudeGlobalConfigResource_basic(t *testing.T) {
 	workDir := testAccGlobalConfigWorkDir()
 	defer os.RemoveAll(workDir)
 
+	// Keep the test away from the real ~/.claude.json and seed it with runtime state
+	t.Setenv("CLAUDE_CONFIG_DIR", workDir)
+	configPath := filepath.Join(workDir, ".claude.json")
+	if err := os.WriteFile(configPath, []byte(`{"numStartups":3,"mcpServers":{"manual":{"type":"stdio","command":"manual-server"}}}`), 0600); err != nil {
+		t.Fatalf("Failed to seed config file: %v", err)
+	}
+
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
@@ -348,7 +449,9 @@ func TestAccClaudeGlobalConfigResource_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("agentsmith_claude_global_config.test", "preferred_notif_channel", "iterm2"),
 					resource.TestCheckResourceAttr("agentsmith_claude_global_config.test", "verbose", "true"),
 					resource.TestCheckResourceAttr("agentsmith_claude_global_config.test", "auto_updates", "false"),
+					resource.TestCheckResourceAttr("agentsmith_claude_global_config.test", "mcp_servers.%", "1"),
 					resource.TestCheckResourceAttrSet("agentsmith_claude_global_config.test", "id"),
+					testAccCheckClaudeStateFileKept(configPath),
 				),
 			},
 			// ImportState testing
@@ -356,6 +459,8 @@ func TestAccClaudeGlobalConfigResource_basic(t *testing.T) {
 				ResourceName:      "agentsmith_claude_global_config.test",
 				ImportState:       true,
 				ImportStateVerify: true,
+				// Imported resources do not take ownership of any MCP server
+				ImportStateVerifyIgnore: []string{"mcp_servers"},
 			},
 			// Update and Read testing
 			{
@@ -365,24 +470,53 @@ func TestAccClaudeGlobalConfigResource_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("agentsmith_claude_global_config.test", "preferred_notif_channel", "terminal_bell"),
 					resource.TestCheckResourceAttr("agentsmith_claude_global_config.test", "verbose", "false"),
 					resource.TestCheckResourceAttr("agentsmith_claude_global_config.test", "auto_updates", "true"),
+					testAccCheckClaudeStateFileKept(configPath),
 				),
 			},
 			// Delete testing automatically occurs in TestCase
 		},
+		CheckDestroy: testAccCheckClaudeStateFileKept(configPath),
 	})
 }
 
+// testAccCheckClaudeStateFileKept checks that keys not managed by the provider survive a write.
+func testAccCheckClaudeStateFileKept(configPath string) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		config, err := readSettingsJSON(configPath)
+		if err != nil {
+			return err
+		}
+		if config["numStartups"] != float64(3) {
+			return fmt.Errorf("expected numStartups to be kept, got %v", config["numStartups"])
+		}
+		servers, _ := config["mcpServers"].(map[string]interface{})
+		if _, ok := servers["manual"]; !ok {
+			return fmt.Errorf("expected the manual MCP server to be kept, got %v", servers)
+		}
+		return nil
+	}
+}
+
 func testAccClaudeGlobalConfigResourceConfig(workDir, theme, notifChannel, verbose, autoUpdates string) string {
 	return fmt.Sprintf(`
 provider "agentsmith" {
   workdir = "%s"
 }
 
+data "agentsmith_mcp_stdio" "memory" {
+  command = "npx"
+  args    = ["-y", "@modelcontextprotocol/server-memory"]
+}
+
 resource "agentsmith_claude_global_config" "test" {
   theme                     = "%s"
   preferred_notif_channel   = "%s"
   verbose                   = %s
   auto_updates              = %s
+
+  mcp_servers = {
+    memory = data.agentsmith_mcp_stdio.memory.json
+  }
 }
 `, workDir, theme, notifChannel, verbose, autoUpdates)
 }
@@ -2,40 +2,55 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-agentsmith/internal/fileio"
 )
 
 var (
-	_ resource.Resource                = &claudeHookResource{}
-	_ resource.ResourceWithConfigure   = &claudeHookResource{}
-	_ resource.ResourceWithImportState = &claudeHookResource{}
+	_ resource.Resource                   = &claudeHookResource{}
+	_ resource.ResourceWithConfigure      = &claudeHookResource{}
+	_ resource.ResourceWithImportState    = &claudeHookResource{}
+	_ resource.ResourceWithValidateConfig = &claudeHookResource{}
 )
 
 func NewClaudeHookResource() resource.Resource {
 	return &claudeHookResource{}
 }
 
+// claudeHookResource writes a hook script and, when an event is configured, owns the single
+// entry that registers it in a settings.json file that other resources and tools may also edit.
 type claudeHookResource struct {
-	client *FileClient
+	client   *FileClient
+	settings claudeSettingsResource
 }
 
 type claudeHookResourceModel struct {
-	ID         types.String `tfsdk:"id"`
-	Scope      types.String `tfsdk:"scope"`
-	Name       types.String `tfsdk:"name"`
-	Content    types.String `tfsdk:"content"`
-	Executable types.Bool   `tfsdk:"executable"`
+	ID            types.String `tfsdk:"id"`
+	Scope         types.String `tfsdk:"scope"`
+	Name          types.String `tfsdk:"name"`
+	Content       types.String `tfsdk:"content"`
+	Executable    types.Bool   `tfsdk:"executable"`
+	Command       types.String `tfsdk:"command"`
+	Event         types.String `tfsdk:"event"`
+	Matcher       types.String `tfsdk:"matcher"`
+	Timeout       types.Int64  `tfsdk:"timeout"`
+	SettingsScope types.String `tfsdk:"settings_scope"`
+	fileWriteOptionsModel
 }
 
 func (r *claudeHookResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -44,7 +59,7 @@ func (r *claudeHookResource) Metadata(_ context.Context, req resource.MetadataRe
 
 func (r *claudeHookResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages a Claude Code hook script. Hooks are custom commands that can be configured to run before or after tool executions.",
+		Description: "Manages a Claude Code hook script. Hooks are custom commands that run at points in the Claude Code lifecycle, such as before or after tool executions. When `event` is set, the script is also registered in a `settings.json` file: the resource adds its own entry under `hooks.<event>` and removes only that entry on destroy, leaving other hooks intact. Do not also manage the same file's hooks with the `hooks` block of `agentsmith_claude_settings`.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "A unique identifier for this hook resource, composed of the scope and name.",
@@ -56,6 +71,7 @@ func (r *claudeHookResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"scope": schema.StringAttribute{
 				Description: "The scope of the hook file. Must be either `user` (for `~/.claude/hooks`) or `project` (for `<workdir>/.claude/hooks`).",
 				Required:    true,
+				Validators:  []validator.String{stringOneOf("user", "project")},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -72,13 +88,59 @@ func (r *claudeHookResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Required:    true,
 			},
 			"executable": schema.BoolAttribute{
-				Description: "Whether to set the executable bit on the hook file. Defaults to `true`.",
+				Description: "Whether to set the executable bit on the hook file. Execute permission is added wherever `file_mode` grants read permission. Defaults to `true`.",
 				Optional:    true,
 				Default:     booldefault.StaticBool(true),
 				Computed:    true,
 			},
+			"command": schema.StringAttribute{
+				Description: "The command that runs the script, as registered in the settings file. Project hooks are referenced through `$CLAUDE_PROJECT_DIR` so the settings file works in any checkout; user hooks by absolute path.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"event": schema.StringAttribute{
+				Description: "The hook event to register the script for. Must be one of `PreToolUse`, `PostToolUse`, `Notification`, `UserPromptSubmit`, `Stop`, `SubagentStop`, `PreCompact`, `SessionStart`, or `SessionEnd`. If unset, the script is written but not registered.",
+				Optional:    true,
+				Validators:  []validator.String{stringOneOf(claudeHookEvents...)},
+			},
+			"matcher": schema.StringAttribute{
+				Description: "A pattern to match tool names (e.g., `Bash` or `Edit|Write`). Only applicable to `PreToolUse` and `PostToolUse`.",
+				Optional:    true,
+			},
+			"timeout": schema.Int64Attribute{
+				Description: "How long the script may run, in seconds, before it is cancelled.",
+				Optional:    true,
+			},
+			"settings_scope": schema.StringAttribute{
+				Description: "The settings file to register the script in. Must be one of `user` (~/.claude/settings.json), `project` (<workdir>/.claude/settings.json), or `local` (<workdir>/.claude/settings.local.json). Defaults to the scope of the hook file.",
+				Optional:    true,
+				Validators:  []validator.String{stringOneOf("user", "project", "local")},
+			},
 		},
 	}
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0644")
+}
+
+func (r *claudeHookResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config claudeHookResourceModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		return
+	}
+
+	if !config.Event.IsNull() {
+		return
+	}
+	for name, v := range map[string]interface{ IsNull() bool }{
+		"matcher":        config.Matcher,
+		"timeout":        config.Timeout,
+		"settings_scope": config.SettingsScope,
+	} {
+		if !v.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root(name), "Missing hook event", fmt.Sprintf("%s only applies when the script is registered; set event as well.", name))
+		}
+	}
 }
 
 func (r *claudeHookResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -95,25 +157,20 @@ func (r *claudeHookResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		resp.Diagnostics.AddError("Failed to create directory", err.Error())
-		return
-	}
-
 	// Write hook file
-	fileMode := os.FileMode(0644)
-	if plan.Executable.ValueBool() {
-		fileMode = 0755
-	}
-
-	if err := os.WriteFile(filePath, []byte(plan.Content.ValueString()), fileMode); err != nil {
+	if err := plan.writeScript(filePath, []byte(plan.Content.ValueString()), plan.Executable.ValueBool()); err != nil {
 		resp.Diagnostics.AddError("Failed to write hook file", err.Error())
 		return
 	}
 
 	// Set computed attributes
 	plan.ID = types.StringValue(fmt.Sprintf("claude-hook-%s-%s", plan.Scope.ValueString(), plan.Name.ValueString()))
+	plan.Command = types.StringValue(r.getHookCommand(plan.Scope.ValueString(), plan.Name.ValueString()))
+
+	r.updateRegistration(&resp.Diagnostics, nil, &plan)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -154,15 +211,42 @@ func (r *claudeHookResource) Read(ctx context.Context, req resource.ReadRequest,
 	// Update state with current file contents and permissions
 	state.Content = types.StringValue(string(data))
 	state.Executable = types.BoolValue(fileInfo.Mode()&0111 != 0) // Check if any execute bit is set
+	state.Command = types.StringValue(r.getHookCommand(state.Scope.ValueString(), state.Name.ValueString()))
+
+	// Only a registration this resource made is refreshed, or any on import; one written by
+	// hand for an unregistered hook is left alone. A registration removed by hand clears
+	// event, so the next plan adds it again.
+	imported, d := req.Private.GetKey(ctx, claudeSettingsImportedKey)
+	resp.Diagnostics.Append(d...)
+	if len(imported) > 0 {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, nil)...)
+	}
+	if len(imported) > 0 || !state.Event.IsNull() {
+		settingsPath := r.getHookSettingsFilePath(&state)
+		settings, err := readSettingsJSON(settingsPath)
+		if err != nil && !os.IsNotExist(err) {
+			resp.Diagnostics.AddError("Failed to read settings file", err.Error())
+			return
+		}
+		if event, entry, cmd, ok := findHookRegistration(settings, state.Command.ValueString()); ok {
+			state.Event = types.StringValue(event)
+			state.Matcher = jsonStringValue(entry, "matcher")
+			state.Timeout = jsonInt64Value(cmd, "timeout")
+		} else {
+			state.Event = types.StringNull()
+			state.Matcher = types.StringNull()
+			state.Timeout = types.Int64Null()
+		}
+	}
 
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
 }
 
 func (r *claudeHookResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan claudeHookResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	var plan, state claudeHookResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -174,17 +258,17 @@ func (r *claudeHookResource) Update(ctx context.Context, req resource.UpdateRequ
 	}
 
 	// Write hook file
-	fileMode := os.FileMode(0644)
-	if plan.Executable.ValueBool() {
-		fileMode = 0755
+	if err := plan.writeScript(filePath, []byte(plan.Content.ValueString()), plan.Executable.ValueBool()); err != nil {
+		resp.Diagnostics.AddError("Failed to write hook file", err.Error())
+		return
 	}
 
-	if err := os.WriteFile(filePath, []byte(plan.Content.ValueString()), fileMode); err != nil {
-		resp.Diagnostics.AddError("Failed to write hook file", err.Error())
+	r.updateRegistration(&resp.Diagnostics, &state, &plan)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	diags = resp.State.Set(ctx, plan)
+	diags := resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 }
 
@@ -202,6 +286,11 @@ func (r *claudeHookResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	r.updateRegistration(&resp.Diagnostics, &state, nil)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
 		resp.Diagnostics.AddError("Failed to delete hook file", err.Error())
 		return
@@ -232,6 +321,7 @@ func (r *claudeHookResource) ImportState(ctx context.Context, req resource.Impor
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("scope"), scope)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("claude-hook-%s-%s", scope, name))...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, []byte("true"))...)
 }
 
 func (r *claudeHookResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -249,6 +339,7 @@ func (r *claudeHookResource) Configure(_ context.Context, req resource.Configure
 	}
 
 	r.client = client
+	r.settings.client = client
 }
 
 func (r *claudeHookResource) getHookFilePath(scope, name string) string {
@@ -268,3 +359,234 @@ func (r *claudeHookResource) getHookFilePath(scope, name string) string {
 		return ""
 	}
 }
+
+// getHookCommand returns the command that runs the hook script. Project hooks go through
+// $CLAUDE_PROJECT_DIR, which Claude Code sets to the project root, so project settings that
+// are checked in do not depend on where the repository is cloned.
+func (r *claudeHookResource) getHookCommand(scope, name string) string {
+	if scope == "project" {
+		return `"$CLAUDE_PROJECT_DIR"/` + shellQuote(".claude/hooks/"+name)
+	}
+	return shellQuote(r.getHookFilePath(scope, name))
+}
+
+// getHookSettingsFilePath returns the settings file the hook is registered in: settings_scope,
+// or the scope of the hook file when it is unset.
+func (r *claudeHookResource) getHookSettingsFilePath(model *claudeHookResourceModel) string {
+	scope := model.Scope.ValueString()
+	if !model.SettingsScope.IsNull() {
+		scope = model.SettingsScope.ValueString()
+	}
+	return r.settings.getSettingsFilePath(scope)
+}
+
+// updateRegistration moves the hook's settings entry from prior to plan. Either may be nil, and
+// a model without an event has no entry. Each settings file involved is edited once, under its
+// lock.
+func (r *claudeHookResource) updateRegistration(diags *diag.Diagnostics, prior, plan *claudeHookResourceModel) {
+	if prior != nil && plan != nil && r.sameHookRegistration(prior, plan) {
+		return
+	}
+
+	var files []string
+	for _, m := range []*claudeHookResourceModel{prior, plan} {
+		if m == nil || m.Event.IsNull() {
+			continue
+		}
+		filePath := r.getHookSettingsFilePath(m)
+		if filePath == "" {
+			diags.AddError("Invalid settings scope", "Settings scope must be 'user', 'project', or 'local'")
+			return
+		}
+		if len(files) == 0 || files[0] != filePath {
+			files = append(files, filePath)
+		}
+	}
+
+	for _, filePath := range files {
+		r.updateSettingsFile(diags, prior, plan, filePath)
+		if diags.HasError() {
+			return
+		}
+	}
+}
+
+func (r *claudeHookResource) updateSettingsFile(diags *diag.Diagnostics, prior, plan *claudeHookResourceModel, filePath string) {
+	defer fileio.Lock(filePath)()
+
+	settings, err := readSettingsJSON(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		diags.AddError("Failed to read settings file", err.Error())
+		return
+	}
+	if settings == nil {
+		if plan == nil || plan.Event.IsNull() || r.getHookSettingsFilePath(plan) != filePath {
+			return
+		}
+		settings = make(map[string]interface{})
+	}
+
+	changed := false
+	if prior != nil && !prior.Event.IsNull() && r.getHookSettingsFilePath(prior) == filePath {
+		changed = unregisterHook(settings, prior.Command.ValueString())
+	}
+	if plan != nil && !plan.Event.IsNull() && r.getHookSettingsFilePath(plan) == filePath {
+		timeout := int64(0)
+		if !plan.Timeout.IsNull() {
+			timeout = plan.Timeout.ValueInt64()
+		}
+		if err := registerHook(settings, plan.Event.ValueString(), plan.Matcher.ValueString(), plan.Command.ValueString(), timeout); err != nil {
+			diags.AddError("Failed to register hook", err.Error())
+			return
+		}
+		changed = true
+	}
+	if !changed {
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		diags.AddError("Failed to marshal JSON", err.Error())
+		return
+	}
+	// file_mode applies to the script; the settings file is written with the usual settings mode.
+	owner := plan
+	if owner == nil {
+		owner = prior
+	}
+	opts := fileWriteOptionsModel{CreateDirectories: owner.CreateDirectories, BackupOnWrite: owner.BackupOnWrite}
+	if err := opts.writeFile(filePath, jsonData, 0644); err != nil {
+		diags.AddError("Failed to write settings file", err.Error())
+	}
+}
+
+// sameHookRegistration reports whether a and b register the same command with the same
+// settings in the same file.
+func (r *claudeHookResource) sameHookRegistration(a, b *claudeHookResourceModel) bool {
+	return a.Event.Equal(b.Event) && a.Matcher.Equal(b.Matcher) && a.Timeout.Equal(b.Timeout) &&
+		a.Command.Equal(b.Command) && r.getHookSettingsFilePath(a) == r.getHookSettingsFilePath(b)
+}
+
+// findHookRegistration returns the event, the settings.hooks.<event> entry and the hook within
+// it that run command.
+func findHookRegistration(settings map[string]interface{}, command string) (string, map[string]interface{}, map[string]interface{}, bool) {
+	events, _ := settings["hooks"].(map[string]interface{})
+	names := make([]string, 0, len(events))
+	for event := range events {
+		names = append(names, event)
+	}
+	sort.Strings(names)
+
+	for _, event := range names {
+		entries, _ := events[event].([]interface{})
+		for _, e := range entries {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			commands, _ := entry["hooks"].([]interface{})
+			for _, c := range commands {
+				if cmd, ok := c.(map[string]interface{}); ok && cmd["command"] == command {
+					return event, entry, cmd, true
+				}
+			}
+		}
+	}
+	return "", nil, nil, false
+}
+
+// registerHook appends an entry running command to settings.hooks.<event>. A timeout of zero
+// is omitted.
+func registerHook(settings map[string]interface{}, event, matcher, command string, timeout int64) error {
+	events, ok := settings["hooks"].(map[string]interface{})
+	if !ok {
+		if _, exists := settings["hooks"]; exists {
+			return fmt.Errorf("hooks in the settings file is not an object")
+		}
+		events = make(map[string]interface{})
+		settings["hooks"] = events
+	}
+	entries, ok := events[event].([]interface{})
+	if !ok && events[event] != nil {
+		return fmt.Errorf("hooks.%s in the settings file is not a list", event)
+	}
+
+	cmd := map[string]interface{}{"type": "command", "command": command}
+	if timeout > 0 {
+		cmd["timeout"] = timeout
+	}
+	entry := map[string]interface{}{"hooks": []interface{}{cmd}}
+	if matcher != "" {
+		entry["matcher"] = matcher
+	}
+	events[event] = append(entries, entry)
+	return nil
+}
+
+// unregisterHook removes every hook running command from settings.hooks, dropping the entries,
+// events and hooks object it empties. It reports whether settings changed.
+func unregisterHook(settings map[string]interface{}, command string) bool {
+	events, ok := settings["hooks"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	changed := false
+	for event, v := range events {
+		entries, ok := v.([]interface{})
+		if !ok {
+			continue
+		}
+		removed := false
+		keptEntries := make([]interface{}, 0, len(entries))
+		for _, e := range entries {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				keptEntries = append(keptEntries, e)
+				continue
+			}
+			commands, _ := entry["hooks"].([]interface{})
+			kept := make([]interface{}, 0, len(commands))
+			for _, c := range commands {
+				if cmd, ok := c.(map[string]interface{}); ok && cmd["command"] == command {
+					continue
+				}
+				kept = append(kept, c)
+			}
+			if len(kept) == len(commands) {
+				keptEntries = append(keptEntries, e)
+				continue
+			}
+			removed = true
+			if len(kept) > 0 {
+				entry["hooks"] = kept
+				keptEntries = append(keptEntries, entry)
+			}
+		}
+		if !removed {
+			continue
+		}
+		changed = true
+		if len(keptEntries) > 0 {
+			events[event] = keptEntries
+		} else {
+			delete(events, event)
+		}
+	}
+	if changed && len(events) == 0 {
+		delete(settings, "hooks")
+	}
+	return changed
+}
+
+// shellQuote single-quotes s for a POSIX shell unless it only contains characters that need
+// no quoting.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(c rune) bool {
+		return !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("/._-+=:,@", c))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
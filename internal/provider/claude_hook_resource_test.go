@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestClaudeHookResource_getHookFilePath(t *testing.T) {
@@ -56,6 +58,145 @@ func TestClaudeHookResource_getHookFilePath(t *testing.T) {
 	}
 }
 
+func TestClaudeHookResource_getHookCommand(t *testing.T) {
+	r := &claudeHookResource{client: &FileClient{workDir: "/work"}}
+
+	testCases := []struct {
+		name     string
+		scope    string
+		hookName string
+		expected string
+	}{
+		{
+			name:     "project_scope",
+			scope:    "project",
+			hookName: "lint.sh",
+			expected: `"$CLAUDE_PROJECT_DIR"/.claude/hooks/lint.sh`,
+		},
+		{
+			name:     "quoted_name",
+			scope:    "project",
+			hookName: "it's lint",
+			expected: `"$CLAUDE_PROJECT_DIR"/'.claude/hooks/it'\''s lint'`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := r.getHookCommand(tc.scope, tc.hookName)
+			if result != tc.expected {
+				t.Errorf("Expected command %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestClaudeHookResource_registerHook(t *testing.T) {
+	testCases := []struct {
+		name        string
+		settings    string
+		event       string
+		matcher     string
+		timeout     int64
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "empty_file",
+			settings: `{}`,
+			event:    "PostToolUse",
+			matcher:  "Edit|Write",
+			timeout:  30,
+			expected: `{"hooks":{"PostToolUse":[{"hooks":[{"command":"/hooks/lint","timeout":30,"type":"command"}],"matcher":"Edit|Write"}]}}`,
+		},
+		{
+			name:     "keeps_other_hooks",
+			settings: `{"hooks":{"Stop":[{"hooks":[{"type":"command","command":"notify"}]}]},"model":"opus"}`,
+			event:    "Stop",
+			expected: `{"hooks":{"Stop":[{"hooks":[{"command":"notify","type":"command"}]},{"hooks":[{"command":"/hooks/lint","type":"command"}]}]},"model":"opus"}`,
+		},
+		{
+			name:        "hooks_not_an_object",
+			settings:    `{"hooks":[]}`,
+			event:       "Stop",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var settings map[string]interface{}
+			if err := json.Unmarshal([]byte(tc.settings), &settings); err != nil {
+				t.Fatalf("Failed to parse settings: %v", err)
+			}
+
+			err := registerHook(settings, tc.event, tc.matcher, "/hooks/lint", tc.timeout)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got, _ := json.Marshal(settings)
+			if string(got) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+			if event, _, _, ok := findHookRegistration(settings, "/hooks/lint"); !ok || event != tc.event {
+				t.Errorf("Expected registration for %s, got %q", tc.event, event)
+			}
+		})
+	}
+}
+
+func TestClaudeHookResource_unregisterHook(t *testing.T) {
+	testCases := []struct {
+		name          string
+		settings      string
+		expected      string
+		expectChanged bool
+	}{
+		{
+			name:          "drops_empty_hooks",
+			settings:      `{"hooks":{"Stop":[{"hooks":[{"type":"command","command":"/hooks/lint"}]}]},"model":"opus"}`,
+			expected:      `{"model":"opus"}`,
+			expectChanged: true,
+		},
+		{
+			name:          "keeps_shared_entry",
+			settings:      `{"hooks":{"PreToolUse":[{"matcher":"Bash","hooks":[{"type":"command","command":"/hooks/lint"},{"type":"command","command":"audit"}]}],"Stop":[]}}`,
+			expected:      `{"hooks":{"PreToolUse":[{"hooks":[{"command":"audit","type":"command"}],"matcher":"Bash"}],"Stop":[]}}`,
+			expectChanged: true,
+		},
+		{
+			name:     "not_registered",
+			settings: `{"hooks":{"Stop":[{"hooks":[{"type":"command","command":"notify"}]}]}}`,
+			expected: `{"hooks":{"Stop":[{"hooks":[{"command":"notify","type":"command"}]}]}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var settings map[string]interface{}
+			if err := json.Unmarshal([]byte(tc.settings), &settings); err != nil {
+				t.Fatalf("Failed to parse settings: %v", err)
+			}
+
+			changed := unregisterHook(settings, "/hooks/lint")
+			if changed != tc.expectChanged {
+				t.Errorf("Expected changed=%v, got %v", tc.expectChanged, changed)
+			}
+			got, _ := json.Marshal(settings)
+			if string(got) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
 func TestClaudeHookResource_FileOperations(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -409,6 +550,149 @@ func TestAccClaudeHookResource_basic(t *testing.T) {
 	})
 }
 
+func TestAccClaudeHookResource_registered(t *testing.T) {
+	workDir := t.TempDir()
+	settingsPath := filepath.Join(workDir, ".claude", "settings.json")
+	command := `"$CLAUDE_PROJECT_DIR"/.claude/hooks/lint.sh`
+
+	// Hooks registered by other tools are left alone
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatalf("Failed to create settings directory: %v", err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"hooks":{"Stop":[{"hooks":[{"type":"command","command":"notify-send done"}]}]}}`), 0644); err != nil {
+		t.Fatalf("Failed to write settings file: %v", err)
+	}
+
+	checkRegistration := func(event string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			settings, err := readSettingsJSON(settingsPath)
+			if err != nil {
+				return err
+			}
+			if _, _, _, ok := findHookRegistration(settings, "notify-send done"); !ok {
+				return fmt.Errorf("expected the Stop hook to be kept, got %v", settings["hooks"])
+			}
+			got, _, _, ok := findHookRegistration(settings, command)
+			if event == "" && ok {
+				return fmt.Errorf("expected the hook to be unregistered, got %v", settings["hooks"])
+			}
+			if event != "" && got != event {
+				return fmt.Errorf("expected the hook to be registered for %s, got %v", event, settings["hooks"])
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkRegistration(""),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccClaudeHookResourceRegisteredConfig(workDir, "PostToolUse", "Edit|Write"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_hook.test", "command", command),
+					resource.TestCheckResourceAttr("agentsmith_claude_hook.test", "timeout", "30"),
+					checkRegistration("PostToolUse"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "agentsmith_claude_hook.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "project:lint.sh",
+			},
+			// A registration removed by hand is added again
+			{
+				PreConfig: func() {
+					settings, err := readSettingsJSON(settingsPath)
+					if err != nil {
+						t.Fatalf("Failed to read settings file: %v", err)
+					}
+					unregisterHook(settings, command)
+					data, _ := json.MarshalIndent(settings, "", "  ")
+					if err := os.WriteFile(settingsPath, data, 0644); err != nil {
+						t.Fatalf("Failed to write settings file: %v", err)
+					}
+				},
+				Config:             testAccClaudeHookResourceRegisteredConfig(workDir, "PostToolUse", "Edit|Write"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccClaudeHookResourceRegisteredConfig(workDir, "PreToolUse", "Bash"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_hook.test", "event", "PreToolUse"),
+					checkRegistration("PreToolUse"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccClaudeHookResource_handWrittenRegistration(t *testing.T) {
+	workDir := t.TempDir()
+	settingsPath := filepath.Join(workDir, ".claude", "settings.json")
+	command := `"$CLAUDE_PROJECT_DIR"/.claude/hooks/lint.sh`
+
+	// A registration written by hand for a hook without event belongs to its author
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatalf("Failed to create settings directory: %v", err)
+	}
+	settings := map[string]interface{}{}
+	if err := registerHook(settings, "Stop", "", command, 0); err != nil {
+		t.Fatalf("Failed to register hook: %v", err)
+	}
+	data, _ := json.MarshalIndent(settings, "", "  ")
+	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write settings file: %v", err)
+	}
+
+	checkKept := func(*terraform.State) error {
+		settings, err := readSettingsJSON(settingsPath)
+		if err != nil {
+			return err
+		}
+		if event, _, _, ok := findHookRegistration(settings, command); !ok || event != "Stop" {
+			return fmt.Errorf("expected the hand-written registration to be kept, got %v", settings["hooks"])
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkKept,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccClaudeHookResourceConfig(workDir, "project", "lint.sh", "#!/bin/sh\\nmake lint", "true"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("agentsmith_claude_hook.test", "event"),
+					checkKept,
+				),
+			},
+		},
+	})
+}
+
+func testAccClaudeHookResourceRegisteredConfig(workDir, event, matcher string) string {
+	return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_claude_hook" "test" {
+  scope   = "project"
+  name    = "lint.sh"
+  content = "#!/bin/sh\nmake lint\n"
+  event   = %q
+  matcher = %q
+  timeout = 30
+}
+`, workDir, event, matcher)
+}
+
 func testAccClaudeHookResourceConfig(workDir, scope, name, content, executable string) string {
 	return fmt.Sprintf(`
 provider "agentsmith" {
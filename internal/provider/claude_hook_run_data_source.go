@@ -0,0 +1,396 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                   = &claudeHookRunDataSource{}
+	_ datasource.DataSourceWithConfigure      = &claudeHookRunDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &claudeHookRunDataSource{}
+)
+
+// claudeHookRunSessionID is the session_id sent to hooks run by this data source, so hooks can
+// tell a test run from a real session.
+const claudeHookRunSessionID = "agentsmith-hook-run"
+
+func NewClaudeHookRunDataSource() datasource.DataSource {
+	return &claudeHookRunDataSource{}
+}
+
+// claudeHookRunDataSource runs a hook command against a synthetic event, the way Claude Code
+// would, and exposes the result.
+type claudeHookRunDataSource struct {
+	client *FileClient
+}
+
+type claudeHookRunDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Command      types.String `tfsdk:"command"`
+	Event        types.String `tfsdk:"event"`
+	ToolName     types.String `tfsdk:"tool_name"`
+	ToolInput    types.String `tfsdk:"tool_input"`
+	ToolResponse types.String `tfsdk:"tool_response"`
+	Prompt       types.String `tfsdk:"prompt"`
+	Message      types.String `tfsdk:"message"`
+	Payload      types.String `tfsdk:"payload"`
+	Env          types.Map    `tfsdk:"env"`
+	Timeout      types.Int64  `tfsdk:"timeout"`
+	Input        types.String `tfsdk:"input"`
+	ExitCode     types.Int64  `tfsdk:"exit_code"`
+	TimedOut     types.Bool   `tfsdk:"timed_out"`
+	Stdout       types.String `tfsdk:"stdout"`
+	Stderr       types.String `tfsdk:"stderr"`
+	OutputJSON   types.String `tfsdk:"output_json"`
+	Decision     types.String `tfsdk:"decision"`
+	Reason       types.String `tfsdk:"reason"`
+	Blocked      types.Bool   `tfsdk:"blocked"`
+}
+
+// claudeHookRunResult is the outcome of running a hook command.
+type claudeHookRunResult struct {
+	ExitCode int
+	TimedOut bool
+	Stdout   string
+	Stderr   string
+}
+
+func (d *claudeHookRunDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claude_hook_run"
+}
+
+func (d *claudeHookRunDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs a Claude Code hook command locally against a synthetic event and exposes the result, so `check` blocks can assert how a hook behaves before it is rolled out. The event JSON is piped to the command's stdin, as Claude Code does. The command runs with `sh -c` in the provider's working directory, with `CLAUDE_PROJECT_DIR` set to it. The hook runs on every read, so it should not have side effects.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "A unique identifier for the run, derived from the command and input.",
+				Computed:    true,
+			},
+			"command": schema.StringAttribute{
+				Description: "The hook command to run, e.g. the `command` attribute of an `agentsmith_claude_hook` resource.",
+				Required:    true,
+			},
+			"event": schema.StringAttribute{
+				Description: "The hook event to simulate. Must be one of `PreToolUse`, `PostToolUse`, `Notification`, `UserPromptSubmit`, `Stop`, `SubagentStop`, `PreCompact`, `SessionStart`, or `SessionEnd`.",
+				Required:    true,
+				Validators:  []validator.String{stringOneOf(claudeHookEvents...)},
+			},
+			"tool_name": schema.StringAttribute{
+				Description: "The tool name of a `PreToolUse` or `PostToolUse` event, e.g. `Bash`.",
+				Optional:    true,
+			},
+			"tool_input": schema.StringAttribute{
+				Description: "The tool input of a `PreToolUse` or `PostToolUse` event, as a JSON object, e.g. `jsonencode({ command = \"rm -rf /\" })`.",
+				Optional:    true,
+				Validators:  []validator.String{jsonObject()},
+			},
+			"tool_response": schema.StringAttribute{
+				Description: "The tool response of a `PostToolUse` event, as a JSON object.",
+				Optional:    true,
+				Validators:  []validator.String{jsonObject()},
+			},
+			"prompt": schema.StringAttribute{
+				Description: "The prompt of a `UserPromptSubmit` event.",
+				Optional:    true,
+			},
+			"message": schema.StringAttribute{
+				Description: "The message of a `Notification` event.",
+				Optional:    true,
+			},
+			"payload": schema.StringAttribute{
+				Description: "Additional event fields as a JSON object, merged over the generated event.",
+				Optional:    true,
+				Validators:  []validator.String{jsonObject()},
+			},
+			"env": schema.MapAttribute{
+				Description: "Additional environment variables for the command.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"timeout": schema.Int64Attribute{
+				Description: "How long the command may run, in seconds, before it is killed. Defaults to `60`, as in Claude Code.",
+				Optional:    true,
+			},
+			"input": schema.StringAttribute{
+				Description: "The event JSON piped to the command.",
+				Computed:    true,
+			},
+			"exit_code": schema.Int64Attribute{
+				Description: "The exit code of the command, or `-1` if it timed out. Claude Code treats `2` as a blocking error.",
+				Computed:    true,
+			},
+			"timed_out": schema.BoolAttribute{
+				Description: "Whether the command was killed after `timeout`.",
+				Computed:    true,
+			},
+			"stdout": schema.StringAttribute{
+				Description: "The standard output of the command.",
+				Computed:    true,
+			},
+			"stderr": schema.StringAttribute{
+				Description: "The standard error of the command.",
+				Computed:    true,
+			},
+			"output_json": schema.StringAttribute{
+				Description: "The standard output as normalized JSON, or null if it is not a JSON object.",
+				Computed:    true,
+			},
+			"decision": schema.StringAttribute{
+				Description: "The decision in the JSON output: `hookSpecificOutput.permissionDecision` (`allow`, `deny` or `ask`) if present, otherwise the top-level `decision` (e.g. `block`). Null if the output has neither.",
+				Computed:    true,
+			},
+			"reason": schema.StringAttribute{
+				Description: "The reason for the decision, or the standard error when the command exits with `2`.",
+				Computed:    true,
+			},
+			"blocked": schema.BoolAttribute{
+				Description: "Whether Claude Code would block the action: the command exited with `2`, decided `deny` or `block`, or returned `\"continue\": false`.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *claudeHookRunDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config claudeHookRunDataSourceModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		return
+	}
+
+	event := config.Event.ValueString()
+	if (event == "PreToolUse" || event == "PostToolUse") && config.ToolName.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("tool_name"), "Missing tool name", fmt.Sprintf("%s events require tool_name.", event))
+	}
+	if !config.Timeout.IsNull() && !config.Timeout.IsUnknown() && config.Timeout.ValueInt64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("timeout"), "Invalid timeout", "timeout must be a positive number of seconds.")
+	}
+}
+
+func (d *claudeHookRunDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data claudeHookRunDataSourceModel
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workDir := "."
+	if d.client != nil {
+		workDir = d.client.workDir
+	}
+
+	input, err := claudeHookEventJSON(&data, workDir)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid hook event", err.Error())
+		return
+	}
+
+	env := []string{"CLAUDE_PROJECT_DIR=" + workDir}
+	if !data.Env.IsNull() {
+		var extra map[string]string
+		resp.Diagnostics.Append(data.Env.ElementsAs(ctx, &extra, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		for k, v := range extra {
+			env = append(env, k+"="+v)
+		}
+	}
+
+	timeout := 60 * time.Second
+	if !data.Timeout.IsNull() {
+		timeout = time.Duration(data.Timeout.ValueInt64()) * time.Second
+	}
+
+	result, err := runClaudeHook(ctx, data.Command.ValueString(), workDir, env, input, timeout)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to run hook", err.Error())
+		return
+	}
+
+	data.Input = types.StringValue(string(input))
+	data.ExitCode = types.Int64Value(int64(result.ExitCode))
+	data.TimedOut = types.BoolValue(result.TimedOut)
+	data.Stdout = types.StringValue(result.Stdout)
+	data.Stderr = types.StringValue(result.Stderr)
+	applyClaudeHookOutput(&data, result)
+
+	hash := sha256.Sum256([]byte(data.Command.ValueString() + "\x00" + string(input)))
+	data.ID = types.StringValue(hex.EncodeToString(hash[:]))
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *claudeHookRunDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*FileClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *FileClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+// claudeHookEventJSON builds the event Claude Code sends to hooks of the configured event. The
+// fields every event carries come first; payload is merged over the result.
+func claudeHookEventJSON(data *claudeHookRunDataSourceModel, workDir string) ([]byte, error) {
+	event := data.Event.ValueString()
+	input := map[string]interface{}{
+		"session_id":      claudeHookRunSessionID,
+		"transcript_path": "",
+		"cwd":             workDir,
+		"hook_event_name": event,
+	}
+
+	jsonField := func(key string, v types.String) error {
+		if v.IsNull() {
+			input[key] = map[string]interface{}{}
+			return nil
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(v.ValueString()), &obj); err != nil {
+			return fmt.Errorf("%s must be a JSON object: %w", key, err)
+		}
+		input[key] = obj
+		return nil
+	}
+
+	switch event {
+	case "PreToolUse", "PostToolUse":
+		input["tool_name"] = data.ToolName.ValueString()
+		if err := jsonField("tool_input", data.ToolInput); err != nil {
+			return nil, err
+		}
+		if event == "PostToolUse" {
+			if err := jsonField("tool_response", data.ToolResponse); err != nil {
+				return nil, err
+			}
+		}
+	case "UserPromptSubmit":
+		input["prompt"] = data.Prompt.ValueString()
+	case "Notification":
+		input["message"] = data.Message.ValueString()
+	case "Stop", "SubagentStop":
+		input["stop_hook_active"] = false
+	case "PreCompact":
+		input["trigger"] = "manual"
+		input["custom_instructions"] = ""
+	case "SessionStart":
+		input["source"] = "startup"
+	case "SessionEnd":
+		input["reason"] = "other"
+	}
+
+	if !data.Payload.IsNull() {
+		var extra map[string]interface{}
+		if err := json.Unmarshal([]byte(data.Payload.ValueString()), &extra); err != nil {
+			return nil, fmt.Errorf("payload must be a JSON object: %w", err)
+		}
+		for k, v := range extra {
+			input[k] = v
+		}
+	}
+
+	return json.Marshal(input)
+}
+
+// runClaudeHook runs command with sh -c, piping input to its stdin. A non-zero exit code is a
+// result, not an error; errors are reserved for commands that could not be started.
+func runClaudeHook(ctx context.Context, command, workDir string, env []string, input []byte, timeout time.Duration) (claudeHookRunResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Background processes that keep the output pipes open must not hang the read.
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	result := claudeHookRunResult{Stdout: stdout.String(), Stderr: stderr.String()}
+	if ctx.Err() == context.DeadlineExceeded {
+		result.ExitCode = -1
+		result.TimedOut = true
+		return result, nil
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		return result, err
+	}
+	return result, nil
+}
+
+// applyClaudeHookOutput sets output_json, decision, reason and blocked from the hook's result,
+// following how Claude Code interprets exit codes and JSON output.
+func applyClaudeHookOutput(data *claudeHookRunDataSourceModel, result claudeHookRunResult) {
+	data.OutputJSON = types.StringNull()
+	data.Decision = types.StringNull()
+	data.Reason = types.StringNull()
+	blocked := result.ExitCode == 2
+
+	var output map[string]interface{}
+	if json.Unmarshal([]byte(result.Stdout), &output) == nil && output != nil {
+		normalized, _ := json.Marshal(output)
+		data.OutputJSON = types.StringValue(string(normalized))
+
+		specific, _ := output["hookSpecificOutput"].(map[string]interface{})
+		if decision, ok := specific["permissionDecision"].(string); ok {
+			data.Decision = types.StringValue(decision)
+			data.Reason = jsonStringValue(specific, "permissionDecisionReason")
+		} else if decision, ok := output["decision"].(string); ok {
+			data.Decision = types.StringValue(decision)
+			data.Reason = jsonStringValue(output, "reason")
+		}
+		if cont, ok := output["continue"].(bool); ok && !cont {
+			blocked = true
+			if data.Reason.IsNull() {
+				data.Reason = jsonStringValue(output, "stopReason")
+			}
+		}
+		switch data.Decision.ValueString() {
+		case "deny", "block":
+			blocked = true
+		}
+	}
+
+	if result.ExitCode == 2 && data.Reason.IsNull() {
+		data.Reason = types.StringValue(strings.TrimSpace(result.Stderr))
+	}
+	data.Blocked = types.BoolValue(blocked)
+}
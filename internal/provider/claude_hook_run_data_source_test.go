@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestClaudeHookRunDataSource_claudeHookEventJSON(t *testing.T) {
+	data := claudeHookRunDataSourceModel{
+		Event:     types.StringValue("PreToolUse"),
+		ToolName:  types.StringValue("Bash"),
+		ToolInput: types.StringValue(`{"command":"rm -rf /"}`),
+		Payload:   types.StringValue(`{"session_id":"custom","permission_mode":"default"}`),
+	}
+
+	input, err := claudeHookEventJSON(&data, "/work")
+	if err != nil {
+		t.Fatalf("Failed to build event: %v", err)
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(input, &event); err != nil {
+		t.Fatalf("Failed to parse event: %v", err)
+	}
+	expected := map[string]interface{}{
+		"session_id":      "custom",
+		"transcript_path": "",
+		"cwd":             "/work",
+		"hook_event_name": "PreToolUse",
+		"tool_name":       "Bash",
+		"tool_input":      map[string]interface{}{"command": "rm -rf /"},
+		"permission_mode": "default",
+	}
+	if !jsonEqual(event, expected) {
+		t.Errorf("Expected %v, got %v", expected, event)
+	}
+}
+
+func TestClaudeHookRunDataSource_applyClaudeHookOutput(t *testing.T) {
+	testCases := []struct {
+		name             string
+		result           claudeHookRunResult
+		expectedDecision string
+		expectedReason   string
+		expectedBlocked  bool
+	}{
+		{
+			name:   "exit_zero",
+			result: claudeHookRunResult{Stdout: "ok\n"},
+		},
+		{
+			name:            "exit_two",
+			result:          claudeHookRunResult{ExitCode: 2, Stderr: "rm -rf is not allowed\n"},
+			expectedReason:  "rm -rf is not allowed",
+			expectedBlocked: true,
+		},
+		{
+			name:             "permission_decision",
+			result:           claudeHookRunResult{Stdout: `{"hookSpecificOutput":{"hookEventName":"PreToolUse","permissionDecision":"deny","permissionDecisionReason":"destructive"}}`},
+			expectedDecision: "deny",
+			expectedReason:   "destructive",
+			expectedBlocked:  true,
+		},
+		{
+			name:             "allow_decision",
+			result:           claudeHookRunResult{Stdout: `{"hookSpecificOutput":{"permissionDecision":"allow"}}`},
+			expectedDecision: "allow",
+		},
+		{
+			name:             "top_level_decision",
+			result:           claudeHookRunResult{Stdout: `{"decision":"block","reason":"tests fail"}`},
+			expectedDecision: "block",
+			expectedReason:   "tests fail",
+			expectedBlocked:  true,
+		},
+		{
+			name:            "continue_false",
+			result:          claudeHookRunResult{Stdout: `{"continue":false,"stopReason":"build broken"}`},
+			expectedReason:  "build broken",
+			expectedBlocked: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var data claudeHookRunDataSourceModel
+			applyClaudeHookOutput(&data, tc.result)
+
+			if data.Decision.ValueString() != tc.expectedDecision {
+				t.Errorf("Expected decision %q, got %s", tc.expectedDecision, data.Decision)
+			}
+			if data.Reason.ValueString() != tc.expectedReason {
+				t.Errorf("Expected reason %q, got %s", tc.expectedReason, data.Reason)
+			}
+			if data.Blocked.ValueBool() != tc.expectedBlocked {
+				t.Errorf("Expected blocked=%v, got %s", tc.expectedBlocked, data.Blocked)
+			}
+		})
+	}
+}
+
+func TestClaudeHookRunDataSource_runClaudeHook(t *testing.T) {
+	ctx := context.Background()
+	workDir := t.TempDir()
+
+	result, err := runClaudeHook(ctx, `cat; echo "$CLAUDE_PROJECT_DIR" >&2; exit 3`, workDir, []string{"CLAUDE_PROJECT_DIR=" + workDir}, []byte(`{"a":1}`), 10*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to run hook: %v", err)
+	}
+	if result.ExitCode != 3 || result.Stdout != `{"a":1}` || result.Stderr != workDir+"\n" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	result, err = runClaudeHook(ctx, "sleep 5", workDir, nil, nil, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to run hook: %v", err)
+	}
+	if !result.TimedOut || result.ExitCode != -1 {
+		t.Errorf("Expected timeout, got %+v", result)
+	}
+}
+
+func TestAccClaudeHookRunDataSource(t *testing.T) {
+	workDir := t.TempDir()
+	script := "#!/bin/sh\nif grep -q 'rm -rf'; then echo 'destructive command' >&2; exit 2; fi\n"
+	if err := os.MkdirAll(filepath.Join(workDir, ".claude", "hooks"), 0755); err != nil {
+		t.Fatalf("Failed to create hooks directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, ".claude", "hooks", "guard.sh"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccClaudeHookRunDataSourceConfig(workDir),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.agentsmith_claude_hook_run.deny", "exit_code", "2"),
+					resource.TestCheckResourceAttr("data.agentsmith_claude_hook_run.deny", "blocked", "true"),
+					resource.TestCheckResourceAttr("data.agentsmith_claude_hook_run.deny", "reason", "destructive command"),
+					resource.TestCheckResourceAttr("data.agentsmith_claude_hook_run.allow", "exit_code", "0"),
+					resource.TestCheckResourceAttr("data.agentsmith_claude_hook_run.allow", "blocked", "false"),
+					resource.TestCheckResourceAttr("data.agentsmith_claude_hook_run.allow", "timed_out", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccClaudeHookRunDataSourceConfig(workDir string) string {
+	return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+data "agentsmith_claude_hook_run" "deny" {
+  command    = "\"$CLAUDE_PROJECT_DIR\"/.claude/hooks/guard.sh"
+  event      = "PreToolUse"
+  tool_name  = "Bash"
+  tool_input = jsonencode({ command = "rm -rf /" })
+}
+
+data "agentsmith_claude_hook_run" "allow" {
+  command    = "\"$CLAUDE_PROJECT_DIR\"/.claude/hooks/guard.sh"
+  event      = "PreToolUse"
+  tool_name  = "Bash"
+  tool_input = jsonencode({ command = "ls" })
+  timeout    = 10
+}
+`, workDir)
+}
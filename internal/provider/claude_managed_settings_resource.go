@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &claudeManagedSettingsResource{}
+	_ resource.ResourceWithConfigure      = &claudeManagedSettingsResource{}
+	_ resource.ResourceWithImportState    = &claudeManagedSettingsResource{}
+	_ resource.ResourceWithValidateConfig = &claudeManagedSettingsResource{}
+)
+
+func NewClaudeManagedSettingsResource() resource.Resource {
+	return &claudeManagedSettingsResource{}
+}
+
+// claudeManagedSettingsResource manages the enterprise policy files. It shares its settings
+// schema and conversion logic with claudeSettingsResource.
+type claudeManagedSettingsResource struct {
+	client   *FileClient
+	settings claudeSettingsResource
+}
+
+type claudeManagedSettingsResourceModel struct {
+	claudeSettingsResourceModel
+	SystemRoot types.String `tfsdk:"system_root"`
+	McpServers types.Map    `tfsdk:"mcp_servers"`
+}
+
+// claudeManagedSystemRoot returns the directory Claude Code reads managed policy files from.
+func claudeManagedSystemRoot() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "/Library/Application Support/ClaudeCode"
+	case "windows":
+		return "C:\\ProgramData\\ClaudeCode"
+	default:
+		return "/etc/claude-code"
+	}
+}
+
+func (r *claudeManagedSettingsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claude_managed_settings"
+}
+
+func (r *claudeManagedSettingsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	r.settings.Schema(ctx, req, resp)
+
+	resp.Schema.Description = "Manages the Claude Code enterprise policy files `managed-settings.json` and `managed-mcp.json`. Managed settings take precedence over user and project settings and accept the same attributes as `agentsmith_claude_settings`."
+	resp.Schema.Attributes["id"] = schema.StringAttribute{
+		Description: "A unique identifier for this managed settings resource.",
+		Computed:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+	resp.Schema.Attributes["scope"] = schema.StringAttribute{
+		Description: "The scope of the settings file. Always `managed`.",
+		Computed:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+	resp.Schema.Attributes["system_root"] = schema.StringAttribute{
+		Description: "The directory containing the managed policy files. Defaults to `/etc/claude-code` on Linux, `/Library/Application Support/ClaudeCode` on macOS, and `C:\\ProgramData\\ClaudeCode` on Windows. Override it to provision an image or to test against a temporary directory.",
+		Optional:    true,
+		Computed:    true,
+		Default:     stringdefault.StaticString(claudeManagedSystemRoot()),
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+	resp.Schema.Attributes["mcp_servers"] = schema.MapAttribute{
+		Description: "A map of MCP server name to its JSON definition, written to `managed-mcp.json`. Values can be the `json` output of the `agentsmith_mcp_stdio` and `agentsmith_mcp_remote` data sources, or `jsonencode` of an object with `type`, `command`, `args`, `env`, `url`, and `headers`.",
+		ElementType: types.StringType,
+		Optional:    true,
+	}
+}
+
+func (r *claudeManagedSettingsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config claudeManagedSettingsResourceModel
+	// Blocks that are not known yet cannot be decoded; they are checked again on apply.
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		return
+	}
+
+	r.settings.validateSettingsConfig(ctx, &resp.Diagnostics, &config.claudeSettingsResourceModel)
+}
+
+func (r *claudeManagedSettingsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan claudeManagedSettingsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settingsPath, mcpPath := r.getManagedFilePaths(plan.SystemRoot.ValueString())
+	if settingsPath == "" {
+		resp.Diagnostics.AddError("Invalid system root", "system_root must not be empty")
+		return
+	}
+
+	written := r.writeManagedFiles(ctx, &resp.Diagnostics, &plan, nil, nil, settingsPath, mcpPath)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(setWrittenSettingsKeys(ctx, resp.Private, written)...)
+
+	// Set computed attributes
+	plan.Scope = types.StringValue("managed")
+	plan.ID = types.StringValue("claude-settings-managed")
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudeManagedSettingsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state claudeManagedSettingsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settingsPath, mcpPath := r.getManagedFilePaths(state.SystemRoot.ValueString())
+	if settingsPath == "" {
+		resp.Diagnostics.AddError("Invalid system root", "system_root must not be empty")
+		return
+	}
+
+	// Check if file exists
+	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	settingsData, err := readSettingsJSON(settingsPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read managed settings file", err.Error())
+		return
+	}
+	// A freshly imported resource, or one that replaces the whole file, owns every known key
+	// that it finds. Otherwise only the keys already in state are refreshed.
+	imported, d := req.Private.GetKey(ctx, claudeSettingsImportedKey)
+	resp.Diagnostics.Append(d...)
+	if len(imported) > 0 {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, nil)...)
+	}
+	ownAll := len(imported) > 0 || settingsMergeStrategy(state.MergeStrategy) == "replace_all"
+	r.settings.settingsToModel(ctx, &resp.Diagnostics, &state.claudeSettingsResourceModel, settingsData, ownAll)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mcpData, err := readSettingsJSON(mcpPath)
+	switch {
+	case os.IsNotExist(err):
+		state.McpServers = types.MapNull(types.StringType)
+	case err != nil:
+		resp.Diagnostics.AddError("Failed to read managed MCP file", err.Error())
+		return
+	default:
+		var prior map[string]string
+		if !state.McpServers.IsNull() {
+			resp.Diagnostics.Append(state.McpServers.ElementsAs(ctx, &prior, false)...)
+		}
+		current, _ := mcpData["mcpServers"].(map[string]interface{})
+		state.McpServers, diags = types.MapValueFrom(ctx, types.StringType, refreshClaudeMCPServers(prior, current))
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudeManagedSettingsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state claudeManagedSettingsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settingsPath, mcpPath := r.getManagedFilePaths(plan.SystemRoot.ValueString())
+	if settingsPath == "" {
+		resp.Diagnostics.AddError("Invalid system root", "system_root must not be empty")
+		return
+	}
+
+	prior := r.settings.writtenKeys(ctx, &resp.Diagnostics, req.Private, &state.claudeSettingsResourceModel)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	written := r.writeManagedFiles(ctx, &resp.Diagnostics, &plan, &state, prior, settingsPath, mcpPath)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(setWrittenSettingsKeys(ctx, resp.Private, written)...)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudeManagedSettingsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state claudeManagedSettingsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settingsPath, mcpPath := r.getManagedFilePaths(state.SystemRoot.ValueString())
+	if settingsPath == "" {
+		resp.Diagnostics.AddError("Invalid system root", "system_root must not be empty")
+		return
+	}
+
+	written := r.settings.writtenKeys(ctx, &resp.Diagnostics, req.Private, &state.claudeSettingsResourceModel)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.settings.removeSettings(&resp.Diagnostics, &state.claudeSettingsResourceModel, written, settingsPath)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.McpServers.IsNull() {
+		if err := os.Remove(mcpPath); err != nil && !os.IsNotExist(err) {
+			resp.Diagnostics.AddError("Failed to delete managed MCP file", err.Error())
+		}
+	}
+}
+
+func (r *claudeManagedSettingsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: the system root directory, e.g. /etc/claude-code
+	root := strings.TrimSpace(req.ID)
+	if root == "" {
+		resp.Diagnostics.AddError("Invalid import ID", "Import ID must be the directory containing managed-settings.json (e.g., '/etc/claude-code')")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("system_root"), root)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("scope"), "managed")...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), "claude-settings-managed")...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, []byte("true"))...)
+}
+
+func (r *claudeManagedSettingsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*FileClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *FileClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+	r.settings.client = client
+}
+
+// getManagedFilePaths returns the paths of managed-settings.json and managed-mcp.json under root.
+func (r *claudeManagedSettingsResource) getManagedFilePaths(root string) (string, string) {
+	if strings.TrimSpace(root) == "" {
+		return "", ""
+	}
+	return filepath.Join(root, "managed-settings.json"), filepath.Join(root, "managed-mcp.json")
+}
+
+// writeManagedFiles writes managed-settings.json and, when mcp_servers is set, managed-mcp.json.
+// managed-mcp.json is removed when mcp_servers is no longer configured. priorKeys holds the
+// settings key paths written before; the ones written now are returned.
+func (r *claudeManagedSettingsResource) writeManagedFiles(ctx context.Context, diags *diag.Diagnostics, plan, prior *claudeManagedSettingsResourceModel, priorKeys [][]string, settingsPath, mcpPath string) [][]string {
+	written := r.settings.writeSettings(ctx, diags, &plan.claudeSettingsResourceModel, priorKeys, settingsPath)
+	if diags.HasError() {
+		return nil
+	}
+
+	if plan.McpServers.IsNull() {
+		if prior != nil && !prior.McpServers.IsNull() {
+			if err := os.Remove(mcpPath); err != nil && !os.IsNotExist(err) {
+				diags.AddError("Failed to delete managed MCP file", err.Error())
+			}
+		}
+		return written
+	}
+
+	var servers map[string]string
+	diags.Append(plan.McpServers.ElementsAs(ctx, &servers, false)...)
+	if diags.HasError() {
+		return written
+	}
+	mcpServers, err := claudeMCPServersFromJSON(servers)
+	if err != nil {
+		diags.AddError("Invalid MCP server definition", err.Error())
+		return written
+	}
+
+	jsonData, err := json.MarshalIndent(map[string]interface{}{"mcpServers": mcpServers}, "", "  ")
+	if err != nil {
+		diags.AddError("Failed to marshal JSON", err.Error())
+		return written
+	}
+	if err := plan.writeFile(mcpPath, jsonData, 0644); err != nil {
+		diags.AddError("Failed to write managed MCP file", err.Error())
+	}
+	return written
+}
@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestClaudeManagedSettingsResource_getManagedFilePaths(t *testing.T) {
+	r := &claudeManagedSettingsResource{}
+	tempDir := t.TempDir()
+
+	settingsPath, mcpPath := r.getManagedFilePaths(tempDir)
+	if settingsPath != filepath.Join(tempDir, "managed-settings.json") {
+		t.Errorf("Expected settings path in system root, got %q", settingsPath)
+	}
+	if mcpPath != filepath.Join(tempDir, "managed-mcp.json") {
+		t.Errorf("Expected MCP path in system root, got %q", mcpPath)
+	}
+
+	if settingsPath, _ := r.getManagedFilePaths(" "); settingsPath != "" {
+		t.Errorf("Expected empty path for blank system root, got %q", settingsPath)
+	}
+}
+
+func TestClaudeManagedSettingsResource_Schema(t *testing.T) {
+	r := &claudeManagedSettingsResource{}
+	ctx := context.Background()
+
+	resp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Schema has errors: %v", resp.Diagnostics.Errors())
+	}
+
+	for _, name := range []string{"system_root", "mcp_servers", "model", "merge_strategy"} {
+		if _, ok := resp.Schema.Attributes[name]; !ok {
+			t.Errorf("Expected attribute %q in schema", name)
+		}
+	}
+	if !resp.Schema.Attributes["scope"].IsComputed() || resp.Schema.Attributes["scope"].IsRequired() {
+		t.Error("Expected scope to be computed only")
+	}
+
+	// The embedded settings model must round-trip through the schema
+	model := claudeManagedSettingsResourceModel{
+		SystemRoot: types.StringValue("/etc/claude-code"),
+		McpServers: types.MapNull(types.StringType),
+	}
+	var diags diag.Diagnostics
+	r.settings.settingsToModel(ctx, &diags, &model.claudeSettingsResourceModel, map[string]interface{}{"model": "opus"}, true)
+	model.ID = types.StringValue("claude-settings-managed")
+	model.Scope = types.StringValue("managed")
+	state := tfsdk.State{Schema: resp.Schema, Raw: tftypes.NewValue(resp.Schema.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, model); diags.HasError() {
+		t.Fatalf("Failed to set state: %v", diags.Errors())
+	}
+	var read claudeManagedSettingsResourceModel
+	if diags := state.Get(ctx, &read); diags.HasError() {
+		t.Fatalf("Failed to get state: %v", diags.Errors())
+	}
+	if read.Model.ValueString() != "opus" || read.SystemRoot.ValueString() != "/etc/claude-code" {
+		t.Errorf("Expected model and system_root to round-trip, got %v and %v", read.Model, read.SystemRoot)
+	}
+}
+
+func TestClaudeManagedSettingsResource_writeManagedFiles(t *testing.T) {
+	r := &claudeManagedSettingsResource{}
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	settingsPath, mcpPath := r.getManagedFilePaths(tempDir)
+
+	servers, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{
+		"memory": `{"command":"npx","args":["-y","@modelcontextprotocol/server-memory"],"transport":"stdio","description":"Memory"}`,
+		"docs":   `{"url":"https://mcp.example.com/mcp","transport":"streamable-http","headers":{"X-Team":"platform"}}`,
+	})
+	plan := claudeManagedSettingsResourceModel{
+		claudeSettingsResourceModel: claudeSettingsResourceModel{
+			MergeStrategy: types.StringValue("replace_all"),
+			Model:         types.StringValue("opus"),
+		},
+		SystemRoot: types.StringValue(tempDir),
+		McpServers: servers,
+	}
+
+	var diags diag.Diagnostics
+	written := r.writeManagedFiles(ctx, &diags, &plan, nil, nil, settingsPath, mcpPath)
+	if diags.HasError() {
+		t.Fatalf("Failed to write managed files: %v", diags.Errors())
+	}
+
+	settings, err := readSettingsJSON(settingsPath)
+	if err != nil {
+		t.Fatalf("Failed to read managed settings: %v", err)
+	}
+	if settings["model"] != "opus" {
+		t.Errorf("Expected model 'opus', got %v", settings["model"])
+	}
+
+	mcp, err := readSettingsJSON(mcpPath)
+	if err != nil {
+		t.Fatalf("Failed to read managed MCP file: %v", err)
+	}
+	mcpServers, ok := mcp["mcpServers"].(map[string]interface{})
+	if !ok || len(mcpServers) != 2 {
+		t.Fatalf("Expected 2 MCP servers, got %v", mcp)
+	}
+	memory := mcpServers["memory"].(map[string]interface{})
+	if memory["type"] != "stdio" || memory["command"] != "npx" {
+		t.Errorf("Expected stdio server in Claude dialect, got %v", memory)
+	}
+	if _, ok := memory["description"]; ok {
+		t.Error("Expected keys unknown to Claude Code to be dropped")
+	}
+	docs := mcpServers["docs"].(map[string]interface{})
+	if docs["type"] != "http" || docs["url"] != "https://mcp.example.com/mcp" {
+		t.Errorf("Expected http server in Claude dialect, got %v", docs)
+	}
+
+	// Removing mcp_servers removes managed-mcp.json
+	prior := plan
+	plan.McpServers = types.MapNull(types.StringType)
+	r.writeManagedFiles(ctx, &diags, &plan, &prior, written, settingsPath, mcpPath)
+	if diags.HasError() {
+		t.Fatalf("Failed to write managed files: %v", diags.Errors())
+	}
+	if _, err := os.Stat(mcpPath); !os.IsNotExist(err) {
+		t.Error("Expected managed-mcp.json to be removed")
+	}
+
+	// Invalid server definitions are reported
+	invalid, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"broken": `{"transport":"stdio"}`})
+	plan.McpServers = invalid
+	diags = nil
+	r.writeManagedFiles(ctx, &diags, &plan, nil, nil, settingsPath, mcpPath)
+	if !diags.HasError() {
+		t.Error("Expected error for stdio server without a command")
+	}
+}
+
+func TestAccClaudeManagedSettingsResource_basic(t *testing.T) {
+	systemRoot := t.TempDir()
+
+	tfresource.Test(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccClaudeManagedSettingsResourceConfig(systemRoot, "claude-3-5-sonnet"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("agentsmith_claude_managed_settings.test", "scope", "managed"),
+					tfresource.TestCheckResourceAttr("agentsmith_claude_managed_settings.test", "system_root", systemRoot),
+					tfresource.TestCheckResourceAttr("agentsmith_claude_managed_settings.test", "model", "claude-3-5-sonnet"),
+					tfresource.TestCheckResourceAttr("agentsmith_claude_managed_settings.test", "mcp_servers.%", "1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "agentsmith_claude_managed_settings.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     systemRoot,
+				// Imported servers are read in Claude's dialect rather than the data source's JSON
+				ImportStateVerifyIgnore: []string{"mcp_servers"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccClaudeManagedSettingsResourceConfig(systemRoot, "claude-3-opus"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("agentsmith_claude_managed_settings.test", "model", "claude-3-opus"),
+				),
+			},
+		},
+	})
+}
+
+func testAccClaudeManagedSettingsResourceConfig(systemRoot, model string) string {
+	return fmt.Sprintf(`
+data "agentsmith_mcp_stdio" "memory" {
+  command = "npx"
+  args    = ["-y", "@modelcontextprotocol/server-memory"]
+}
+
+resource "agentsmith_claude_managed_settings" "test" {
+  system_root = %q
+  model       = %q
+
+  mcp_servers = {
+    memory = data.agentsmith_mcp_stdio.memory.json
+  }
+}
+`, systemRoot, model)
+}
@@ -0,0 +1,243 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-agentsmith/internal/fileio"
+)
+
+var (
+	_ resource.Resource                   = &claudeMCPJSONResource{}
+	_ resource.ResourceWithConfigure      = &claudeMCPJSONResource{}
+	_ resource.ResourceWithImportState    = &claudeMCPJSONResource{}
+	_ resource.ResourceWithValidateConfig = &claudeMCPJSONResource{}
+)
+
+func NewClaudeMCPJSONResource() resource.Resource {
+	return &claudeMCPJSONResource{}
+}
+
+type claudeMCPJSONResource struct {
+	client *FileClient
+}
+
+type claudeMCPJSONResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	McpServers types.Map    `tfsdk:"mcp_servers"`
+	fileWriteOptionsModel
+}
+
+func (r *claudeMCPJSONResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claude_mcp_json"
+}
+
+func (r *claudeMCPJSONResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the project-scoped MCP server file `<workdir>/.mcp.json` that Claude Code shares through version control. Servers are written in Claude Code's dialect (`type`, `command`, `args`, `env`, `url`, `headers`). This resource is a singleton per working directory.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The path of the `.mcp.json` file.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"mcp_servers": schema.MapAttribute{
+				Description: "A map of MCP server name to its JSON definition. Values can be the `json` output of the `agentsmith_mcp_stdio` and `agentsmith_mcp_remote` data sources, or a `jsonencode`d object in Claude Code's own dialect. Keys Claude Code does not understand are dropped.",
+				ElementType: types.StringType,
+				Required:    true,
+			},
+		},
+	}
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0644")
+}
+
+func (r *claudeMCPJSONResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var servers types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("mcp_servers"), &servers)...)
+	if resp.Diagnostics.HasError() || servers.IsNull() || servers.IsUnknown() {
+		return
+	}
+
+	for name, v := range servers.Elements() {
+		s, ok := v.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+		if _, err := claudeMCPServerFromJSON(s.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("mcp_servers").AtMapKey(name),
+				"Invalid MCP server definition",
+				fmt.Sprintf("mcp server %q: %s", name, err),
+			)
+		}
+	}
+}
+
+func (r *claudeMCPJSONResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan claudeMCPJSONResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filePath := r.getMCPJSONFilePath()
+	defer fileio.Lock(filePath)()
+
+	r.writeMCPJSON(ctx, &resp.Diagnostics, &plan, filePath)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set computed attributes
+	plan.ID = types.StringValue(filePath)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudeMCPJSONResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state claudeMCPJSONResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data, err := readSettingsJSON(state.ID.ValueString())
+	if os.IsNotExist(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read .mcp.json file", err.Error())
+		return
+	}
+
+	// Every server in the file is managed, so servers added by hand show up as drift
+	var prior map[string]string
+	if !state.McpServers.IsNull() {
+		resp.Diagnostics.Append(state.McpServers.ElementsAs(ctx, &prior, false)...)
+	}
+	current, _ := data["mcpServers"].(map[string]interface{})
+	state.McpServers, diags = types.MapValueFrom(ctx, types.StringType, refreshClaudeMCPServers(prior, current))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudeMCPJSONResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan claudeMCPJSONResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filePath := plan.ID.ValueString()
+	defer fileio.Lock(filePath)()
+
+	r.writeMCPJSON(ctx, &resp.Diagnostics, &plan, filePath)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudeMCPJSONResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state claudeMCPJSONResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filePath := state.ID.ValueString()
+	defer fileio.Lock(filePath)()
+
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		resp.Diagnostics.AddError("Failed to delete .mcp.json file", err.Error())
+	}
+}
+
+func (r *claudeMCPJSONResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import uses the working directory's .mcp.json since there's only one per project
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), r.getMCPJSONFilePath())...)
+}
+
+func (r *claudeMCPJSONResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*FileClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *FileClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *claudeMCPJSONResource) getMCPJSONFilePath() string {
+	workDir := "."
+	if r.client != nil {
+		workDir = r.client.workDir
+	}
+	return filepath.Join(workDir, ".mcp.json")
+}
+
+// writeMCPJSON replaces the mcpServers object of the file with the planned servers. Other
+// top-level keys are kept.
+func (r *claudeMCPJSONResource) writeMCPJSON(ctx context.Context, diags *diag.Diagnostics, plan *claudeMCPJSONResourceModel, filePath string) {
+	var servers map[string]string
+	diags.Append(plan.McpServers.ElementsAs(ctx, &servers, false)...)
+	if diags.HasError() {
+		return
+	}
+	mcpServers, err := claudeMCPServersFromJSON(servers)
+	if err != nil {
+		diags.AddError("Invalid MCP server definition", err.Error())
+		return
+	}
+
+	data, err := readSettingsJSON(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		diags.AddError("Failed to read .mcp.json file", err.Error())
+		return
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["mcpServers"] = mcpServers
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		diags.AddError("Failed to marshal JSON", err.Error())
+		return
+	}
+	if err := plan.writeFile(filePath, jsonData, 0644); err != nil {
+		diags.AddError("Failed to write .mcp.json file", err.Error())
+	}
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestClaudeMCPJSONResource_writeMCPJSON(t *testing.T) {
+	r := &claudeMCPJSONResource{}
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), ".mcp.json")
+
+	if err := os.WriteFile(filePath, []byte(`{"mcpServers":{"stale":{"type":"stdio","command":"old"}},"$schema":"https://example.com/mcp.schema.json"}`), 0644); err != nil {
+		t.Fatalf("Failed to write .mcp.json: %v", err)
+	}
+
+	servers, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{
+		"memory": `{"command":"npx","args":["-y","@modelcontextprotocol/server-memory"],"env":{"DEBUG":"1"},"transport":"stdio"}`,
+		"docs":   `{"type":"http","url":"https://mcp.example.com/mcp","headers":{"Authorization":"Bearer ${DOCS_TOKEN}"}}`,
+	})
+	plan := claudeMCPJSONResourceModel{McpServers: servers}
+
+	var diags diag.Diagnostics
+	r.writeMCPJSON(ctx, &diags, &plan, filePath)
+	if diags.HasError() {
+		t.Fatalf("Failed to write .mcp.json: %v", diags.Errors())
+	}
+
+	data, err := readSettingsJSON(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read .mcp.json: %v", err)
+	}
+	if data["$schema"] == nil {
+		t.Error("Expected other top-level keys to be kept")
+	}
+	mcpServers := data["mcpServers"].(map[string]interface{})
+	if len(mcpServers) != 2 {
+		t.Fatalf("Expected the servers to be replaced, got %v", mcpServers)
+	}
+	memory := mcpServers["memory"].(map[string]interface{})
+	if memory["type"] != "stdio" || memory["env"].(map[string]interface{})["DEBUG"] != "1" {
+		t.Errorf("Expected stdio server in Claude dialect, got %v", memory)
+	}
+	if _, ok := memory["transport"]; ok {
+		t.Error("Expected transport to be dropped")
+	}
+	docs := mcpServers["docs"].(map[string]interface{})
+	if docs["type"] != "http" || docs["headers"] == nil {
+		t.Errorf("Expected http server with headers, got %v", docs)
+	}
+
+	// Invalid server definitions are reported
+	invalid, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"broken": `{"type":"sse"}`})
+	plan.McpServers = invalid
+	r.writeMCPJSON(ctx, &diags, &plan, filePath)
+	if !diags.HasError() {
+		t.Error("Expected error for sse server without a url")
+	}
+}
+
+func TestAccClaudeMCPJSONResource_basic(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, ".mcp.json")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccClaudeMCPJSONResourceConfig(workDir, `{}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_mcp_json.test", "id", filePath),
+					resource.TestCheckResourceAttr("agentsmith_claude_mcp_json.test", "mcp_servers.%", "2"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "agentsmith_claude_mcp_json.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Imported servers are read in Claude's dialect rather than the data source's JSON
+				ImportStateVerifyIgnore: []string{"mcp_servers"},
+			},
+			// Servers added by hand show up as drift
+			{
+				PreConfig: func() {
+					data, err := readSettingsJSON(filePath)
+					if err != nil {
+						t.Fatalf("Failed to read .mcp.json: %v", err)
+					}
+					data["mcpServers"].(map[string]interface{})["manual"] = map[string]interface{}{"type": "stdio", "command": "manual-server"}
+					jsonData, _ := json.MarshalIndent(data, "", "  ")
+					if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+						t.Fatalf("Failed to write .mcp.json: %v", err)
+					}
+				},
+				Config:             testAccClaudeMCPJSONResourceConfig(workDir, `{}`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccClaudeMCPJSONResourceConfig(workDir, `{ DEBUG = "1" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_mcp_json.test", "mcp_servers.%", "2"),
+					resource.TestCheckResourceAttrWith("agentsmith_claude_mcp_json.test", "mcp_servers.memory", func(value string) error {
+						data, err := readSettingsJSON(filePath)
+						if err != nil {
+							return err
+						}
+						servers := data["mcpServers"].(map[string]interface{})
+						if _, ok := servers["manual"]; ok {
+							return fmt.Errorf("expected the manual server to be removed, got %v", servers)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccClaudeMCPJSONResourceConfig(workDir, env string) string {
+	return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+data "agentsmith_mcp_stdio" "memory" {
+  command = "npx"
+  args    = ["-y", "@modelcontextprotocol/server-memory"]
+  env     = %s
+}
+
+resource "agentsmith_claude_mcp_json" "test" {
+  mcp_servers = {
+    memory = data.agentsmith_mcp_stdio.memory.json
+    docs = jsonencode({
+      type = "http"
+      url  = "https://mcp.example.com/mcp"
+    })
+  }
+}
+`, workDir, env)
+}
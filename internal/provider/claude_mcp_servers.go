@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// claudeMCPServerFromJSON converts an MCP server definition, such as the `json` output of the
+// mcp_stdio and mcp_remote data sources, into Claude Code's `.mcp.json` dialect. Only the keys
+// Claude Code understands are kept: type, command, args and env for stdio servers, and type, url
+// and headers for remote servers.
+func claudeMCPServerFromJSON(raw string) (map[string]interface{}, error) {
+	var def map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &def); err != nil {
+		return nil, fmt.Errorf("failed to parse server JSON: %w", err)
+	}
+	if def == nil {
+		return nil, fmt.Errorf("server definition must be a JSON object")
+	}
+
+	serverType, _ := def["type"].(string)
+	switch serverType {
+	case "stdio", "sse", "http":
+	default:
+		transport, _ := def["transport"].(string)
+		switch transport {
+		case "stdio", "sse", "http":
+			serverType = transport
+		case "streamable-http", "streamable_http":
+			serverType = "http"
+		default:
+			if _, ok := def["url"]; ok {
+				serverType = "http"
+			} else {
+				serverType = "stdio"
+			}
+		}
+	}
+
+	server := map[string]interface{}{"type": serverType}
+	if serverType == "stdio" {
+		command, ok := def["command"].(string)
+		if !ok || command == "" {
+			return nil, fmt.Errorf("stdio server requires a command")
+		}
+		server["command"] = command
+		if args, ok := def["args"].([]interface{}); ok && len(args) > 0 {
+			server["args"] = args
+		}
+		if env, ok := def["env"].(map[string]interface{}); ok && len(env) > 0 {
+			server["env"] = env
+		}
+		return server, nil
+	}
+
+	url, ok := def["url"].(string)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("%s server requires a url", serverType)
+	}
+	server["url"] = url
+	if headers, ok := def["headers"].(map[string]interface{}); ok && len(headers) > 0 {
+		server["headers"] = headers
+	}
+	return server, nil
+}
+
+// claudeMCPServersFromJSON converts a map of server name to JSON definition into the dialect
+// written under the `mcpServers` key.
+func claudeMCPServersFromJSON(servers map[string]string) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(servers))
+	for name, raw := range servers {
+		server, err := claudeMCPServerFromJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("mcp server %q: %w", name, err)
+		}
+		out[name] = server
+	}
+	return out, nil
+}
+
+// refreshClaudeMCPServers returns the server definitions to store in state after reading
+// current from disk. Definitions in prior that render to what is on disk are kept verbatim so
+// that data source output does not show as drift; anything else is replaced by the file's JSON.
+func refreshClaudeMCPServers(prior map[string]string, current map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(current))
+	for name, v := range current {
+		if raw, ok := prior[name]; ok {
+			if rendered, err := claudeMCPServerFromJSON(raw); err == nil && reflect.DeepEqual(rendered, v) {
+				out[name] = raw
+				continue
+			}
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		out[name] = string(b)
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClaudeMCPServerFromJSON(t *testing.T) {
+	testCases := []struct {
+		name        string
+		raw         string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "stdio_from_transport",
+			raw:      `{"command":"my-server","args":["--port","8080"],"env":{"FOO":"bar"},"transport":"stdio","timeout":5000}`,
+			expected: `{"args":["--port","8080"],"command":"my-server","env":{"FOO":"bar"},"type":"stdio"}`,
+		},
+		{
+			name:     "stdio_without_type",
+			raw:      `{"command":"my-server"}`,
+			expected: `{"command":"my-server","type":"stdio"}`,
+		},
+		{
+			name:     "sse_from_transport",
+			raw:      `{"url":"https://example.com/sse","transport":"sse"}`,
+			expected: `{"type":"sse","url":"https://example.com/sse"}`,
+		},
+		{
+			name:     "http_type",
+			raw:      `{"type":"http","url":"https://example.com/mcp","headers":{"Authorization":"Bearer x"}}`,
+			expected: `{"headers":{"Authorization":"Bearer x"},"type":"http","url":"https://example.com/mcp"}`,
+		},
+		{
+			name:        "remote_without_url",
+			raw:         `{"type":"http"}`,
+			expectError: true,
+		},
+		{
+			name:        "not_an_object",
+			raw:         `["my-server"]`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, err := claudeMCPServerFromJSON(tc.raw)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			b, _ := json.Marshal(server)
+			if string(b) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, b)
+			}
+		})
+	}
+}
+
+func TestRefreshClaudeMCPServers(t *testing.T) {
+	prior := map[string]string{
+		"memory": `{"command":"npx","transport":"stdio","description":"Memory"}`,
+		"docs":   `{"type":"http","url":"https://old.example.com/mcp"}`,
+	}
+	current := map[string]interface{}{
+		"memory": map[string]interface{}{"type": "stdio", "command": "npx"},
+		"docs":   map[string]interface{}{"type": "http", "url": "https://new.example.com/mcp"},
+	}
+
+	refreshed := refreshClaudeMCPServers(prior, current)
+	if refreshed["memory"] != prior["memory"] {
+		t.Errorf("Expected unchanged server to keep its configured JSON, got %s", refreshed["memory"])
+	}
+	if refreshed["docs"] != `{"type":"http","url":"https://new.example.com/mcp"}` {
+		t.Errorf("Expected changed server to be read from disk, got %s", refreshed["docs"])
+	}
+}
@@ -0,0 +1,363 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-agentsmith/internal/fileio"
+)
+
+var (
+	_ resource.Resource                = &claudePermissionRuleResource{}
+	_ resource.ResourceWithConfigure   = &claudePermissionRuleResource{}
+	_ resource.ResourceWithImportState = &claudePermissionRuleResource{}
+)
+
+func NewClaudePermissionRuleResource() resource.Resource {
+	return &claudePermissionRuleResource{}
+}
+
+// claudePermissionRuleResource owns a single entry in the permissions lists of a settings.json
+// file that other resources and tools may also edit.
+type claudePermissionRuleResource struct {
+	client   *FileClient
+	settings claudeSettingsResource
+}
+
+// claudePermissionRuleAddedKey is the private state key holding the entry Create added to the
+// list. It is absent when the rule was adopted or imported, which leaves the entry on destroy.
+const claudePermissionRuleAddedKey = "added_rule"
+
+type claudePermissionRuleResourceModel struct {
+	ID    types.String `tfsdk:"id"`
+	Scope types.String `tfsdk:"scope"`
+	List  types.String `tfsdk:"list"`
+	Rule  types.String `tfsdk:"rule"`
+	fileWriteOptionsModel
+}
+
+func (r *claudePermissionRuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claude_permission_rule"
+}
+
+func (r *claudePermissionRuleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a single Claude Code permission rule in a `settings.json` file. The rule is added to the existing list on create and only the entry it added is removed on destroy, so several modules can contribute rules to the same file. Do not also manage the same list with the `permissions` block of `agentsmith_claude_settings`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "A unique identifier for this rule, composed of the scope, list and normalized rule.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"scope": schema.StringAttribute{
+				Description: "The scope of the settings file. Must be one of `user` (~/.claude/settings.json), `project` (<workdir>/.claude/settings.json), or `local` (<workdir>/.claude/settings.local.json).",
+				Required:    true,
+				Validators:  []validator.String{stringOneOf("user", "project", "local")},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"list": schema.StringAttribute{
+				Description: "The permissions list the rule belongs to. Must be one of `allow`, `ask`, or `deny`.",
+				Required:    true,
+				Validators:  []validator.String{stringOneOf("allow", "ask", "deny")},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"rule": schema.StringAttribute{
+				Description: "The permission rule, e.g. `Bash(make test:*)`. A rule that is equivalent to one already in the list is adopted rather than added twice, and is left in place on destroy. The same goes for imported rules.",
+				Required:    true,
+				Validators:  []validator.String{claudePermissionRuleString()},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0644")
+}
+
+func (r *claudePermissionRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan claudePermissionRuleResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filePath := r.settings.getSettingsFilePath(plan.Scope.ValueString())
+	if filePath == "" {
+		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user', 'project', or 'local'")
+		return
+	}
+	defer fileio.Lock(filePath)()
+
+	settings, err := readSettingsJSON(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		resp.Diagnostics.AddError("Failed to read settings file", err.Error())
+		return
+	}
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+
+	changed, err := addPermissionRule(settings, plan.List.ValueString(), plan.Rule.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add permission rule", err.Error())
+		return
+	}
+	if changed {
+		jsonData, err := json.MarshalIndent(settings, "", "  ")
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to marshal JSON", err.Error())
+			return
+		}
+		if err := plan.writeFile(filePath, jsonData, 0644); err != nil {
+			resp.Diagnostics.AddError("Failed to write settings file", err.Error())
+			return
+		}
+
+		added, _ := json.Marshal(plan.Rule.ValueString())
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudePermissionRuleAddedKey, added)...)
+	}
+
+	plan.ID = types.StringValue(claudePermissionRuleID(plan.Scope.ValueString(), plan.List.ValueString(), plan.Rule.ValueString()))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudePermissionRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state claudePermissionRuleResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filePath := r.settings.getSettingsFilePath(state.Scope.ValueString())
+	if filePath == "" {
+		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user', 'project', or 'local'")
+		return
+	}
+
+	settings, err := readSettingsJSON(filePath)
+	if os.IsNotExist(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read settings file", err.Error())
+		return
+	}
+
+	// The rule is gone once no equivalent entry is left in the list
+	if len(findPermissionRule(settings, state.List.ValueString(), state.Rule.ValueString())) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudePermissionRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only the write options can change in place; they take effect on the next write.
+	var plan claudePermissionRuleResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudePermissionRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state claudePermissionRuleResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filePath := r.settings.getSettingsFilePath(state.Scope.ValueString())
+	if filePath == "" {
+		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user', 'project', or 'local'")
+		return
+	}
+	// Rules that were adopted rather than added belong to someone else
+	raw, d := req.Private.GetKey(ctx, claudePermissionRuleAddedKey)
+	resp.Diagnostics.Append(d...)
+	var added string
+	if len(raw) == 0 || json.Unmarshal(raw, &added) != nil {
+		return
+	}
+	defer fileio.Lock(filePath)()
+
+	settings, err := readSettingsJSON(filePath)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read settings file", err.Error())
+		return
+	}
+
+	if !removePermissionRule(settings, state.List.ValueString(), added) {
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to marshal JSON", err.Error())
+		return
+	}
+	if err := state.writeFile(filePath, jsonData, 0644); err != nil {
+		resp.Diagnostics.AddError("Failed to write settings file", err.Error())
+	}
+}
+
+func (r *claudePermissionRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: scope:list:rule (e.g., "project:allow:Bash(make test:*)")
+	parts := strings.SplitN(req.ID, ":", 3)
+	if len(parts) != 3 {
+		resp.Diagnostics.AddError("Invalid import ID", "Import ID must be in format 'scope:list:rule' (e.g., 'project:allow:Bash(make test:*)')")
+		return
+	}
+
+	scope := strings.TrimSpace(parts[0])
+	list := strings.TrimSpace(parts[1])
+	rule := strings.TrimSpace(parts[2])
+
+	if scope != "user" && scope != "project" && scope != "local" {
+		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user', 'project', or 'local'")
+		return
+	}
+	if list != "allow" && list != "ask" && list != "deny" {
+		resp.Diagnostics.AddError("Invalid list", "List must be 'allow', 'ask', or 'deny'")
+		return
+	}
+	if _, _, err := parseClaudePermissionRule(rule); err != nil {
+		resp.Diagnostics.AddError("Invalid rule", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("scope"), scope)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("list"), list)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("rule"), rule)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), claudePermissionRuleID(scope, list, rule))...)
+}
+
+func (r *claudePermissionRuleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*FileClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *FileClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+	r.settings.client = client
+}
+
+// claudePermissionRuleID builds the resource ID from the normalized rule, so equivalent
+// spellings of a rule share an ID.
+func claudePermissionRuleID(scope, list, rule string) string {
+	if parsed, _, err := parseClaudePermissionRule(rule); err == nil {
+		rule = parsed.String()
+	}
+	return fmt.Sprintf("claude-permission-rule-%s-%s-%s", scope, list, rule)
+}
+
+// findPermissionRule returns the indexes of the entries in settings.permissions.<list> that are
+// equivalent to rule.
+func findPermissionRule(settings map[string]interface{}, list, rule string) []int {
+	want, _, err := parseClaudePermissionRule(rule)
+	if err != nil {
+		return nil
+	}
+	perms, _ := settings["permissions"].(map[string]interface{})
+	entries, _ := perms[list].([]interface{})
+
+	var found []int
+	for i, e := range entries {
+		s, ok := e.(string)
+		if !ok {
+			continue
+		}
+		if got, _, err := parseClaudePermissionRule(s); err == nil && got == want {
+			found = append(found, i)
+		}
+	}
+	return found
+}
+
+// addPermissionRule appends rule to settings.permissions.<list> unless an equivalent entry is
+// already present, and reports whether settings changed.
+func addPermissionRule(settings map[string]interface{}, list, rule string) (bool, error) {
+	if len(findPermissionRule(settings, list, rule)) > 0 {
+		return false, nil
+	}
+
+	perms, ok := settings["permissions"].(map[string]interface{})
+	if !ok {
+		if _, exists := settings["permissions"]; exists {
+			return false, fmt.Errorf("permissions in the settings file is not an object")
+		}
+		perms = make(map[string]interface{})
+		settings["permissions"] = perms
+	}
+	entries, ok := perms[list].([]interface{})
+	if !ok && perms[list] != nil {
+		return false, fmt.Errorf("permissions.%s in the settings file is not a list", list)
+	}
+
+	perms[list] = append(entries, rule)
+	return true, nil
+}
+
+// removePermissionRule removes the first entry of settings.permissions.<list> that is exactly
+// rule, leaving equivalent spellings added by others, and drops the list and the permissions
+// object once they are empty. It reports whether settings changed.
+func removePermissionRule(settings map[string]interface{}, list, rule string) bool {
+	perms, _ := settings["permissions"].(map[string]interface{})
+	entries, _ := perms[list].([]interface{})
+	i := slices.Index(entries, interface{}(rule))
+	if i < 0 {
+		return false
+	}
+
+	kept := slices.Delete(slices.Clone(entries), i, i+1)
+
+	if len(kept) > 0 {
+		perms[list] = kept
+	} else {
+		delete(perms, list)
+	}
+	if len(perms) == 0 {
+		delete(settings, "permissions")
+	}
+	return true
+}
@@ -0,0 +1,259 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestClaudePermissionRuleResource_addPermissionRule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		settings      string
+		list          string
+		rule          string
+		expected      string
+		expectChanged bool
+		expectError   bool
+	}{
+		{
+			name:          "empty_file",
+			settings:      `{}`,
+			list:          "allow",
+			rule:          "Bash(make test:*)",
+			expected:      `{"permissions":{"allow":["Bash(make test:*)"]}}`,
+			expectChanged: true,
+		},
+		{
+			name:          "appends_to_existing_list",
+			settings:      `{"model":"opus","permissions":{"allow":["Read"],"defaultMode":"plan"}}`,
+			list:          "allow",
+			rule:          "Bash(make test:*)",
+			expected:      `{"model":"opus","permissions":{"allow":["Read","Bash(make test:*)"],"defaultMode":"plan"}}`,
+			expectChanged: true,
+		},
+		{
+			name:     "adopts_equivalent_rule",
+			settings: `{"permissions":{"deny":["Read(./.env)"]}}`,
+			list:     "deny",
+			rule:     "Read(.env)",
+			expected: `{"permissions":{"deny":["Read(./.env)"]}}`,
+		},
+		{
+			name:          "same_rule_in_other_list",
+			settings:      `{"permissions":{"deny":["Read(.env)"]}}`,
+			list:          "ask",
+			rule:          "Read(.env)",
+			expected:      `{"permissions":{"ask":["Read(.env)"],"deny":["Read(.env)"]}}`,
+			expectChanged: true,
+		},
+		{
+			name:        "permissions_not_an_object",
+			settings:    `{"permissions":["Read"]}`,
+			list:        "allow",
+			rule:        "Read",
+			expectError: true,
+		},
+		{
+			name:        "list_not_a_list",
+			settings:    `{"permissions":{"allow":"Read"}}`,
+			list:        "allow",
+			rule:        "Read",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var settings map[string]interface{}
+			if err := json.Unmarshal([]byte(tc.settings), &settings); err != nil {
+				t.Fatalf("Failed to parse settings: %v", err)
+			}
+
+			changed, err := addPermissionRule(settings, tc.list, tc.rule)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if changed != tc.expectChanged {
+				t.Errorf("Expected changed %v, got %v", tc.expectChanged, changed)
+			}
+			if b, _ := json.Marshal(settings); string(b) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, b)
+			}
+		})
+	}
+}
+
+func TestClaudePermissionRuleResource_removePermissionRule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		settings      string
+		list          string
+		rule          string
+		expected      string
+		expectChanged bool
+	}{
+		{
+			name:          "removes_only_the_rule",
+			settings:      `{"permissions":{"allow":["Read","Bash(make test:*)","Edit"]}}`,
+			list:          "allow",
+			rule:          "Bash(make test:*)",
+			expected:      `{"permissions":{"allow":["Read","Edit"]}}`,
+			expectChanged: true,
+		},
+		{
+			name:          "keeps_equivalent_spellings",
+			settings:      `{"permissions":{"deny":["Read(./.env)","Read(.env)"],"allow":["Read"]}}`,
+			list:          "deny",
+			rule:          "Read(.env)",
+			expected:      `{"permissions":{"allow":["Read"],"deny":["Read(./.env)"]}}`,
+			expectChanged: true,
+		},
+		{
+			name:          "removes_one_copy",
+			settings:      `{"permissions":{"allow":["Edit","Read","Edit"]}}`,
+			list:          "allow",
+			rule:          "Edit",
+			expected:      `{"permissions":{"allow":["Read","Edit"]}}`,
+			expectChanged: true,
+		},
+		{
+			name:          "drops_empty_permissions",
+			settings:      `{"model":"opus","permissions":{"ask":["Bash(git push:*)"]}}`,
+			list:          "ask",
+			rule:          "Bash(git push:*)",
+			expected:      `{"model":"opus"}`,
+			expectChanged: true,
+		},
+		{
+			name:     "missing_rule",
+			settings: `{"permissions":{"allow":["Read"]}}`,
+			list:     "allow",
+			rule:     "Edit",
+			expected: `{"permissions":{"allow":["Read"]}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var settings map[string]interface{}
+			if err := json.Unmarshal([]byte(tc.settings), &settings); err != nil {
+				t.Fatalf("Failed to parse settings: %v", err)
+			}
+
+			if changed := removePermissionRule(settings, tc.list, tc.rule); changed != tc.expectChanged {
+				t.Errorf("Expected changed %v, got %v", tc.expectChanged, changed)
+			}
+			if b, _ := json.Marshal(settings); string(b) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, b)
+			}
+		})
+	}
+}
+
+func TestClaudePermissionRuleResource_claudePermissionRuleID(t *testing.T) {
+	if id := claudePermissionRuleID("project", "deny", "Read(./.env)"); id != "claude-permission-rule-project-deny-Read(.env)" {
+		t.Errorf("Expected ID with normalized rule, got %q", id)
+	}
+	if a, b := claudePermissionRuleID("user", "allow", "Bash( npm test )"), claudePermissionRuleID("user", "allow", "Bash(npm test)"); a != b {
+		t.Errorf("Expected equivalent rules to share an ID, got %q and %q", a, b)
+	}
+}
+
+func TestAccClaudePermissionRuleResource_basic(t *testing.T) {
+	workDir := t.TempDir()
+	settingsPath := filepath.Join(workDir, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	existing := `{"model": "opus", "permissions": {"allow": ["Read", "Bash(make lint)"]}}`
+	if err := os.WriteFile(settingsPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write settings file: %v", err)
+	}
+
+	// Rules are created concurrently, so only the set of entries is checked
+	checkAllow := func(expected ...string) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			settings, err := readSettingsJSON(settingsPath)
+			if err != nil {
+				return err
+			}
+			perms, _ := settings["permissions"].(map[string]interface{})
+			entries, _ := perms["allow"].([]interface{})
+			var allow []string
+			for _, e := range entries {
+				allow = append(allow, e.(string))
+			}
+			sort.Strings(allow)
+			sort.Strings(expected)
+			if !reflect.DeepEqual(allow, expected) {
+				return fmt.Errorf("expected allow %v, got %v", expected, perms["allow"])
+			}
+			if settings["model"] != "opus" {
+				return fmt.Errorf("expected unrelated settings to be preserved, got %v", settings)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The pre-existing rule adopted by "second" is left in place
+		CheckDestroy: checkAllow("Read", "Bash(make lint)"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccClaudePermissionRuleResourceConfig(workDir, "Bash(make test:*)", "Bash(make lint)"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_permission_rule.first", "id", "claude-permission-rule-project-allow-Bash(make test:*)"),
+					checkAllow("Read", "Bash(make test:*)", "Bash(make lint)"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "agentsmith_claude_permission_rule.first",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "project:allow:Bash(make test:*)",
+			},
+			// Replacing one rule leaves the other entries alone
+			{
+				Config: testAccClaudePermissionRuleResourceConfig(workDir, "Bash(make check:*)", "Bash(make lint)"),
+				Check:  checkAllow("Read", "Bash(make lint)", "Bash(make check:*)"),
+			},
+		},
+	})
+}
+
+func testAccClaudePermissionRuleResourceConfig(workDir, first, second string) string {
+	return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_claude_permission_rule" "first" {
+  scope = "project"
+  list  = "allow"
+  rule  = %q
+}
+
+resource "agentsmith_claude_permission_rule" "second" {
+  scope = "project"
+  list  = "allow"
+  rule  = %q
+}
+`, workDir, first, second)
+}
@@ -0,0 +1,377 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// claudeKnownTools lists the built-in Claude Code tools that permission rules can refer to.
+var claudeKnownTools = map[string]struct{}{
+	"Bash": {}, "BashOutput": {}, "Edit": {}, "ExitPlanMode": {}, "Glob": {}, "Grep": {},
+	"KillShell": {}, "LS": {}, "MultiEdit": {}, "NotebookEdit": {}, "NotebookRead": {},
+	"Read": {}, "SlashCommand": {}, "Task": {}, "TodoWrite": {}, "WebFetch": {},
+	"WebSearch": {}, "Write": {},
+}
+
+// claudePathTools are the tools whose specifier is a gitignore-style path pattern.
+var claudePathTools = map[string]struct{}{
+	"Read": {}, "Edit": {}, "Write": {}, "MultiEdit": {}, "NotebookEdit": {}, "NotebookRead": {},
+}
+
+var (
+	claudeToolNamePattern  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	claudeMCPServerPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// claudePermissionRule is a parsed permission rule of the form `Tool` or `Tool(specifier)`.
+type claudePermissionRule struct {
+	Tool      string
+	Specifier string
+}
+
+// String returns the normalized form of the rule.
+func (r claudePermissionRule) String() string {
+	if r.Specifier == "" {
+		return r.Tool
+	}
+	return fmt.Sprintf("%s(%s)", r.Tool, r.Specifier)
+}
+
+// covers reports whether r matches every tool use that other matches.
+func (r claudePermissionRule) covers(other claudePermissionRule) bool {
+	if r.Tool != other.Tool {
+		// mcp__server matches every tool exposed by that server
+		return strings.HasPrefix(r.Tool, "mcp__") && strings.HasPrefix(other.Tool, r.Tool+"__")
+	}
+	if r.Specifier == "" || r.Specifier == other.Specifier {
+		return true
+	}
+	if r.Tool == "Bash" && strings.HasSuffix(r.Specifier, ":*") {
+		// The prefix matches whole words only, so `git:*` covers `git log` but not `gitk`
+		prefix := strings.TrimSuffix(r.Specifier, ":*")
+		rest, ok := strings.CutPrefix(strings.TrimSuffix(other.Specifier, ":*"), prefix)
+		return ok && (rest == "" || rest[0] == ' ' || rest[0] == ':')
+	}
+	return false
+}
+
+// parseClaudePermissionRule parses a rule using Claude Code's `Tool(specifier)` grammar.
+// Specifiers are normalized so equivalent rules compare equal. Problems that do not prevent
+// Claude Code from loading the rule are returned as warnings.
+func parseClaudePermissionRule(raw string) (claudePermissionRule, []string, error) {
+	var rule claudePermissionRule
+	var warnings []string
+
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return rule, nil, fmt.Errorf("rule must not be empty")
+	}
+
+	hasSpecifier := false
+	if open := strings.IndexByte(s, '('); open >= 0 {
+		if !strings.HasSuffix(s, ")") {
+			return rule, nil, fmt.Errorf("rule %q is missing a closing parenthesis", raw)
+		}
+		rule.Tool = strings.TrimSpace(s[:open])
+		rule.Specifier = strings.TrimSpace(s[open+1 : len(s)-1])
+		hasSpecifier = true
+	} else {
+		if strings.ContainsRune(s, ')') {
+			return rule, nil, fmt.Errorf("rule %q has a closing parenthesis without an opening one", raw)
+		}
+		rule.Tool = s
+	}
+
+	if strings.HasPrefix(rule.Tool, "mcp__") {
+		w, err := validateMCPRuleTool(rule.Tool, hasSpecifier)
+		return rule, w, err
+	}
+
+	if !claudeToolNamePattern.MatchString(rule.Tool) {
+		return rule, nil, fmt.Errorf("rule %q has an invalid tool name %q", raw, rule.Tool)
+	}
+	if _, ok := claudeKnownTools[rule.Tool]; !ok {
+		for known := range claudeKnownTools {
+			if strings.EqualFold(known, rule.Tool) {
+				return rule, nil, fmt.Errorf("rule %q refers to unknown tool %q; tool names are case-sensitive, did you mean %q?", raw, rule.Tool, known)
+			}
+		}
+		if rule.Tool[0] < 'A' || rule.Tool[0] > 'Z' {
+			return rule, nil, fmt.Errorf("rule %q is not a permission rule; rules take the form `Tool` or `Tool(specifier)`, e.g. `Bash(npm run test:*)`", raw)
+		}
+		warnings = append(warnings, fmt.Sprintf("rule %q refers to unknown tool %q", raw, rule.Tool))
+	}
+
+	if !hasSpecifier {
+		return rule, warnings, nil
+	}
+	if rule.Specifier == "" {
+		return rule, nil, fmt.Errorf("rule %q has an empty specifier; use %q to match every use of the tool", raw, rule.Tool)
+	}
+	if rule.Specifier == "*" {
+		rule.Specifier = ""
+		return rule, warnings, nil
+	}
+
+	switch rule.Tool {
+	case "Bash":
+		trimmed := strings.TrimSuffix(rule.Specifier, ":*")
+		if strings.Contains(trimmed, "*") {
+			warnings = append(warnings, fmt.Sprintf("rule %q uses a wildcard that is not at the end; Bash rules only support prefix matching with a trailing `:*`", raw))
+		}
+	case "WebFetch":
+		domain, ok := strings.CutPrefix(rule.Specifier, "domain:")
+		domain = strings.TrimSpace(domain)
+		if !ok || domain == "" {
+			return rule, nil, fmt.Errorf("rule %q must use the form `WebFetch(domain:example.com)`", raw)
+		}
+		rule.Specifier = "domain:" + strings.ToLower(domain)
+	default:
+		if _, ok := claudePathTools[rule.Tool]; ok {
+			rule.Specifier = normalizeClaudeRulePath(rule.Specifier)
+		}
+	}
+
+	return rule, warnings, nil
+}
+
+// validateMCPRuleTool validates an `mcp__server` or `mcp__server__tool` rule.
+func validateMCPRuleTool(tool string, hasSpecifier bool) ([]string, error) {
+	if hasSpecifier {
+		return nil, fmt.Errorf("MCP rule %q does not accept a specifier", tool)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(tool, "mcp__"), "__", 2)
+	if !claudeMCPServerPattern.MatchString(parts[0]) {
+		return nil, fmt.Errorf("MCP rule %q has an invalid server name %q", tool, parts[0])
+	}
+	if len(parts) == 2 {
+		if parts[1] == "*" {
+			return []string{fmt.Sprintf("MCP rule %q uses a wildcard, which is not supported; use %q to match every tool of the server", tool, "mcp__"+parts[0])}, nil
+		}
+		if parts[1] == "" {
+			return nil, fmt.Errorf("MCP rule %q has an empty tool name", tool)
+		}
+	}
+	return nil, nil
+}
+
+// normalizeClaudeRulePath cleans a path specifier while keeping its anchor
+// (`//` absolute, `~/` home, `/` settings-relative), so `./src/**` and `src/**` compare equal.
+func normalizeClaudeRulePath(spec string) string {
+	anchor := ""
+	for _, prefix := range []string{"//", "~/", "/"} {
+		if strings.HasPrefix(spec, prefix) {
+			anchor = prefix
+			spec = strings.TrimPrefix(spec, prefix)
+			break
+		}
+	}
+	if spec == "" {
+		return anchor
+	}
+	return anchor + path.Clean(spec)
+}
+
+var _ validator.List = claudePermissionRulesValidator{}
+
+// claudePermissionRulesValidator validates each element of a list of permission rules.
+type claudePermissionRulesValidator struct{}
+
+func (v claudePermissionRulesValidator) Description(_ context.Context) string {
+	return "each element must be a Claude Code permission rule of the form `Tool` or `Tool(specifier)`"
+}
+
+func (v claudePermissionRulesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v claudePermissionRulesValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	seen := make(map[string]int)
+	for i, elem := range req.ConfigValue.Elements() {
+		s, ok := elem.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+
+		rule, warnings, err := parseClaudePermissionRule(s.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(req.Path.AtListIndex(i), "Invalid permission rule", err.Error())
+			continue
+		}
+		for _, w := range warnings {
+			resp.Diagnostics.AddAttributeWarning(req.Path.AtListIndex(i), "Questionable permission rule", w)
+		}
+
+		if j, dup := seen[rule.String()]; dup {
+			resp.Diagnostics.AddAttributeWarning(
+				req.Path.AtListIndex(i),
+				"Duplicate permission rule",
+				fmt.Sprintf("Rule %q is equivalent to the rule at index %d.", s.ValueString(), j),
+			)
+			continue
+		}
+		seen[rule.String()] = i
+	}
+}
+
+// claudePermissionRules returns a list validator for Claude Code permission rules.
+func claudePermissionRules() validator.List {
+	return claudePermissionRulesValidator{}
+}
+
+var _ validator.String = claudePermissionRuleStringValidator{}
+
+// claudePermissionRuleStringValidator validates a single permission rule.
+type claudePermissionRuleStringValidator struct{}
+
+func (v claudePermissionRuleStringValidator) Description(_ context.Context) string {
+	return "value must be a Claude Code permission rule of the form `Tool` or `Tool(specifier)`"
+}
+
+func (v claudePermissionRuleStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v claudePermissionRuleStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	_, warnings, err := parseClaudePermissionRule(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid permission rule", err.Error())
+		return
+	}
+	for _, w := range warnings {
+		resp.Diagnostics.AddAttributeWarning(req.Path, "Questionable permission rule", w)
+	}
+}
+
+// claudePermissionRuleString returns a string validator for a single Claude Code permission rule.
+func claudePermissionRuleString() validator.String {
+	return claudePermissionRuleStringValidator{}
+}
+
+var _ validator.List = claudeToolNamesValidator{}
+
+// claudeToolNamesValidator validates a list of tool names, such as the tools a subagent may use.
+// Names follow the permission rule grammar without a specifier.
+type claudeToolNamesValidator struct{}
+
+func (v claudeToolNamesValidator) Description(_ context.Context) string {
+	return "each element must be a built-in Claude Code tool name or an MCP tool of the form `mcp__<server>__<tool>`"
+}
+
+func (v claudeToolNamesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v claudeToolNamesValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if len(req.ConfigValue.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Empty tool list", "The list must name at least one tool; omit it to allow every tool.")
+		return
+	}
+
+	seen := make(map[string]int)
+	for i, elem := range req.ConfigValue.Elements() {
+		s, ok := elem.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+
+		name := s.ValueString()
+		if strings.ContainsAny(name, "(),") {
+			resp.Diagnostics.AddAttributeError(req.Path.AtListIndex(i), "Invalid tool name", fmt.Sprintf("%q is not a tool name; list each tool separately and without a specifier", name))
+			continue
+		}
+		rule, warnings, err := parseClaudePermissionRule(name)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(req.Path.AtListIndex(i), "Invalid tool name", err.Error())
+			continue
+		}
+		for _, w := range warnings {
+			resp.Diagnostics.AddAttributeWarning(req.Path.AtListIndex(i), "Questionable tool name", w)
+		}
+
+		if j, dup := seen[rule.Tool]; dup {
+			resp.Diagnostics.AddAttributeWarning(
+				req.Path.AtListIndex(i),
+				"Duplicate tool name",
+				fmt.Sprintf("Tool %q is already listed at index %d.", rule.Tool, j),
+			)
+			continue
+		}
+		seen[rule.Tool] = i
+	}
+}
+
+// claudeToolNames returns a list validator for Claude Code tool names.
+func claudeToolNames() validator.List {
+	return claudeToolNamesValidator{}
+}
+
+// claudePermissionRuleList is a named list of rules, ordered from highest to lowest precedence
+// when passed to checkPermissionRuleConflicts.
+type claudePermissionRuleList struct {
+	Name  string
+	Rules types.List
+}
+
+// checkPermissionRuleConflicts warns about rules that can never take effect because a rule
+// in a list with higher precedence (deny, then ask, then allow) matches the same tool uses.
+func checkPermissionRuleConflicts(lists []claudePermissionRuleList, addWarning func(list string, index int, summary, detail string)) {
+	type parsed struct {
+		raw  string
+		rule claudePermissionRule
+	}
+
+	parsedLists := make([][]parsed, len(lists))
+	for n, l := range lists {
+		if l.Rules.IsNull() || l.Rules.IsUnknown() {
+			continue
+		}
+		parsedLists[n] = make([]parsed, len(l.Rules.Elements()))
+		for i, elem := range l.Rules.Elements() {
+			s, ok := elem.(types.String)
+			if !ok || s.IsNull() || s.IsUnknown() {
+				continue
+			}
+			if rule, _, err := parseClaudePermissionRule(s.ValueString()); err == nil {
+				parsedLists[n][i] = parsed{raw: s.ValueString(), rule: rule}
+			}
+		}
+	}
+
+	for lower := range lists {
+		for i, p := range parsedLists[lower] {
+			if p.raw == "" {
+				continue
+			}
+		higher:
+			for higher := 0; higher < lower; higher++ {
+				for _, q := range parsedLists[higher] {
+					if q.raw != "" && q.rule.covers(p.rule) {
+						addWarning(lists[lower].Name, i, "Conflicting permission rule", fmt.Sprintf(
+							"Rule %q in %s never takes effect because %s rule %q matches the same tool uses and takes precedence.",
+							p.raw, lists[lower].Name, lists[higher].Name, q.raw,
+						))
+						break higher
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseClaudePermissionRule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rule          string
+		expected      string
+		expectError   bool
+		expectWarning bool
+	}{
+		{name: "bare_tool", rule: "Bash", expected: "Bash"},
+		{name: "bash_prefix", rule: "Bash(npm run test:*)", expected: "Bash(npm run test:*)"},
+		{name: "bash_exact", rule: " Bash( git status ) ", expected: "Bash(git status)"},
+		{name: "bash_inner_wildcard", rule: "Bash(npm * test)", expected: "Bash(npm * test)", expectWarning: true},
+		{name: "wildcard_specifier", rule: "Edit(*)", expected: "Edit"},
+		{name: "read_relative_path", rule: "Read(./secrets/**)", expected: "Read(secrets/**)"},
+		{name: "read_plain_path", rule: "Read(secrets/**)", expected: "Read(secrets/**)"},
+		{name: "read_absolute_path", rule: "Read(//etc/passwd)", expected: "Read(//etc/passwd)"},
+		{name: "read_home_path", rule: "Read(~/.ssh/**)", expected: "Read(~/.ssh/**)"},
+		{name: "edit_settings_relative_path", rule: "Edit(/docs//*.md)", expected: "Edit(/docs/*.md)"},
+		{name: "webfetch_domain", rule: "WebFetch(domain:Example.com)", expected: "WebFetch(domain:example.com)"},
+		{name: "webfetch_missing_domain", rule: "WebFetch(example.com)", expectError: true},
+		{name: "webfetch_empty_domain", rule: "WebFetch(domain:)", expectError: true},
+		{name: "mcp_server", rule: "mcp__github", expected: "mcp__github"},
+		{name: "mcp_tool", rule: "mcp__github__get_issue", expected: "mcp__github__get_issue"},
+		{name: "mcp_wildcard", rule: "mcp__github__*", expected: "mcp__github__*", expectWarning: true},
+		{name: "mcp_specifier", rule: "mcp__github(repo)", expectError: true},
+		{name: "mcp_empty_tool", rule: "mcp__github__", expectError: true},
+		{name: "unknown_tool", rule: "Deploy(prod)", expected: "Deploy(prod)", expectWarning: true},
+		{name: "wrong_case_tool", rule: "read", expectError: true},
+		{name: "not_a_rule", rule: "network", expectError: true},
+		{name: "empty_rule", rule: "  ", expectError: true},
+		{name: "empty_specifier", rule: "Bash()", expectError: true},
+		{name: "missing_closing_paren", rule: "Bash(npm run", expectError: true},
+		{name: "stray_closing_paren", rule: "Bash)", expectError: true},
+		{name: "invalid_tool_name", rule: "Web Fetch", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, warnings, err := parseClaudePermissionRule(tc.rule)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q but got none", tc.rule)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error for %q: %v", tc.rule, err)
+			}
+			if rule.String() != tc.expected {
+				t.Errorf("Expected normalized rule %q, got %q", tc.expected, rule.String())
+			}
+			if tc.expectWarning != (len(warnings) > 0) {
+				t.Errorf("Expected warning %v, got %v", tc.expectWarning, warnings)
+			}
+		})
+	}
+}
+
+func TestClaudePermissionRule_covers(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     string
+		other    string
+		expected bool
+	}{
+		{name: "same_rule", rule: "Read(./.env)", other: "Read(.env)", expected: true},
+		{name: "bare_tool", rule: "Bash", other: "Bash(npm test)", expected: true},
+		{name: "bash_prefix", rule: "Bash(npm:*)", other: "Bash(npm run test:*)", expected: true},
+		{name: "bash_prefix_mismatch", rule: "Bash(npm:*)", other: "Bash(yarn test)", expected: false},
+		{name: "bash_prefix_exact", rule: "Bash(git:*)", other: "Bash(git)", expected: true},
+		{name: "bash_prefix_word", rule: "Bash(git:*)", other: "Bash(gitk)", expected: false},
+		{name: "bash_prefix_word_wildcard", rule: "Bash(git:*)", other: "Bash(github-cli:*)", expected: false},
+		{name: "mcp_server", rule: "mcp__github", other: "mcp__github__get_issue", expected: true},
+		{name: "mcp_other_server", rule: "mcp__github", other: "mcp__gitlab__get_issue", expected: false},
+		{name: "different_tools", rule: "Read", other: "Edit(src/**)", expected: false},
+		{name: "narrower_rule", rule: "Bash(npm test)", other: "Bash", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, _, err := parseClaudePermissionRule(tc.rule)
+			if err != nil {
+				t.Fatalf("Failed to parse %q: %v", tc.rule, err)
+			}
+			other, _, err := parseClaudePermissionRule(tc.other)
+			if err != nil {
+				t.Fatalf("Failed to parse %q: %v", tc.other, err)
+			}
+			if rule.covers(other) != tc.expected {
+				t.Errorf("Expected %q covers %q to be %v", tc.rule, tc.other, tc.expected)
+			}
+		})
+	}
+}
+
+func TestClaudePermissionRulesValidator(t *testing.T) {
+	ctx := context.Background()
+	list, _ := types.ListValueFrom(ctx, types.StringType, []string{"Bash(npm test)", "read", "Read(./.env)", "Read(.env)"})
+
+	req := validator.ListRequest{Path: path.Root("permissions").AtName("allow"), ConfigValue: list}
+	resp := &validator.ListResponse{}
+	claudePermissionRules().ValidateList(ctx, req, resp)
+
+	if resp.Diagnostics.ErrorsCount() != 1 {
+		t.Fatalf("Expected 1 error, got %v", resp.Diagnostics.Errors())
+	}
+	if p := resp.Diagnostics.Errors()[0].(interface{ Path() path.Path }).Path(); !p.Equal(req.Path.AtListIndex(1)) {
+		t.Errorf("Expected error at index 1, got %s", p)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("Expected 1 duplicate warning, got %v", resp.Diagnostics.Warnings())
+	}
+	if p := resp.Diagnostics.Warnings()[0].(interface{ Path() path.Path }).Path(); !p.Equal(req.Path.AtListIndex(3)) {
+		t.Errorf("Expected warning at index 3, got %s", p)
+	}
+}
+
+func TestClaudeToolNamesValidator(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name          string
+		tools         []string
+		expectError   bool
+		expectWarning bool
+	}{
+		{name: "built_in_tools", tools: []string{"Read", "Grep", "Glob"}},
+		{name: "mcp_tools", tools: []string{"mcp__github__get_issue", "mcp__memory"}},
+		{name: "unknown_tool", tools: []string{"Deploy"}, expectWarning: true},
+		{name: "duplicate_tool", tools: []string{"Read", "Read"}, expectWarning: true},
+		{name: "wrong_case_tool", tools: []string{"grep"}, expectError: true},
+		{name: "specifier", tools: []string{"Bash(git status)"}, expectError: true},
+		{name: "comma_separated", tools: []string{"Read, Grep"}, expectError: true},
+		{name: "invalid_mcp_tool", tools: []string{"mcp__github__"}, expectError: true},
+		{name: "empty_list", tools: []string{}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			list, _ := types.ListValueFrom(ctx, types.StringType, tc.tools)
+			req := validator.ListRequest{Path: path.Root("tools"), ConfigValue: list}
+			resp := &validator.ListResponse{}
+			claudeToolNames().ValidateList(ctx, req, resp)
+
+			if tc.expectError != resp.Diagnostics.HasError() {
+				t.Errorf("Expected error %v, got %v", tc.expectError, resp.Diagnostics.Errors())
+			}
+			if tc.expectWarning != (resp.Diagnostics.WarningsCount() > 0) {
+				t.Errorf("Expected warning %v, got %v", tc.expectWarning, resp.Diagnostics.Warnings())
+			}
+		})
+	}
+}
+
+func TestCheckPermissionRuleConflicts(t *testing.T) {
+	ctx := context.Background()
+	deny, _ := types.ListValueFrom(ctx, types.StringType, []string{"Bash(rm:*)", "Read(./.env)"})
+	ask, _ := types.ListValueFrom(ctx, types.StringType, []string{"Bash(git push:*)"})
+	allow, _ := types.ListValueFrom(ctx, types.StringType, []string{"Bash(npm test)", "Read(.env)", "Bash(git push origin main)", "Bash(rm -rf build)"})
+
+	type warning struct {
+		list  string
+		index int
+	}
+	var got []warning
+	checkPermissionRuleConflicts(
+		[]claudePermissionRuleList{
+			{Name: "deny", Rules: deny},
+			{Name: "ask", Rules: ask},
+			{Name: "allow", Rules: allow},
+		},
+		func(list string, index int, _, _ string) {
+			got = append(got, warning{list, index})
+		},
+	)
+
+	expected := []warning{{"allow", 1}, {"allow", 2}, {"allow", 3}}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected warnings %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected warning %v, got %v", expected[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-agentsmith/internal/fileio"
+)
+
+var (
+	_ resource.Resource                = &claudeProjectConfigResource{}
+	_ resource.ResourceWithConfigure   = &claudeProjectConfigResource{}
+	_ resource.ResourceWithImportState = &claudeProjectConfigResource{}
+)
+
+func NewClaudeProjectConfigResource() resource.Resource {
+	return &claudeProjectConfigResource{}
+}
+
+// claudeProjectConfigResource owns part of a `projects.<path>` entry in the Claude Code global
+// state file. Claude Code keeps its own runtime state in the same entry.
+type claudeProjectConfigResource struct {
+	client *FileClient
+}
+
+type claudeProjectConfigResourceModel struct {
+	ID                     types.String `tfsdk:"id"`
+	ProjectPath            types.String `tfsdk:"project_path"`
+	McpServers             types.Map    `tfsdk:"mcp_servers"`
+	AllowedTools           types.List   `tfsdk:"allowed_tools"`
+	HasTrustDialogAccepted types.Bool   `tfsdk:"has_trust_dialog_accepted"`
+	fileWriteOptionsModel
+}
+
+func (r *claudeProjectConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claude_project_config"
+}
+
+func (r *claudeProjectConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the entry of a project under `projects` in the Claude Code global state file, `~/.claude.json` (or `$CLAUDE_CONFIG_DIR/.claude.json`). Only the attributes set here are managed; the rest of the entry and of the file is preserved.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The absolute project path, which is the key of the entry.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_path": schema.StringAttribute{
+				Description: "The project directory. Defaults to the provider's working directory. Relative paths are resolved against the current directory.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"mcp_servers": schema.MapAttribute{
+				Description: "A map of MCP server name to its JSON definition, written to the project's `mcpServers` key (the `local` scope of `claude mcp add`). Values can be the `json` output of the `agentsmith_mcp_stdio` and `agentsmith_mcp_remote` data sources. Only the servers named here are managed.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"allowed_tools": schema.ListAttribute{
+				Description: "Permission rules the project has allowed, e.g. `Bash(npm run test:*)`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators:  []validator.List{claudePermissionRules()},
+			},
+			"has_trust_dialog_accepted": schema.BoolAttribute{
+				Description: "Whether the workspace trust dialog has been accepted for the project.",
+				Optional:    true,
+			},
+		},
+	}
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0600")
+}
+
+func (r *claudeProjectConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan claudeProjectConfigResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectPath := plan.ProjectPath.ValueString()
+	if plan.ProjectPath.IsNull() || plan.ProjectPath.IsUnknown() {
+		if r.client == nil {
+			resp.Diagnostics.AddError("Missing project path", "project_path must be set when the provider has no working directory")
+			return
+		}
+		projectPath = r.client.workDir
+	}
+	key, err := claudeProjectKey(projectPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid project path", err.Error())
+		return
+	}
+
+	filePath := claudeStateFilePath()
+	if filePath == "" {
+		resp.Diagnostics.AddError("Failed to locate global config file", "Unable to determine the user home directory")
+		return
+	}
+	defer fileio.Lock(filePath)()
+
+	r.writeProject(ctx, &resp.Diagnostics, &plan, nil, filePath, key)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(key)
+	plan.ProjectPath = types.StringValue(projectPath)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudeProjectConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state claudeProjectConfigResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config, err := readSettingsJSON(claudeStateFilePath())
+	if os.IsNotExist(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read global config file", err.Error())
+		return
+	}
+
+	projects, _ := config["projects"].(map[string]interface{})
+	project, ok := projects[state.ID.ValueString()].(map[string]interface{})
+	if !ok {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// A freshly imported resource adopts the values it finds; otherwise only the attributes
+	// already in state are refreshed.
+	imported, d := req.Private.GetKey(ctx, claudeSettingsImportedKey)
+	resp.Diagnostics.Append(d...)
+	if len(imported) > 0 {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, nil)...)
+	}
+	r.projectToModel(ctx, &resp.Diagnostics, &state, project, len(imported) > 0)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudeProjectConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state claudeProjectConfigResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filePath := claudeStateFilePath()
+	if filePath == "" {
+		resp.Diagnostics.AddError("Failed to locate global config file", "Unable to determine the user home directory")
+		return
+	}
+	defer fileio.Lock(filePath)()
+
+	r.writeProject(ctx, &resp.Diagnostics, &plan, &state, filePath, state.ID.ValueString())
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *claudeProjectConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state claudeProjectConfigResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filePath := claudeStateFilePath()
+	if filePath == "" {
+		return
+	}
+	defer fileio.Lock(filePath)()
+
+	r.writeProject(ctx, &resp.Diagnostics, nil, &state, filePath, state.ID.ValueString())
+}
+
+func (r *claudeProjectConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: the project path (e.g., "/home/me/src/app")
+	key, err := claudeProjectKey(req.ID)
+	if err != nil || req.ID == "" {
+		resp.Diagnostics.AddError("Invalid import ID", "Import ID must be the project path (e.g., '/home/me/src/app')")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), key)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_path"), req.ID)...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, []byte("true"))...)
+}
+
+func (r *claudeProjectConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*FileClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *FileClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// renderProject returns the keys of the project entry managed by model.
+func (r *claudeProjectConfigResource) renderProject(ctx context.Context, diags *diag.Diagnostics, model *claudeProjectConfigResourceModel) (map[string]interface{}, error) {
+	project := make(map[string]interface{})
+
+	servers, err := claudeMCPServersValue(ctx, diags, model.McpServers)
+	if err != nil {
+		return nil, err
+	}
+	if servers != nil {
+		project["mcpServers"] = servers
+	}
+	if !model.AllowedTools.IsNull() && !model.AllowedTools.IsUnknown() {
+		var tools []string
+		diags.Append(model.AllowedTools.ElementsAs(ctx, &tools, false)...)
+		list := make([]interface{}, len(tools))
+		for i, tool := range tools {
+			list[i] = tool
+		}
+		project["allowedTools"] = list
+	}
+	if !model.HasTrustDialogAccepted.IsNull() && !model.HasTrustDialogAccepted.IsUnknown() {
+		project["hasTrustDialogAccepted"] = model.HasTrustDialogAccepted.ValueBool()
+	}
+
+	return project, nil
+}
+
+// writeProject replaces the keys of the project entry managed by prior with those managed by
+// plan and writes the file. A nil plan removes the managed keys, and the entry once it is empty.
+func (r *claudeProjectConfigResource) writeProject(ctx context.Context, diags *diag.Diagnostics, plan, prior *claudeProjectConfigResourceModel, filePath, key string) {
+	config, err := readSettingsJSON(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		diags.AddError("Failed to read global config file", err.Error())
+		return
+	}
+	if config == nil {
+		if plan == nil {
+			return
+		}
+		config = make(map[string]interface{})
+	}
+
+	projects, ok := config["projects"].(map[string]interface{})
+	if !ok {
+		if _, exists := config["projects"]; exists {
+			diags.AddError("Failed to update project entry", "projects in the global config file is not an object")
+			return
+		}
+		projects = make(map[string]interface{})
+	}
+	project, ok := projects[key].(map[string]interface{})
+	if !ok {
+		if plan == nil {
+			return
+		}
+		project = make(map[string]interface{})
+	}
+
+	var previous, desired map[string]interface{}
+	if prior != nil {
+		if previous, err = r.renderProject(ctx, diags, prior); err != nil {
+			diags.AddError("Failed to create project config data", err.Error())
+			return
+		}
+	}
+	opts := prior
+	if plan != nil {
+		if desired, err = r.renderProject(ctx, diags, plan); err != nil {
+			diags.AddError("Failed to create project config data", err.Error())
+			return
+		}
+		opts = plan
+	}
+	if diags.HasError() {
+		return
+	}
+
+	if project = applyOwnedSettings(project, previous, desired); len(project) > 0 {
+		projects[key] = project
+	} else {
+		delete(projects, key)
+	}
+	if len(projects) > 0 {
+		config["projects"] = projects
+	} else {
+		delete(config, "projects")
+	}
+
+	jsonData, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		diags.AddError("Failed to marshal JSON", err.Error())
+		return
+	}
+	if err := opts.writeFile(filePath, jsonData, 0600); err != nil {
+		diags.AddError("Failed to write global config file", err.Error())
+	}
+}
+
+// projectToModel refreshes the attributes of model that are set from the project entry, so that
+// values written by Claude Code or by hand are not adopted and then removed on the next apply.
+// An imported model adopts the values that differ from the defaults Claude Code records for
+// every project it opens: an empty allowedTools list and an unaccepted trust dialog.
+func (r *claudeProjectConfigResource) projectToModel(ctx context.Context, diags *diag.Diagnostics, model *claudeProjectConfigResourceModel, project map[string]interface{}, imported bool) {
+	model.McpServers = refreshOwnedMCPServers(ctx, diags, model.McpServers, project)
+
+	tools, _ := project["allowedTools"].([]interface{})
+	if !model.AllowedTools.IsNull() || (imported && len(tools) > 0) {
+		values := make([]string, 0, len(tools))
+		for _, tool := range tools {
+			if s, ok := tool.(string); ok {
+				values = append(values, s)
+			}
+		}
+		list, d := types.ListValueFrom(ctx, types.StringType, values)
+		diags.Append(d...)
+		model.AllowedTools = list
+	}
+
+	trusted, _ := project["hasTrustDialogAccepted"].(bool)
+	if !model.HasTrustDialogAccepted.IsNull() || (imported && trusted) {
+		model.HasTrustDialogAccepted = types.BoolValue(trusted)
+	}
+}
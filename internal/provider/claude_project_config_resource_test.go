@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestClaudeProjectConfigResource_writeProject(t *testing.T) {
+	r := &claudeProjectConfigResource{}
+	ctx := context.Background()
+	configPath := filepath.Join(t.TempDir(), ".claude.json")
+	key := "/src/app"
+
+	existing := `{"theme":"dark","projects":{"/src/app":{"allowedTools":[],"lastCost":0.5,"mcpServers":{"manual":{"type":"stdio","command":"manual-server"}}},"/src/other":{"allowedTools":["Read"]}}}`
+	if err := os.WriteFile(configPath, []byte(existing), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	servers, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{
+		"docs": `{"url":"https://mcp.example.com/mcp","transport":"streamable-http"}`,
+	})
+	tools, _ := types.ListValueFrom(ctx, types.StringType, []string{"Bash(npm run test:*)"})
+	plan := claudeProjectConfigResourceModel{
+		McpServers:             servers,
+		AllowedTools:           tools,
+		HasTrustDialogAccepted: types.BoolValue(true),
+	}
+
+	var diags diag.Diagnostics
+	r.writeProject(ctx, &diags, &plan, nil, configPath, key)
+	if diags.HasError() {
+		t.Fatalf("Failed to write project: %v", diags.Errors())
+	}
+
+	config, err := readSettingsJSON(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	projects := config["projects"].(map[string]interface{})
+	project := projects[key].(map[string]interface{})
+	if project["lastCost"] != 0.5 || project["hasTrustDialogAccepted"] != true {
+		t.Errorf("Expected managed keys next to runtime state, got %v", project)
+	}
+	if tools := project["allowedTools"].([]interface{}); len(tools) != 1 || tools[0] != "Bash(npm run test:*)" {
+		t.Errorf("Expected allowedTools to be replaced, got %v", tools)
+	}
+	if servers := project["mcpServers"].(map[string]interface{}); len(servers) != 2 {
+		t.Errorf("Expected managed server next to the manual one, got %v", servers)
+	}
+	if config["theme"] != "dark" || projects["/src/other"] == nil {
+		t.Errorf("Expected the rest of the file to be kept, got %v", config)
+	}
+
+	// Read only reports the managed servers
+	var state claudeProjectConfigResourceModel
+	state.McpServers = plan.McpServers
+	r.projectToModel(ctx, &diags, &state, project, true)
+	if len(state.McpServers.Elements()) != 1 || !state.HasTrustDialogAccepted.ValueBool() || len(state.AllowedTools.Elements()) != 1 {
+		t.Errorf("Unexpected refreshed model: %+v", state)
+	}
+
+	// Removing the resource keeps the entry while Claude Code's own keys remain
+	r.writeProject(ctx, &diags, nil, &plan, configPath, key)
+	if diags.HasError() {
+		t.Fatalf("Failed to remove project: %v", diags.Errors())
+	}
+	config, _ = readSettingsJSON(configPath)
+	project = config["projects"].(map[string]interface{})[key].(map[string]interface{})
+	if _, ok := project["allowedTools"]; ok {
+		t.Error("Expected allowedTools to be removed")
+	}
+	if servers := project["mcpServers"].(map[string]interface{}); len(servers) != 1 || servers["manual"] == nil {
+		t.Errorf("Expected only the manual server to remain, got %v", servers)
+	}
+
+	// An entry left empty is dropped
+	r.writeProject(ctx, &diags, &plan, nil, configPath, "/src/new")
+	r.writeProject(ctx, &diags, nil, &plan, configPath, "/src/new")
+	if diags.HasError() {
+		t.Fatalf("Failed to write project: %v", diags.Errors())
+	}
+	config, _ = readSettingsJSON(configPath)
+	if _, ok := config["projects"].(map[string]interface{})["/src/new"]; ok {
+		t.Error("Expected empty project entry to be removed")
+	}
+}
+
+func TestClaudeProjectConfigResource_projectToModel(t *testing.T) {
+	r := &claudeProjectConfigResource{}
+	ctx := context.Background()
+
+	// Claude Code's defaults are not reported for unset attributes
+	model := claudeProjectConfigResourceModel{
+		McpServers:             types.MapNull(types.StringType),
+		AllowedTools:           types.ListNull(types.StringType),
+		HasTrustDialogAccepted: types.BoolNull(),
+	}
+	var diags diag.Diagnostics
+	r.projectToModel(ctx, &diags, &model, map[string]interface{}{
+		"allowedTools":           []interface{}{},
+		"hasTrustDialogAccepted": false,
+		"mcpServers":             map[string]interface{}{"manual": map[string]interface{}{"command": "x"}},
+	}, true)
+	if !model.AllowedTools.IsNull() || !model.HasTrustDialogAccepted.IsNull() || !model.McpServers.IsNull() {
+		t.Errorf("Expected unset attributes to stay null, got %+v", model)
+	}
+
+	// Values written outside Terraform are not adopted by unset attributes
+	project := map[string]interface{}{
+		"allowedTools":           []interface{}{"Read"},
+		"hasTrustDialogAccepted": true,
+	}
+	r.projectToModel(ctx, &diags, &model, project, false)
+	if !model.AllowedTools.IsNull() || !model.HasTrustDialogAccepted.IsNull() {
+		t.Errorf("Expected unset attributes to stay null, got %+v", model)
+	}
+
+	// An import adopts values that differ from the defaults
+	r.projectToModel(ctx, &diags, &model, project, true)
+	if len(model.AllowedTools.Elements()) != 1 || !model.HasTrustDialogAccepted.ValueBool() {
+		t.Errorf("Expected imported values to be adopted, got %+v", model)
+	}
+
+	// Set attributes report drift
+	model.HasTrustDialogAccepted = types.BoolValue(true)
+	r.projectToModel(ctx, &diags, &model, map[string]interface{}{"allowedTools": []interface{}{}}, false)
+	if len(model.AllowedTools.Elements()) != 0 || model.HasTrustDialogAccepted.ValueBool() {
+		t.Errorf("Expected drift to be reported, got %+v", model)
+	}
+}
+
+func TestAccClaudeProjectConfigResource_basic(t *testing.T) {
+	workDir := t.TempDir()
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	configPath := filepath.Join(configDir, ".claude.json")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccClaudeProjectConfigResourceConfig(workDir, `"Bash(make test:*)"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_project_config.test", "id", workDir),
+					resource.TestCheckResourceAttr("agentsmith_claude_project_config.test", "project_path", workDir),
+					resource.TestCheckResourceAttr("agentsmith_claude_project_config.test", "allowed_tools.#", "1"),
+					resource.TestCheckResourceAttr("agentsmith_claude_project_config.test", "mcp_servers.%", "1"),
+					testAccCheckClaudeProjectEntry(configPath, workDir, true),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "agentsmith_claude_project_config.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateId:           workDir,
+				ImportStateVerifyIgnore: []string{"mcp_servers"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccClaudeProjectConfigResourceConfig(workDir, `"Bash(make test:*)", "Read(./docs/**)"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_project_config.test", "allowed_tools.#", "2"),
+					resource.TestCheckResourceAttr("agentsmith_claude_project_config.test", "allowed_tools.1", "Read(./docs/**)"),
+				),
+			},
+		},
+		CheckDestroy: testAccCheckClaudeProjectEntry(configPath, workDir, false),
+	})
+}
+
+func testAccCheckClaudeProjectEntry(configPath, projectPath string, exists bool) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		config, err := readSettingsJSON(configPath)
+		if err != nil {
+			return err
+		}
+		projects, _ := config["projects"].(map[string]interface{})
+		if _, ok := projects[projectPath]; ok != exists {
+			return fmt.Errorf("expected project entry for %s to exist: %v, got %v", projectPath, exists, projects)
+		}
+		return nil
+	}
+}
+
+func testAccClaudeProjectConfigResourceConfig(workDir, allowedTools string) string {
+	return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+data "agentsmith_mcp_remote" "docs" {
+  url       = "https://mcp.example.com/mcp"
+  transport = "streamable-http"
+}
+
+resource "agentsmith_claude_project_config" "test" {
+  allowed_tools             = [%s]
+  has_trust_dialog_accepted = true
+
+  mcp_servers = {
+    docs = data.agentsmith_mcp_remote.docs.json
+  }
+}
+`, workDir, allowedTools)
+}
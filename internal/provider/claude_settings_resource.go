@@ -1,11 +1,13 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -13,14 +15,21 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"terraform-provider-agentsmith/internal/codex/configio"
+	"terraform-provider-agentsmith/internal/fileio"
 )
 
 var (
-	_ resource.Resource                = &claudeSettingsResource{}
-	_ resource.ResourceWithConfigure   = &claudeSettingsResource{}
-	_ resource.ResourceWithImportState = &claudeSettingsResource{}
+	_ resource.Resource                   = &claudeSettingsResource{}
+	_ resource.ResourceWithConfigure      = &claudeSettingsResource{}
+	_ resource.ResourceWithImportState    = &claudeSettingsResource{}
+	_ resource.ResourceWithValidateConfig = &claudeSettingsResource{}
+	_ resource.ResourceWithUpgradeState   = &claudeSettingsResource{}
 )
 
 func NewClaudeSettingsResource() resource.Resource {
@@ -31,9 +40,28 @@ type claudeSettingsResource struct {
 	client *FileClient
 }
 
+// claudeSettingsImportedKey is the private state key set by ImportState, so that the first Read
+// populates every attribute from the file rather than only the ones already in state.
+const claudeSettingsImportedKey = "imported"
+
+// claudeSettingsWrittenKey is the private state key recording the key paths last written to the
+// settings file, so that keys written by other resources or by hand are left alone.
+const claudeSettingsWrittenKey = "written_keys"
+
+// privateStateReader and privateStateWriter are implemented by the private state of resource
+// requests and responses.
+type privateStateReader interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateStateWriter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
 type claudeSettingsResourceModel struct {
 	ID                         types.String                    `tfsdk:"id"`
 	Scope                      types.String                    `tfsdk:"scope"`
+	MergeStrategy              types.String                    `tfsdk:"merge_strategy"`
 	APIKeyHelper               types.String                    `tfsdk:"api_key_helper"`
 	CleanupPeriodDays          types.Int64                     `tfsdk:"cleanup_period_days"`
 	Env                        types.Map                       `tfsdk:"env"`
@@ -50,7 +78,18 @@ type claudeSettingsResourceModel struct {
 	DisabledMcpjsonServers     types.List                      `tfsdk:"disabled_mcpjson_servers"`
 	Permissions                *claudeSettingsPermissionsModel `tfsdk:"permissions"`
 	StatusLine                 *claudeSettingsStatusLineModel  `tfsdk:"status_line"`
-	Hooks                      types.Map                       `tfsdk:"hooks"`
+	Hooks                      []claudeSettingsHookModel       `tfsdk:"hooks"`
+	ExtraSettings              types.String                    `tfsdk:"extra_settings"`
+	fileWriteOptionsModel
+}
+
+// claudeSettingsKnownKeys is the set of top-level settings.json keys managed by this resource.
+var claudeSettingsKnownKeys = map[string]struct{}{
+	"apiKeyHelper": {}, "cleanupPeriodDays": {}, "env": {}, "includeCoAuthoredBy": {}, "model": {},
+	"outputStyle": {}, "forceLoginMethod": {}, "forceLoginOrgUUID": {}, "disableAllHooks": {},
+	"awsAuthRefresh": {}, "awsCredentialExport": {}, "enableAllProjectMcpServers": {},
+	"enabledMcpjsonServers": {}, "disabledMcpjsonServers": {}, "permissions": {}, "statusLine": {},
+	"hooks": {},
 }
 
 type claudeSettingsPermissionsModel struct {
@@ -67,12 +106,32 @@ type claudeSettingsStatusLineModel struct {
 	Command types.String `tfsdk:"command"`
 }
 
+// claudeSettingsHookModel is a single matcher entry registered for a hook event.
+type claudeSettingsHookModel struct {
+	Event   types.String                     `tfsdk:"event"`
+	Matcher types.String                     `tfsdk:"matcher"`
+	Hook    []claudeSettingsHookCommandModel `tfsdk:"hook"`
+}
+
+type claudeSettingsHookCommandModel struct {
+	Type    types.String `tfsdk:"type"`
+	Command types.String `tfsdk:"command"`
+	Timeout types.Int64  `tfsdk:"timeout"`
+}
+
+// claudeHookEvents lists the hook events supported by Claude Code, in the order they are documented.
+var claudeHookEvents = []string{
+	"PreToolUse", "PostToolUse", "Notification", "UserPromptSubmit", "Stop",
+	"SubagentStop", "PreCompact", "SessionStart", "SessionEnd",
+}
+
 func (r *claudeSettingsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_claude_settings"
 }
 
 func (r *claudeSettingsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     1,
 		Description: "Manages a Claude Code `settings.json` file. This resource can operate at different scopes to manage user-level, project-level, or local project-level settings.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -89,6 +148,13 @@ func (r *claudeSettingsResource) Schema(_ context.Context, _ resource.SchemaRequ
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"merge_strategy": schema.StringAttribute{
+				Description: "Defines how to handle an existing settings file. `preserve_unknown` (default) merges managed settings while keeping keys this resource does not manage. `replace_all` overwrites the file with only managed settings. `fail_on_unknown` fails if the existing file contains unmanaged keys.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringOneOf("preserve_unknown", "replace_all", "fail_on_unknown"),
+				},
+			},
 			"api_key_helper": schema.StringAttribute{
 				Description: "A custom script, executed in `/bin/sh`, to generate an auth value for model requests.",
 				Optional:    true,
@@ -148,13 +214,49 @@ func (r *claudeSettingsResource) Schema(_ context.Context, _ resource.SchemaRequ
 				ElementType: types.StringType,
 				Optional:    true,
 			},
-			"hooks": schema.MapAttribute{
-				Description: "A map of custom commands to run before or after tool executions. The map key is the hook name (e.g., `PreToolUse`) and the value is another map containing the hook details.",
-				ElementType: types.MapType{ElemType: types.StringType},
-				Optional:    true,
-			},
 		},
 		Blocks: map[string]schema.Block{
+			"hooks": schema.ListNestedBlock{
+				Description: "Custom commands to run at points in the Claude Code lifecycle, such as before or after tool executions. Each block registers the nested `hook` commands for one event and matcher.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"event": schema.StringAttribute{
+							Description: "The hook event. Must be one of `PreToolUse`, `PostToolUse`, `Notification`, `UserPromptSubmit`, `Stop`, `SubagentStop`, `PreCompact`, `SessionStart`, or `SessionEnd`.",
+							Required:    true,
+							Validators: []validator.String{
+								stringOneOf(claudeHookEvents...),
+							},
+						},
+						"matcher": schema.StringAttribute{
+							Description: "A pattern to match tool names (e.g., `Bash` or `Edit|Write`). Only applicable to `PreToolUse` and `PostToolUse`.",
+							Optional:    true,
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"hook": schema.ListNestedBlock{
+							Description: "An ordered list of hook commands to execute.",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"type": schema.StringAttribute{
+										Description: "The type of hook. Defaults to `command`.",
+										Optional:    true,
+										Computed:    true,
+										Default:     stringdefault.StaticString("command"),
+									},
+									"command": schema.StringAttribute{
+										Description: "The bash command to execute.",
+										Required:    true,
+									},
+									"timeout": schema.Int64Attribute{
+										Description: "How long the command may run, in seconds, before it is cancelled.",
+										Optional:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 			"permissions": schema.SingleNestedBlock{
 				Description: "A block for configuring tool usage permissions.",
 				Attributes: map[string]schema.Attribute{
@@ -162,16 +264,25 @@ func (r *claudeSettingsResource) Schema(_ context.Context, _ resource.SchemaRequ
 						Description: "A list of permission rules to automatically allow tool use without prompting.",
 						ElementType: types.StringType,
 						Optional:    true,
+						Validators: []validator.List{
+							claudePermissionRules(),
+						},
 					},
 					"ask": schema.ListAttribute{
 						Description: "A list of permission rules that will cause Claude to ask for confirmation before using a tool.",
 						ElementType: types.StringType,
 						Optional:    true,
+						Validators: []validator.List{
+							claudePermissionRules(),
+						},
 					},
 					"deny": schema.ListAttribute{
 						Description: "A list of permission rules to deny tool use. Also used to exclude sensitive files from being read.",
 						ElementType: types.StringType,
 						Optional:    true,
+						Validators: []validator.List{
+							claudePermissionRules(),
+						},
 					},
 					"additional_directories": schema.ListAttribute{
 						Description: "A list of additional working directories that Claude has access to.",
@@ -203,45 +314,68 @@ func (r *claudeSettingsResource) Schema(_ context.Context, _ resource.SchemaRequ
 			},
 		},
 	}
+	resp.Schema.Attributes["extra_settings"] = extraSettingsAttribute("`settings.json`")
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0644")
 }
 
-func (r *claudeSettingsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan claudeSettingsResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+func (r *claudeSettingsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config claudeSettingsResourceModel
+	// Blocks that are not known yet cannot be decoded; they are checked again on apply.
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
 		return
 	}
 
-	filePath := r.getSettingsFilePath(plan.Scope.ValueString())
-	if filePath == "" {
-		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user', 'project', or 'local'")
-		return
+	r.validateSettingsConfig(ctx, &resp.Diagnostics, &config)
+}
+
+// validateSettingsConfig reports extra_settings keys that collide with typed attributes and
+// permission rules shadowed by rules with higher precedence.
+func (r *claudeSettingsResource) validateSettingsConfig(ctx context.Context, diags *diag.Diagnostics, config *claudeSettingsResourceModel) {
+	if !config.ExtraSettings.IsNull() && !config.ExtraSettings.IsUnknown() {
+		// Unknown values cannot be rendered yet; conflicts are then reported on apply.
+		var d diag.Diagnostics
+		if typed, err := r.modelToSettings(ctx, &d, config); err == nil {
+			validateExtraSettings(diags, config.ExtraSettings, typed)
+		}
 	}
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		resp.Diagnostics.AddError("Failed to create directory", err.Error())
+	perms := config.Permissions
+	if perms == nil {
 		return
 	}
 
-	// Create settings JSON
-	settingsData, err := r.modelToSettings(ctx, &resp.Diagnostics, &plan)
-	if err != nil || resp.Diagnostics.HasError() {
-		resp.Diagnostics.AddError("Failed to create settings data", err.Error())
+	checkPermissionRuleConflicts(
+		[]claudePermissionRuleList{
+			{Name: "deny", Rules: perms.Deny},
+			{Name: "ask", Rules: perms.Ask},
+			{Name: "allow", Rules: perms.Allow},
+		},
+		func(list string, index int, summary, detail string) {
+			diags.AddAttributeWarning(path.Root("permissions").AtName(list).AtListIndex(index), summary, detail)
+		},
+	)
+}
+
+func (r *claudeSettingsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan claudeSettingsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	jsonData, err := json.MarshalIndent(settingsData, "", "  ")
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to marshal JSON", err.Error())
+	filePath := r.getSettingsFilePath(plan.Scope.ValueString())
+	if filePath == "" {
+		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user', 'project', or 'local'")
 		return
 	}
+	defer fileio.Lock(filePath)()
 
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
-		resp.Diagnostics.AddError("Failed to write settings file", err.Error())
+	written := r.writeSettings(ctx, &resp.Diagnostics, &plan, nil, filePath)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(setWrittenSettingsKeys(ctx, resp.Private, written)...)
 
 	// Set computed attributes
 	plan.ID = types.StringValue(fmt.Sprintf("claude-settings-%s", plan.Scope.ValueString()))
@@ -271,20 +405,21 @@ func (r *claudeSettingsResource) Read(ctx context.Context, req resource.ReadRequ
 	}
 
 	// Read and parse settings file
-	data, err := os.ReadFile(filePath)
+	settingsData, err := readSettingsJSON(filePath)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read settings file", err.Error())
 		return
 	}
 
-	var settingsData map[string]interface{}
-	if err := json.Unmarshal(data, &settingsData); err != nil {
-		resp.Diagnostics.AddError("Failed to parse settings JSON", err.Error())
-		return
+	// A freshly imported resource, or one that replaces the whole file, owns every known key
+	// that it finds. Otherwise only the keys already in state are refreshed.
+	imported, d := req.Private.GetKey(ctx, claudeSettingsImportedKey)
+	resp.Diagnostics.Append(d...)
+	if len(imported) > 0 {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, nil)...)
 	}
-
-	// Update state with current file contents
-	r.settingsToModel(ctx, &resp.Diagnostics, &state, settingsData)
+	ownAll := len(imported) > 0 || settingsMergeStrategy(state.MergeStrategy) == "replace_all"
+	r.settingsToModel(ctx, &resp.Diagnostics, &state, settingsData, ownAll)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -294,9 +429,11 @@ func (r *claudeSettingsResource) Read(ctx context.Context, req resource.ReadRequ
 }
 
 func (r *claudeSettingsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan claudeSettingsResourceModel
+	var plan, state claudeSettingsResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -306,24 +443,17 @@ func (r *claudeSettingsResource) Update(ctx context.Context, req resource.Update
 		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user', 'project', or 'local'")
 		return
 	}
+	defer fileio.Lock(filePath)()
 
-	// Create settings JSON
-	settingsData, err := r.modelToSettings(ctx, &resp.Diagnostics, &plan)
-	if err != nil || resp.Diagnostics.HasError() {
-		resp.Diagnostics.AddError("Failed to create settings data", err.Error())
-		return
-	}
-
-	jsonData, err := json.MarshalIndent(settingsData, "", "  ")
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to marshal JSON", err.Error())
+	prior := r.writtenKeys(ctx, &resp.Diagnostics, req.Private, &state)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
-		resp.Diagnostics.AddError("Failed to write settings file", err.Error())
+	written := r.writeSettings(ctx, &resp.Diagnostics, &plan, prior, filePath)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(setWrittenSettingsKeys(ctx, resp.Private, written)...)
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -342,11 +472,13 @@ func (r *claudeSettingsResource) Delete(ctx context.Context, req resource.Delete
 		resp.Diagnostics.AddError("Invalid scope", "Scope must be 'user', 'project', or 'local'")
 		return
 	}
+	defer fileio.Lock(filePath)()
 
-	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
-		resp.Diagnostics.AddError("Failed to delete settings file", err.Error())
+	written := r.writtenKeys(ctx, &resp.Diagnostics, req.Private, &state)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	r.removeSettings(&resp.Diagnostics, &state, written, filePath)
 }
 
 func (r *claudeSettingsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -359,6 +491,37 @@ func (r *claudeSettingsResource) ImportState(ctx context.Context, req resource.I
 
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("scope"), scope)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("claude-settings-%s", scope))...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, claudeSettingsImportedKey, []byte("true"))...)
+}
+
+// UpgradeState upgrades state written before `hooks` became a list of blocks. The old
+// map(map(string)) value has no equivalent in the block form, so it is dropped and the next apply
+// writes the configured hooks again.
+func (r *claudeSettingsResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {StateUpgrader: upgradeClaudeSettingsStateV0},
+	}
+}
+
+func upgradeClaudeSettingsStateV0(_ context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	if req.RawState == nil || req.RawState.JSON == nil {
+		resp.Diagnostics.AddError("Unable to upgrade state", "The prior state is not in JSON format.")
+		return
+	}
+
+	var state map[string]json.RawMessage
+	if err := json.Unmarshal(req.RawState.JSON, &state); err != nil {
+		resp.Diagnostics.AddError("Unable to upgrade state", fmt.Sprintf("Could not parse the prior state: %s", err))
+		return
+	}
+	state["hooks"] = json.RawMessage("[]")
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to upgrade state", fmt.Sprintf("Could not encode the upgraded state: %s", err))
+		return
+	}
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: data}
 }
 
 func (r *claudeSettingsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -401,6 +564,175 @@ func (r *claudeSettingsResource) getSettingsFilePath(scope string) string {
 	}
 }
 
+// removeSettings deletes the settings file, or with preserve_unknown only the written keys.
+func (r *claudeSettingsResource) removeSettings(diags *diag.Diagnostics, state *claudeSettingsResourceModel, written [][]string, filePath string) {
+	// The file is deleted once nothing else is left in it.
+	if settingsMergeStrategy(state.MergeStrategy) == "preserve_unknown" {
+		existing, err := readSettingsJSON(filePath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return
+			}
+			diags.AddError("Failed to read settings file", err.Error())
+			return
+		}
+
+		for _, p := range written {
+			existing = configio.DeleteKeyPath(existing, p)
+		}
+
+		if len(existing) > 0 {
+			jsonData, err := json.MarshalIndent(existing, "", "  ")
+			if err != nil {
+				diags.AddError("Failed to marshal JSON", err.Error())
+				return
+			}
+			if err := state.writeFile(filePath, jsonData, 0644); err != nil {
+				diags.AddError("Failed to write settings file", err.Error())
+			}
+			return
+		}
+	}
+
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		diags.AddError("Failed to delete settings file", err.Error())
+	}
+}
+
+// writeSettings merges plan into the settings file and returns the key paths it wrote. prior
+// holds the key paths written before; it is nil on create.
+func (r *claudeSettingsResource) writeSettings(ctx context.Context, diags *diag.Diagnostics, plan *claudeSettingsResourceModel, prior [][]string, filePath string) [][]string {
+	settingsData, written, err := r.mergeIntoExisting(ctx, diags, plan, prior, filePath)
+	if err != nil {
+		diags.AddError("Failed to create settings data", err.Error())
+		return nil
+	}
+	if diags.HasError() {
+		return nil
+	}
+
+	jsonData, err := json.MarshalIndent(settingsData, "", "  ")
+	if err != nil {
+		diags.AddError("Failed to marshal JSON", err.Error())
+		return nil
+	}
+
+	if err := plan.writeFile(filePath, jsonData, 0644); err != nil {
+		diags.AddError("Failed to write settings file", err.Error())
+		return nil
+	}
+	return written
+}
+
+// mergeIntoExisting builds the settings to write according to merge_strategy, and returns them
+// with the key paths they set. prior holds the key paths written before, which are removed
+// when no longer configured; it is nil on create.
+func (r *claudeSettingsResource) mergeIntoExisting(ctx context.Context, diags *diag.Diagnostics, plan *claudeSettingsResourceModel, prior [][]string, filePath string) (map[string]interface{}, [][]string, error) {
+	desired, err := r.renderSettings(ctx, diags, plan)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	strategy := settingsMergeStrategy(plan.MergeStrategy)
+	if strategy == "replace_all" {
+		return desired, configio.KeyPaths(desired), nil
+	}
+
+	existing, err := readSettingsJSON(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read existing settings file: %w", err)
+	}
+
+	if strategy == "fail_on_unknown" {
+		var unknown []string
+		for k := range existing {
+			_, known := claudeSettingsKnownKeys[k]
+			_, managed := desired[k]
+			if !known && !managed {
+				unknown = append(unknown, k)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return nil, nil, fmt.Errorf("fail_on_unknown: file contains unknown keys: %s", strings.Join(unknown, ", "))
+		}
+	}
+
+	merged, written := configio.MergeKeyPaths(existing, desired, prior)
+	return merged, written, nil
+}
+
+// writtenKeys returns the key paths last written for state. State saved before they were
+// recorded, or by an import, falls back to the keys rendered from state.
+func (r *claudeSettingsResource) writtenKeys(ctx context.Context, diags *diag.Diagnostics, private privateStateReader, state *claudeSettingsResourceModel) [][]string {
+	raw, d := private.GetKey(ctx, claudeSettingsWrittenKey)
+	diags.Append(d...)
+	if len(raw) > 0 {
+		var written [][]string
+		if err := json.Unmarshal(raw, &written); err == nil {
+			return written
+		}
+	}
+
+	rendered, err := r.renderSettings(ctx, diags, state)
+	if err != nil {
+		diags.AddError("Failed to create settings data", err.Error())
+		return nil
+	}
+	return configio.KeyPaths(rendered)
+}
+
+// setWrittenSettingsKeys records the key paths written to the settings file in private state.
+func setWrittenSettingsKeys(ctx context.Context, private privateStateWriter, written [][]string) diag.Diagnostics {
+	if written == nil {
+		written = [][]string{}
+	}
+	raw, err := json.Marshal(written)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to record written settings", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, claudeSettingsWrittenKey, raw)
+}
+
+// settingsMergeStrategy returns the configured merge strategy, defaulting to preserve_unknown.
+func settingsMergeStrategy(v types.String) string {
+	if v.IsNull() || v.IsUnknown() {
+		return "preserve_unknown"
+	}
+	return v.ValueString()
+}
+
+// readSettingsJSON reads a JSON settings file into a generic map. An empty file yields an empty map.
+func readSettingsJSON(filePath string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[string]interface{})
+	if len(bytes.TrimSpace(data)) == 0 {
+		return settings, nil
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings JSON: %w", err)
+	}
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+	return settings, nil
+}
+
+// renderSettings returns the settings managed by model: the typed attributes with
+// extra_settings merged in.
+func (r *claudeSettingsResource) renderSettings(ctx context.Context, diags *diag.Diagnostics, model *claudeSettingsResourceModel) (map[string]interface{}, error) {
+	settings, err := r.modelToSettings(ctx, diags, model)
+	if err != nil {
+		return nil, err
+	}
+	return mergeExtraSettings(settings, model.ExtraSettings)
+}
+
 func (r *claudeSettingsResource) modelToSettings(ctx context.Context, diags *diag.Diagnostics, model *claudeSettingsResourceModel) (map[string]interface{}, error) {
 	settings := make(map[string]interface{})
 
@@ -467,17 +799,15 @@ func (r *claudeSettingsResource) modelToSettings(ctx context.Context, diags *dia
 			diags.Append(d...)
 			return nil, fmt.Errorf("failed to convert env map")
 		}
-		settings["env"] = envMap
+		env := make(map[string]interface{}, len(envMap))
+		for k, v := range envMap {
+			env[k] = v
+		}
+		settings["env"] = env
 	}
 
-	if !model.Hooks.IsNull() {
-		var hooksMap map[string]map[string]string
-		d := model.Hooks.ElementsAs(ctx, &hooksMap, false)
-		if d.HasError() {
-			diags.Append(d...)
-			return nil, fmt.Errorf("failed to convert hooks map")
-		}
-		settings["hooks"] = hooksMap
+	if len(model.Hooks) > 0 {
+		settings["hooks"] = hooksToSettings(model.Hooks)
 	}
 
 	// Handle permissions
@@ -553,21 +883,263 @@ func (r *claudeSettingsResource) modelToSettings(ctx context.Context, diags *dia
 	return settings, nil
 }
 
-func (r *claudeSettingsResource) settingsToModel(ctx context.Context, diags *diag.Diagnostics, model *claudeSettingsResourceModel, settings map[string]interface{}) {
-	// Implementation would populate model from settings map
-	// This is a simplified version - full implementation would handle all fields
+// settingsToModel refreshes model from settings. Unless ownAll is set, only the keys already in
+// model are refreshed, so that keys written by other resources or by hand are not adopted.
+func (r *claudeSettingsResource) settingsToModel(ctx context.Context, diags *diag.Diagnostics, model *claudeSettingsResourceModel, settings map[string]interface{}, ownAll bool) {
+	var d diag.Diagnostics
+
+	// Keys owned by extra_settings are refreshed there and hidden from the typed attributes
+	if extra, err := parseExtraSettings(model.ExtraSettings); err == nil && extra != nil {
+		model.ExtraSettings = refreshExtraSettings(model.ExtraSettings, settings)
+		settings = stripExtraSettings(settings, extra)
+	}
+
+	if !ownAll {
+		typed, err := r.modelToSettings(ctx, diags, model)
+		if err != nil {
+			diags.AddError("Failed to create settings data", err.Error())
+			return
+		}
+		settings = projectJSON(typed, settings)
+	}
+
+	model.APIKeyHelper = jsonStringValue(settings, "apiKeyHelper")
+	model.CleanupPeriodDays = jsonInt64Value(settings, "cleanupPeriodDays")
+	model.IncludeCoAuthoredBy = jsonBoolValue(settings, "includeCoAuthoredBy")
+	model.Model = jsonStringValue(settings, "model")
+	model.OutputStyle = jsonStringValue(settings, "outputStyle")
+	model.ForceLoginMethod = jsonStringValue(settings, "forceLoginMethod")
+	model.ForceLoginOrgUUID = jsonStringValue(settings, "forceLoginOrgUUID")
+	model.DisableAllHooks = jsonBoolValue(settings, "disableAllHooks")
+	model.AwsAuthRefresh = jsonStringValue(settings, "awsAuthRefresh")
+	model.AwsCredentialExport = jsonStringValue(settings, "awsCredentialExport")
+	model.EnableAllProjectMcpServers = jsonBoolValue(settings, "enableAllProjectMcpServers")
+
+	model.EnabledMcpjsonServers, d = jsonStringListValue(ctx, settings, "enabledMcpjsonServers")
+	diags.Append(d...)
+	model.DisabledMcpjsonServers, d = jsonStringListValue(ctx, settings, "disabledMcpjsonServers")
+	diags.Append(d...)
+	model.Env, d = jsonStringMapValue(ctx, settings, "env")
+	diags.Append(d...)
+
+	if raw, ok := settings["hooks"]; ok {
+		hooks, ok := hooksFromSettings(raw, model.Hooks)
+		if ok {
+			model.Hooks = hooks
+		} else {
+			diags.AddWarning(
+				"Unsupported hooks format",
+				"The `hooks` key in the settings file cannot be represented by the `hooks` block and was not refreshed.",
+			)
+		}
+	} else {
+		model.Hooks = nil
+	}
 
-	if val, ok := settings["apiKeyHelper"].(string); ok {
-		model.APIKeyHelper = types.StringValue(val)
+	if raw, ok := settings["permissions"].(map[string]interface{}); ok {
+		perms := &claudeSettingsPermissionsModel{
+			DefaultMode:                  jsonStringValue(raw, "defaultMode"),
+			DisableBypassPermissionsMode: jsonStringValue(raw, "disableBypassPermissionsMode"),
+		}
+		perms.Allow, d = jsonStringListValue(ctx, raw, "allow")
+		diags.Append(d...)
+		perms.Ask, d = jsonStringListValue(ctx, raw, "ask")
+		diags.Append(d...)
+		perms.Deny, d = jsonStringListValue(ctx, raw, "deny")
+		diags.Append(d...)
+		perms.AdditionalDirectories, d = jsonStringListValue(ctx, raw, "additionalDirectories")
+		diags.Append(d...)
+		model.Permissions = perms
 	} else {
-		model.APIKeyHelper = types.StringNull()
+		model.Permissions = nil
 	}
 
-	if val, ok := settings["model"].(string); ok {
-		model.Model = types.StringValue(val)
+	if raw, ok := settings["statusLine"].(map[string]interface{}); ok {
+		model.StatusLine = &claudeSettingsStatusLineModel{
+			Type:    jsonStringValue(raw, "type"),
+			Command: jsonStringValue(raw, "command"),
+		}
 	} else {
-		model.Model = types.StringNull()
+		model.StatusLine = nil
+	}
+}
+
+// jsonStringValue returns the string stored under key, or null if it is absent or not a string.
+func jsonStringValue(m map[string]interface{}, key string) types.String {
+	if v, ok := m[key].(string); ok {
+		return types.StringValue(v)
+	}
+	return types.StringNull()
+}
+
+// jsonBoolValue returns the bool stored under key, or null if it is absent or not a bool.
+func jsonBoolValue(m map[string]interface{}, key string) types.Bool {
+	if v, ok := m[key].(bool); ok {
+		return types.BoolValue(v)
+	}
+	return types.BoolNull()
+}
+
+// jsonInt64Value returns the number stored under key as an int64, or null if it is absent or not a number.
+func jsonInt64Value(m map[string]interface{}, key string) types.Int64 {
+	switch v := m[key].(type) {
+	case float64:
+		return types.Int64Value(int64(v))
+	case int64:
+		return types.Int64Value(v)
+	case int:
+		return types.Int64Value(int64(v))
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return types.Int64Value(n)
+		}
+	}
+	return types.Int64Null()
+}
+
+// jsonStringListValue converts a JSON array of strings stored under key into a list value.
+func jsonStringListValue(ctx context.Context, m map[string]interface{}, key string) (types.List, diag.Diagnostics) {
+	raw, ok := m[key].([]interface{})
+	if !ok {
+		return types.ListNull(types.StringType), nil
+	}
+	items := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			items = append(items, s)
+		}
+	}
+	return types.ListValueFrom(ctx, types.StringType, items)
+}
+
+// jsonStringMapValue converts a JSON object of strings stored under key into a map value.
+func jsonStringMapValue(ctx context.Context, m map[string]interface{}, key string) (types.Map, diag.Diagnostics) {
+	raw, ok := m[key].(map[string]interface{})
+	if !ok {
+		return types.MapNull(types.StringType), nil
+	}
+	items := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			items[k] = s
+		}
 	}
+	return types.MapValueFrom(ctx, types.StringType, items)
+}
 
-	// Add implementation for remaining fields...
+// hooksToSettings converts hook blocks into the settings.json shape: event -> list of
+// {matcher, hooks: [{type, command, timeout}]}. Blocks for the same event keep their order.
+func hooksToSettings(hooks []claudeSettingsHookModel) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, h := range hooks {
+		commands := make([]interface{}, 0, len(h.Hook))
+		for _, c := range h.Hook {
+			cmd := map[string]interface{}{
+				"type":    "command",
+				"command": c.Command.ValueString(),
+			}
+			if !c.Type.IsNull() && !c.Type.IsUnknown() {
+				cmd["type"] = c.Type.ValueString()
+			}
+			if !c.Timeout.IsNull() {
+				cmd["timeout"] = c.Timeout.ValueInt64()
+			}
+			commands = append(commands, cmd)
+		}
+
+		entry := map[string]interface{}{"hooks": commands}
+		if !h.Matcher.IsNull() {
+			entry["matcher"] = h.Matcher.ValueString()
+		}
+
+		event := h.Event.ValueString()
+		entries, _ := out[event].([]interface{})
+		out[event] = append(entries, entry)
+	}
+	return out
+}
+
+// hooksFromSettings converts the settings.json hooks object back into hook blocks. Entries are
+// matched to the blocks in prior, in order, so that blocks interleaving events keep their
+// positions; remaining entries follow with events in documented order and then alphabetically.
+// It returns false if the value has an unexpected shape.
+func hooksFromSettings(raw interface{}, prior []claudeSettingsHookModel) ([]claudeSettingsHookModel, bool) {
+	events, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	rank := make(map[string]int)
+	for i, event := range claudeHookEvents {
+		rank[event] = i
+	}
+	names := make([]string, 0, len(events))
+	for event := range events {
+		names = append(names, event)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		ri, iok := rank[names[i]]
+		rj, jok := rank[names[j]]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		default:
+			return names[i] < names[j]
+		}
+	})
+
+	parsed := make(map[string][]claudeSettingsHookModel, len(events))
+	for _, event := range names {
+		entries, ok := events[event].([]interface{})
+		if !ok {
+			return nil, false
+		}
+		for _, e := range entries {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			commands, ok := entry["hooks"].([]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			h := claudeSettingsHookModel{
+				Event:   types.StringValue(event),
+				Matcher: jsonStringValue(entry, "matcher"),
+			}
+			for _, c := range commands {
+				cmd, ok := c.(map[string]interface{})
+				if !ok {
+					return nil, false
+				}
+				// Claude Code treats a missing type as a command hook, as does the schema default
+				hookType := jsonStringValue(cmd, "type")
+				if hookType.IsNull() {
+					hookType = types.StringValue("command")
+				}
+				h.Hook = append(h.Hook, claudeSettingsHookCommandModel{
+					Type:    hookType,
+					Command: jsonStringValue(cmd, "command"),
+					Timeout: jsonInt64Value(cmd, "timeout"),
+				})
+			}
+			parsed[event] = append(parsed[event], h)
+		}
+	}
+
+	var hooks []claudeSettingsHookModel
+	for _, p := range prior {
+		event := p.Event.ValueString()
+		if entries := parsed[event]; len(entries) > 0 {
+			hooks = append(hooks, entries[0])
+			parsed[event] = entries[1:]
+		}
+	}
+	for _, event := range names {
+		hooks = append(hooks, parsed[event]...)
+	}
+	return hooks, true
 }
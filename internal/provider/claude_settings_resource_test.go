@@ -6,12 +6,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestClaudeSettingsResource_getSettingsFilePath(t *testing.T) {
@@ -182,6 +189,463 @@ func TestClaudeSettingsResource_modelToSettings(t *testing.T) {
 	}
 }
 
+func TestClaudeSettingsResource_settingsToModel(t *testing.T) {
+	r := &claudeSettingsResource{}
+	ctx := context.Background()
+
+	fileContent := `{
+  "apiKeyHelper": "/bin/generate_temp_api_key.sh",
+  "cleanupPeriodDays": 20,
+  "includeCoAuthoredBy": false,
+  "model": "claude-sonnet-4",
+  "outputStyle": "Explanatory",
+  "forceLoginMethod": "console",
+  "forceLoginOrgUUID": "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx",
+  "disableAllHooks": true,
+  "awsAuthRefresh": "aws sso login --profile myprofile",
+  "awsCredentialExport": "/bin/generate_aws_grant.sh",
+  "enableAllProjectMcpServers": true,
+  "enabledMcpjsonServers": ["memory", "github"],
+  "disabledMcpjsonServers": ["filesystem"],
+  "env": {"FOO": "bar"},
+  "permissions": {
+    "allow": ["Bash(npm run lint)"],
+    "deny": ["Read(./.env)"],
+    "additionalDirectories": ["../docs/"],
+    "defaultMode": "acceptEdits"
+  },
+  "statusLine": {"type": "command", "command": "~/.claude/statusline.sh"}
+}`
+
+	var settings map[string]interface{}
+	if err := json.Unmarshal([]byte(fileContent), &settings); err != nil {
+		t.Fatalf("Failed to unmarshal settings: %v", err)
+	}
+
+	var model claudeSettingsResourceModel
+	var diags diag.Diagnostics
+	r.settingsToModel(ctx, &diags, &model, settings, true)
+	if diags.HasError() {
+		t.Fatalf("Diagnostics has errors: %v", diags.Errors())
+	}
+
+	if model.APIKeyHelper.ValueString() != "/bin/generate_temp_api_key.sh" {
+		t.Errorf("Expected api_key_helper to be read, got %v", model.APIKeyHelper)
+	}
+	if model.CleanupPeriodDays.ValueInt64() != 20 {
+		t.Errorf("Expected cleanup_period_days 20, got %v", model.CleanupPeriodDays)
+	}
+	if model.IncludeCoAuthoredBy.IsNull() || model.IncludeCoAuthoredBy.ValueBool() {
+		t.Errorf("Expected include_co_authored_by false, got %v", model.IncludeCoAuthoredBy)
+	}
+	if model.ForceLoginMethod.ValueString() != "console" {
+		t.Errorf("Expected force_login_method 'console', got %v", model.ForceLoginMethod)
+	}
+	if !model.DisableAllHooks.ValueBool() || !model.EnableAllProjectMcpServers.ValueBool() {
+		t.Errorf("Expected boolean flags to be read, got %v and %v", model.DisableAllHooks, model.EnableAllProjectMcpServers)
+	}
+	if model.AwsAuthRefresh.ValueString() != "aws sso login --profile myprofile" || model.AwsCredentialExport.ValueString() != "/bin/generate_aws_grant.sh" {
+		t.Errorf("Expected AWS helpers to be read, got %v and %v", model.AwsAuthRefresh, model.AwsCredentialExport)
+	}
+	if len(model.EnabledMcpjsonServers.Elements()) != 2 || len(model.DisabledMcpjsonServers.Elements()) != 1 {
+		t.Errorf("Expected MCP server lists to be read, got %v and %v", model.EnabledMcpjsonServers, model.DisabledMcpjsonServers)
+	}
+	if len(model.Env.Elements()) != 1 {
+		t.Errorf("Expected env to have 1 entry, got %v", model.Env)
+	}
+	if model.Hooks != nil {
+		t.Errorf("Expected hooks to be empty, got %v", model.Hooks)
+	}
+
+	if model.Permissions == nil {
+		t.Fatal("Expected permissions block to be read")
+	}
+	if model.Permissions.DefaultMode.ValueString() != "acceptEdits" {
+		t.Errorf("Expected default_mode 'acceptEdits', got %v", model.Permissions.DefaultMode)
+	}
+	if len(model.Permissions.Allow.Elements()) != 1 || len(model.Permissions.Deny.Elements()) != 1 {
+		t.Errorf("Expected allow and deny to have 1 entry each, got %v and %v", model.Permissions.Allow, model.Permissions.Deny)
+	}
+	if !model.Permissions.Ask.IsNull() {
+		t.Errorf("Expected ask to be null, got %v", model.Permissions.Ask)
+	}
+	if !model.Permissions.DisableBypassPermissionsMode.IsNull() {
+		t.Errorf("Expected disable_bypass_permissions_mode to be null, got %v", model.Permissions.DisableBypassPermissionsMode)
+	}
+
+	if model.StatusLine == nil || model.StatusLine.Command.ValueString() != "~/.claude/statusline.sh" {
+		t.Errorf("Expected status_line to be read, got %v", model.StatusLine)
+	}
+
+	// Reading an empty file clears everything that was previously set
+	r.settingsToModel(ctx, &diags, &model, map[string]interface{}{}, true)
+	if !model.Model.IsNull() || !model.Env.IsNull() || model.Permissions != nil || model.StatusLine != nil {
+		t.Errorf("Expected all attributes to be cleared, got %+v", model)
+	}
+
+	// Without ownAll only the keys already in the model are refreshed
+	env, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"FOO": "old"})
+	model = claudeSettingsResourceModel{Model: types.StringValue("opus"), Env: env}
+	r.settingsToModel(ctx, &diags, &model, settings, false)
+	if diags.HasError() {
+		t.Fatalf("Diagnostics has errors: %v", diags.Errors())
+	}
+	if model.Model.ValueString() != "claude-sonnet-4" {
+		t.Errorf("Expected model to be refreshed, got %v", model.Model)
+	}
+	if !model.Env.Equal(types.MapValueMust(types.StringType, map[string]attr.Value{"FOO": types.StringValue("bar")})) {
+		t.Errorf("Expected env to be refreshed, got %v", model.Env)
+	}
+	if model.Permissions != nil || model.StatusLine != nil || !model.APIKeyHelper.IsNull() {
+		t.Errorf("Expected unmanaged keys not to be adopted, got %+v", model)
+	}
+}
+
+func TestClaudeSettingsResource_RoundTrip(t *testing.T) {
+	r := &claudeSettingsResource{}
+	ctx := context.Background()
+
+	allow, _ := types.ListValueFrom(ctx, types.StringType, []string{"Bash(npm run test:*)"})
+	env, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"NODE_ENV": "test"})
+	hooks := []claudeSettingsHookModel{
+		{
+			Event:   types.StringValue("PreToolUse"),
+			Matcher: types.StringValue("Bash"),
+			Hook: []claudeSettingsHookCommandModel{
+				{Type: types.StringValue("command"), Command: types.StringValue("./check.sh"), Timeout: types.Int64Null()},
+			},
+		},
+	}
+
+	planned := claudeSettingsResourceModel{
+		Model:             types.StringValue("opus"),
+		CleanupPeriodDays: types.Int64Value(7),
+		Env:               env,
+		Hooks:             hooks,
+		Permissions: &claudeSettingsPermissionsModel{
+			Allow:       allow,
+			DefaultMode: types.StringValue("plan"),
+		},
+	}
+
+	var diags diag.Diagnostics
+	settings, err := r.modelToSettings(ctx, &diags, &planned)
+	if err != nil {
+		t.Fatalf("Failed to convert model to settings: %v", err)
+	}
+	jsonData, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatalf("Failed to marshal settings: %v", err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(jsonData, &parsed); err != nil {
+		t.Fatalf("Failed to unmarshal settings: %v", err)
+	}
+
+	var read claudeSettingsResourceModel
+	r.settingsToModel(ctx, &diags, &read, parsed, true)
+	if diags.HasError() {
+		t.Fatalf("Diagnostics has errors: %v", diags.Errors())
+	}
+
+	if !read.Model.Equal(planned.Model) || !read.CleanupPeriodDays.Equal(planned.CleanupPeriodDays) {
+		t.Errorf("Scalar attributes did not round-trip: %v, %v", read.Model, read.CleanupPeriodDays)
+	}
+	if !read.Env.Equal(planned.Env) {
+		t.Errorf("Expected env %v, got %v", planned.Env, read.Env)
+	}
+	if !reflect.DeepEqual(read.Hooks, planned.Hooks) {
+		t.Errorf("Expected hooks %v, got %v", planned.Hooks, read.Hooks)
+	}
+	if read.Permissions == nil || !read.Permissions.Allow.Equal(allow) || !read.Permissions.DefaultMode.Equal(planned.Permissions.DefaultMode) {
+		t.Errorf("Permissions did not round-trip: %+v", read.Permissions)
+	}
+	if !read.Permissions.Deny.IsNull() {
+		t.Errorf("Expected deny to stay null, got %v", read.Permissions.Deny)
+	}
+}
+
+func TestClaudeSettingsResource_mergeIntoExisting(t *testing.T) {
+	r := &claudeSettingsResource{}
+	ctx := context.Background()
+
+	existingContent := `{
+  "model": "claude-3-opus",
+  "outputStyle": "verbose",
+  "enabledPlugins": {"formatter@tools": true},
+  "permissions": {"allow": ["Bash(git diff:*)"]}
+}`
+
+	testCases := []struct {
+		name        string
+		plan        claudeSettingsResourceModel
+		prior       [][]string
+		expectError bool
+		validate    func(*testing.T, map[string]interface{})
+	}{
+		{
+			name: "preserve_unknown_default",
+			plan: claudeSettingsResourceModel{
+				Model: types.StringValue("claude-sonnet-4"),
+			},
+			validate: func(t *testing.T, settings map[string]interface{}) {
+				if settings["model"] != "claude-sonnet-4" {
+					t.Errorf("Expected model to be overwritten, got %v", settings["model"])
+				}
+				if _, ok := settings["enabledPlugins"]; !ok {
+					t.Error("Expected unmanaged key enabledPlugins to be preserved")
+				}
+				if _, ok := settings["permissions"]; !ok {
+					t.Error("Expected unconfigured permissions to be preserved")
+				}
+			},
+		},
+		{
+			name: "preserve_unknown_drops_previously_managed_keys",
+			plan: claudeSettingsResourceModel{
+				MergeStrategy: types.StringValue("preserve_unknown"),
+				Model:         types.StringValue("claude-sonnet-4"),
+			},
+			prior: [][]string{{"model"}, {"outputStyle"}},
+			validate: func(t *testing.T, settings map[string]interface{}) {
+				if _, ok := settings["outputStyle"]; ok {
+					t.Error("Expected outputStyle to be removed once no longer managed")
+				}
+				if _, ok := settings["enabledPlugins"]; !ok {
+					t.Error("Expected unmanaged key enabledPlugins to be preserved")
+				}
+			},
+		},
+		{
+			name: "preserve_unknown_merges_nested_keys",
+			plan: claudeSettingsResourceModel{
+				Permissions: &claudeSettingsPermissionsModel{
+					Allow:                 types.ListNull(types.StringType),
+					Ask:                   types.ListNull(types.StringType),
+					Deny:                  types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Read(./.env)")}),
+					AdditionalDirectories: types.ListNull(types.StringType),
+				},
+			},
+			validate: func(t *testing.T, settings map[string]interface{}) {
+				perms, _ := settings["permissions"].(map[string]interface{})
+				if perms["allow"] == nil || perms["deny"] == nil {
+					t.Errorf("Expected allow rules written elsewhere to be kept next to deny, got %v", perms)
+				}
+			},
+		},
+		{
+			name: "replace_all",
+			plan: claudeSettingsResourceModel{
+				MergeStrategy: types.StringValue("replace_all"),
+				Model:         types.StringValue("claude-sonnet-4"),
+			},
+			validate: func(t *testing.T, settings map[string]interface{}) {
+				if len(settings) != 1 || settings["model"] != "claude-sonnet-4" {
+					t.Errorf("Expected only the managed model key, got %v", settings)
+				}
+			},
+		},
+		{
+			name: "fail_on_unknown",
+			plan: claudeSettingsResourceModel{
+				MergeStrategy: types.StringValue("fail_on_unknown"),
+				Model:         types.StringValue("claude-sonnet-4"),
+			},
+			expectError: true,
+		},
+		{
+			name: "fail_on_unknown_allows_extra_settings_keys",
+			plan: claudeSettingsResourceModel{
+				MergeStrategy: types.StringValue("fail_on_unknown"),
+				Model:         types.StringValue("claude-sonnet-4"),
+				ExtraSettings: types.StringValue(`{"enabledPlugins": {"formatter@tools": false}}`),
+			},
+			validate: func(t *testing.T, settings map[string]interface{}) {
+				plugins, _ := settings["enabledPlugins"].(map[string]interface{})
+				if plugins["formatter@tools"] != false {
+					t.Errorf("Expected enabledPlugins from extra_settings, got %v", settings["enabledPlugins"])
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			settingsPath := filepath.Join(t.TempDir(), "settings.json")
+			if err := os.WriteFile(settingsPath, []byte(existingContent), 0644); err != nil {
+				t.Fatalf("Failed to write settings file: %v", err)
+			}
+
+			var diags diag.Diagnostics
+			result, _, err := r.mergeIntoExisting(ctx, &diags, &tc.plan, tc.prior, settingsPath)
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tc.validate(t, result)
+		})
+	}
+
+	// A missing file is treated as empty
+	var diags diag.Diagnostics
+	plan := claudeSettingsResourceModel{MergeStrategy: types.StringValue("fail_on_unknown"), Model: types.StringValue("opus")}
+	result, written, err := r.mergeIntoExisting(ctx, &diags, &plan, nil, filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error for missing file: %v", err)
+	}
+	if result["model"] != "opus" {
+		t.Errorf("Expected model 'opus', got %v", result["model"])
+	}
+	if !reflect.DeepEqual(written, [][]string{{"model"}}) {
+		t.Errorf("Expected the written key paths, got %v", written)
+	}
+}
+
+func TestClaudeSettingsResource_hooks(t *testing.T) {
+	fileContent := `{
+  "Stop": [
+    {"hooks": [{"type": "command", "command": "notify-send done"}]}
+  ],
+  "PreToolUse": [
+    {"matcher": "Bash", "hooks": [{"type": "command", "command": "./validate.sh", "timeout": 30}]},
+    {"matcher": "Edit|Write", "hooks": [
+      {"type": "command", "command": "./lint.sh"},
+      {"type": "command", "command": "./format.sh"}
+    ]}
+  ]
+}`
+
+	var raw interface{}
+	if err := json.Unmarshal([]byte(fileContent), &raw); err != nil {
+		t.Fatalf("Failed to unmarshal hooks: %v", err)
+	}
+
+	// Without prior state, events follow the documented order
+	hooks, ok := hooksFromSettings(raw, nil)
+	if !ok {
+		t.Fatal("Expected hooks to be representable")
+	}
+	if len(hooks) != 3 {
+		t.Fatalf("Expected 3 hook entries, got %d", len(hooks))
+	}
+	if hooks[0].Event.ValueString() != "PreToolUse" || hooks[0].Matcher.ValueString() != "Bash" {
+		t.Errorf("Expected first entry PreToolUse/Bash, got %v/%v", hooks[0].Event, hooks[0].Matcher)
+	}
+	if hooks[0].Hook[0].Timeout.ValueInt64() != 30 {
+		t.Errorf("Expected timeout 30, got %v", hooks[0].Hook[0].Timeout)
+	}
+	if len(hooks[1].Hook) != 2 || hooks[1].Hook[1].Command.ValueString() != "./format.sh" {
+		t.Errorf("Expected ordered hook commands, got %v", hooks[1].Hook)
+	}
+	if hooks[2].Event.ValueString() != "Stop" || !hooks[2].Matcher.IsNull() {
+		t.Errorf("Expected last entry Stop without matcher, got %v/%v", hooks[2].Event, hooks[2].Matcher)
+	}
+
+	// Prior state ordering is kept to avoid spurious diffs
+	prior := []claudeSettingsHookModel{{Event: types.StringValue("Stop")}}
+	hooks, _ = hooksFromSettings(raw, prior)
+	if hooks[0].Event.ValueString() != "Stop" {
+		t.Errorf("Expected Stop first when ordered by prior state, got %v", hooks[0].Event)
+	}
+
+	// Blocks interleaving events keep their positions from prior state
+	interleaved := []claudeSettingsHookModel{
+		{Event: types.StringValue("PreToolUse")},
+		{Event: types.StringValue("Stop")},
+		{Event: types.StringValue("PreToolUse")},
+	}
+	reordered, _ := hooksFromSettings(raw, interleaved)
+	var order []string
+	for _, h := range reordered {
+		order = append(order, h.Event.ValueString()+"/"+h.Matcher.ValueString())
+	}
+	if !reflect.DeepEqual(order, []string{"PreToolUse/Bash", "Stop/", "PreToolUse/Edit|Write"}) {
+		t.Errorf("Expected the interleaved order from prior state, got %v", order)
+	}
+
+	// Writing the blocks back yields the original structure
+	settings := hooksToSettings(hooks)
+	jsonData, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatalf("Failed to marshal hooks: %v", err)
+	}
+	var roundTripped interface{}
+	if err := json.Unmarshal(jsonData, &roundTripped); err != nil {
+		t.Fatalf("Failed to unmarshal hooks: %v", err)
+	}
+	if !reflect.DeepEqual(roundTripped, raw) {
+		t.Errorf("Expected hooks to round-trip, got %s", jsonData)
+	}
+
+	// A command without a type reads back as the schema default
+	untyped := map[string]interface{}{"Stop": []interface{}{
+		map[string]interface{}{"hooks": []interface{}{map[string]interface{}{"command": "./done.sh"}}},
+	}}
+	hooks, _ = hooksFromSettings(untyped, nil)
+	if hooks[0].Hook[0].Type.ValueString() != "command" {
+		t.Errorf("Expected a missing type to read as command, got %v", hooks[0].Hook[0].Type)
+	}
+
+	// The legacy map-of-maps shape cannot be represented
+	legacy := map[string]interface{}{"PreToolUse": map[string]interface{}{"command": "./check.sh"}}
+	if _, ok := hooksFromSettings(legacy, nil); ok {
+		t.Error("Expected legacy hooks shape to be rejected")
+	}
+}
+
+func TestClaudeSettingsResource_UpgradeState(t *testing.T) {
+	ctx := context.Background()
+	r := &claudeSettingsResource{}
+
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	// State written by version 0, where hooks was a map of maps
+	rawState := `{
+  "id": "claude-settings-project",
+  "scope": "project",
+  "model": "opus",
+  "env": {"DEBUG": "1"},
+  "hooks": {"PreToolUse": {"command": "./check.sh"}}
+}`
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("Expected an upgrader from version 0")
+	}
+	req := fwresource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(rawState)}}
+	resp := &fwresource.UpgradeStateResponse{}
+	upgrader.StateUpgrader(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+
+	value, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("Expected the upgraded state to match the schema: %v", err)
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: value}
+	var model claudeSettingsResourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("Failed to read upgraded state: %v", diags)
+	}
+	if model.Model.ValueString() != "opus" || model.Scope.ValueString() != "project" {
+		t.Errorf("Expected attributes to be kept, got model %v, scope %v", model.Model, model.Scope)
+	}
+	if len(model.Hooks) != 0 {
+		t.Errorf("Expected the legacy hooks to be dropped, got %v", model.Hooks)
+	}
+	if !model.MergeStrategy.IsNull() {
+		t.Errorf("Expected attributes missing from the prior state to be null, got %v", model.MergeStrategy)
+	}
+}
+
 func TestClaudeSettingsResource_JSONOperations(t *testing.T) {
 	tempDir := t.TempDir()
 	settingsPath := filepath.Join(tempDir, "settings.json")
@@ -403,9 +867,17 @@ func TestClaudeSettingsResource_ComplexSettings(t *testing.T) {
 					"command": "pwd && git branch --show-current",
 				},
 				"hooks": map[string]interface{}{
-					"pre-commit": map[string]interface{}{
-						"command": "./scripts/pre-commit.sh",
-						"timeout": "30",
+					"PreToolUse": []interface{}{
+						map[string]interface{}{
+							"matcher": "Bash",
+							"hooks": []interface{}{
+								map[string]interface{}{
+									"type":    "command",
+									"command": "./scripts/pre-commit.sh",
+									"timeout": float64(30),
+								},
+							},
+						},
 					},
 				},
 			},
@@ -521,6 +993,117 @@ func TestAccClaudeSettingsResource_basic(t *testing.T) {
 	})
 }
 
+func TestAccClaudeSettingsResource_extraSettings(t *testing.T) {
+	workDir := testAccSettingsWorkDir()
+	defer os.RemoveAll(workDir)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Keys set by typed attributes cannot also be set in extra_settings
+			{
+				Config:      testAccClaudeSettingsResourceExtraConfig(workDir, `{ model = "opus" }`),
+				ExpectError: regexp.MustCompile(`Conflicting extra_settings`),
+			},
+			// Create and Read testing
+			{
+				Config: testAccClaudeSettingsResourceExtraConfig(workDir, `{ spinnerTipsEnabled = false, permissions = { additionalDirectories = ["../docs"] } }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_settings.test", "model", "claude-3-5-sonnet"),
+					resource.TestCheckResourceAttr("agentsmith_claude_settings.test", "permissions.allow.#", "1"),
+					resource.TestCheckNoResourceAttr("agentsmith_claude_settings.test", "permissions.additional_directories"),
+					func(_ *terraform.State) error {
+						settings, err := readSettingsJSON(filepath.Join(workDir, ".claude", "settings.json"))
+						if err != nil {
+							return err
+						}
+						perms, _ := settings["permissions"].(map[string]interface{})
+						if settings["spinnerTipsEnabled"] != false || perms["additionalDirectories"] == nil || perms["allow"] == nil {
+							return fmt.Errorf("expected extra_settings to be deep-merged, got %v", settings)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccClaudeSettingsResource_keepsUnmanagedKeys(t *testing.T) {
+	workDir := testAccSettingsWorkDir()
+	defer os.RemoveAll(workDir)
+
+	settingsPath := filepath.Join(workDir, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatalf("Failed to create .claude: %v", err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"model":"opus","permissions":{"allow":["Bash(make test:*)"]}}`), 0644); err != nil {
+		t.Fatalf("Failed to write settings file: %v", err)
+	}
+
+	permissionsKept := func(_ *terraform.State) error {
+		settings, err := readSettingsJSON(settingsPath)
+		if err != nil {
+			return err
+		}
+		perms, _ := settings["permissions"].(map[string]interface{})
+		if allow, _ := perms["allow"].([]interface{}); len(allow) != 1 || allow[0] != "Bash(make test:*)" {
+			return fmt.Errorf("expected the hand-written permissions to be kept, got %v", settings)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			settings, err := readSettingsJSON(settingsPath)
+			if err != nil {
+				return err
+			}
+			if _, ok := settings["model"]; ok {
+				return fmt.Errorf("expected model to be removed, got %v", settings)
+			}
+			return permissionsKept(s)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccClaudeSettingsResourceConfig(workDir, "project", "claude-3-5-sonnet", "concise"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("agentsmith_claude_settings.test", "permissions.allow.#"),
+					permissionsKept,
+				),
+			},
+			// The refresh before this update must not adopt the permissions
+			{
+				Config: testAccClaudeSettingsResourceConfig(workDir, "project", "claude-3-opus", "concise"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_claude_settings.test", "model", "claude-3-opus"),
+					permissionsKept,
+				),
+			},
+		},
+	})
+}
+
+func testAccClaudeSettingsResourceExtraConfig(workDir, extra string) string {
+	return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = "%s"
+}
+
+resource "agentsmith_claude_settings" "test" {
+  scope = "project"
+  model = "claude-3-5-sonnet"
+
+  permissions {
+    allow = ["Bash(npm test)"]
+  }
+
+  extra_settings = jsonencode(%s)
+}
+`, workDir, extra)
+}
+
 func testAccClaudeSettingsResourceConfig(workDir, scope, model, outputStyle string) string {
 	return fmt.Sprintf(`
 provider "agentsmith" {
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// claudeStateFilePath returns the path of Claude Code's global state file, `~/.claude.json`, or
+// `$CLAUDE_CONFIG_DIR/.claude.json` when that variable is set. Besides preferences it holds
+// user-scope MCP servers, per-project entries and runtime state such as OAuth account details.
+func claudeStateFilePath() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, ".claude.json")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".claude.json")
+}
+
+// claudeProjectKey returns the key of a project under `projects` in the state file, which is
+// the project's absolute path.
+func claudeProjectKey(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Clean(abs), nil
+}
+
+// applyOwnedSettings removes the values owned by prior from doc and merges desired into it,
+// leaving everything else untouched. Objects are merged key by key, except mcpServers, whose
+// entries are owned per server name and always replaced as a whole. prior may be nil.
+func applyOwnedSettings(doc, prior, desired map[string]interface{}) map[string]interface{} {
+	out := doc
+	if prior != nil {
+		priorServers, _ := prior["mcpServers"].(map[string]interface{})
+		rest := make(map[string]interface{}, len(prior))
+		for k, v := range prior {
+			if k != "mcpServers" {
+				rest[k] = v
+			}
+		}
+		out = stripExtraSettings(out, rest)
+		out = setOwnedMCPServers(out, priorServers, nil)
+	}
+
+	desiredServers, _ := desired["mcpServers"].(map[string]interface{})
+	rest := make(map[string]interface{}, len(desired))
+	for k, v := range desired {
+		if k != "mcpServers" {
+			rest[k] = v
+		}
+	}
+	out = deepMergeJSON(out, rest)
+	return setOwnedMCPServers(out, nil, desiredServers)
+}
+
+// setOwnedMCPServers returns a copy of doc whose mcpServers object no longer holds the servers
+// named in remove and holds every server in set. An mcpServers object left empty is dropped.
+func setOwnedMCPServers(doc, remove, set map[string]interface{}) map[string]interface{} {
+	if len(remove) == 0 && len(set) == 0 {
+		return doc
+	}
+
+	existing, _ := doc["mcpServers"].(map[string]interface{})
+	servers := make(map[string]interface{}, len(existing)+len(set))
+	for name, v := range existing {
+		if _, owned := remove[name]; !owned {
+			servers[name] = v
+		}
+	}
+	for name, v := range set {
+		servers[name] = v
+	}
+
+	out := make(map[string]interface{}, len(doc)+1)
+	for k, v := range doc {
+		out[k] = v
+	}
+	if len(servers) > 0 {
+		out["mcpServers"] = servers
+	} else {
+		delete(out, "mcpServers")
+	}
+	return out
+}
+
+// claudeMCPServersValue renders a map of server name to JSON definition for the mcpServers key.
+// A null map renders to nil.
+func claudeMCPServersValue(ctx context.Context, diags *diag.Diagnostics, servers types.Map) (map[string]interface{}, error) {
+	if servers.IsNull() || servers.IsUnknown() {
+		return nil, nil
+	}
+	var raw map[string]string
+	if d := servers.ElementsAs(ctx, &raw, false); d.HasError() {
+		diags.Append(d...)
+		return nil, nil
+	}
+	return claudeMCPServersFromJSON(raw)
+}
+
+// refreshOwnedMCPServers refreshes the servers named in prior from the mcpServers object of
+// block. Servers added by other tools are ignored; a null prior stays null.
+func refreshOwnedMCPServers(ctx context.Context, diags *diag.Diagnostics, prior types.Map, block map[string]interface{}) types.Map {
+	if prior.IsNull() || prior.IsUnknown() {
+		return prior
+	}
+	var priorServers map[string]string
+	diags.Append(prior.ElementsAs(ctx, &priorServers, false)...)
+
+	current, _ := block["mcpServers"].(map[string]interface{})
+	owned := make(map[string]interface{}, len(priorServers))
+	for name := range priorServers {
+		if v, ok := current[name]; ok {
+			owned[name] = v
+		}
+	}
+
+	refreshed, d := types.MapValueFrom(ctx, types.StringType, refreshClaudeMCPServers(priorServers, owned))
+	diags.Append(d...)
+	return refreshed
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClaudeStateFile_applyOwnedSettings(t *testing.T) {
+	testCases := []struct {
+		name     string
+		doc      string
+		prior    string
+		desired  string
+		expected string
+	}{
+		{
+			name:     "sets_keys_and_keeps_runtime_state",
+			doc:      `{"numStartups":4,"theme":"light"}`,
+			desired:  `{"theme":"dark","verbose":true}`,
+			expected: `{"numStartups":4,"theme":"dark","verbose":true}`,
+		},
+		{
+			name:     "removes_keys_no_longer_managed",
+			doc:      `{"numStartups":4,"theme":"dark","verbose":true}`,
+			prior:    `{"theme":"dark","verbose":true}`,
+			desired:  `{"theme":"dark"}`,
+			expected: `{"numStartups":4,"theme":"dark"}`,
+		},
+		{
+			name:     "mcp_servers_owned_by_name",
+			doc:      `{"mcpServers":{"manual":{"command":"a"},"memory":{"command":"old","env":{"X":"1"}}}}`,
+			prior:    `{"mcpServers":{"memory":{"command":"old"}}}`,
+			desired:  `{"mcpServers":{"memory":{"command":"new"}}}`,
+			expected: `{"mcpServers":{"manual":{"command":"a"},"memory":{"command":"new"}}}`,
+		},
+		{
+			name:     "drops_empty_mcp_servers",
+			doc:      `{"mcpServers":{"memory":{"command":"npx"}},"theme":"dark"}`,
+			prior:    `{"mcpServers":{"memory":{"command":"npx"}}}`,
+			expected: `{"theme":"dark"}`,
+		},
+	}
+
+	decode := func(t *testing.T, s string) map[string]interface{} {
+		if s == "" {
+			return nil
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			t.Fatalf("Failed to parse %q: %v", s, err)
+		}
+		return m
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := applyOwnedSettings(decode(t, tc.doc), decode(t, tc.prior), decode(t, tc.desired))
+			if !jsonEqual(result, decode(t, tc.expected)) {
+				got, _ := json.Marshal(result)
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
@@ -5,15 +5,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"gopkg.in/yaml.v3"
+	"terraform-provider-agentsmith/internal/frontmatter"
 )
 
 var (
@@ -31,22 +35,29 @@ type claudeSubagentResource struct {
 }
 
 type claudeSubagentResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Scope       types.String `tfsdk:"scope"`
-	Name        types.String `tfsdk:"name"`
-	Model       types.String `tfsdk:"model"`
-	Description types.String `tfsdk:"description"`
-	Color       types.String `tfsdk:"color"`
-	Prompt      types.String `tfsdk:"prompt"`
+	ID               types.String `tfsdk:"id"`
+	Scope            types.String `tfsdk:"scope"`
+	Name             types.String `tfsdk:"name"`
+	Model            types.String `tfsdk:"model"`
+	Description      types.String `tfsdk:"description"`
+	Color            types.String `tfsdk:"color"`
+	Tools            types.List   `tfsdk:"tools"`
+	ExtraFrontmatter types.Map    `tfsdk:"extra_frontmatter"`
+	Prompt           types.String `tfsdk:"prompt"`
+	fileWriteOptionsModel
 }
 
 type subagentFrontmatter struct {
-	Name        string `yaml:"name"`
-	Model       string `yaml:"model,omitempty"`
-	Description string `yaml:"description,omitempty"`
-	Color       string `yaml:"color,omitempty"`
+	Name        string          `yaml:"name"`
+	Model       string          `yaml:"model,omitempty"`
+	Description string          `yaml:"description,omitempty"`
+	Color       string          `yaml:"color,omitempty"`
+	Tools       frontmatterList `yaml:"tools,omitempty"`
 }
 
+// subagentFrontmatterKeys are the frontmatter keys backed by dedicated attributes.
+var subagentFrontmatterKeys = []string{"name", "model", "description", "color", "tools"}
+
 func (r *claudeSubagentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_claude_subagent"
 }
@@ -88,12 +99,25 @@ func (r *claudeSubagentResource) Schema(_ context.Context, _ resource.SchemaRequ
 				Description: "A display color for the subagent in the UI, included in the YAML frontmatter.",
 				Optional:    true,
 			},
+			"tools": schema.ListAttribute{
+				Description: "The tools the subagent may use, e.g. `[\"Read\", \"Grep\", \"Glob\"]`. Each entry is a built-in tool name or an MCP tool of the form `mcp__<server>__<tool>`. When omitted, the subagent inherits every tool available to the main thread.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators:  []validator.List{claudeToolNames()},
+			},
+			"extra_frontmatter": schema.MapAttribute{
+				Description: "Additional YAML frontmatter keys, for keys that have no attribute in this resource. Each value is YAML, so `5` and `true` are written as a number and a boolean; quote a value in YAML, as in `\"'true'\"`, to write it as a string. Keys managed by other attributes are not allowed.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators:  []validator.Map{mapKeysNoneOf(subagentFrontmatterKeys...)},
+			},
 			"prompt": schema.StringAttribute{
 				Description: "The main instructional prompt for the subagent, which forms the body of the Markdown file.",
 				Required:    true,
 			},
 		},
 	}
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0644")
 }
 
 func (r *claudeSubagentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -110,12 +134,6 @@ func (r *claudeSubagentResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		resp.Diagnostics.AddError("Failed to create directory", err.Error())
-		return
-	}
-
 	// Create subagent file content
 	content, err := r.modelToMarkdown(&plan)
 	if err != nil {
@@ -123,7 +141,7 @@ func (r *claudeSubagentResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+	if err := plan.writeFile(filePath, []byte(content), 0644); err != nil {
 		resp.Diagnostics.AddError("Failed to write subagent file", err.Error())
 		return
 	}
@@ -193,7 +211,7 @@ func (r *claudeSubagentResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+	if err := plan.writeFile(filePath, []byte(content), 0644); err != nil {
 		resp.Diagnostics.AddError("Failed to write subagent file", err.Error())
 		return
 	}
@@ -257,7 +275,7 @@ func (r *claudeSubagentResource) Configure(_ context.Context, req resource.Confi
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *FileClient, got: %%T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *FileClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -284,64 +302,125 @@ func (r *claudeSubagentResource) getSubagentFilePath(scope, name string) string
 }
 
 func (r *claudeSubagentResource) modelToMarkdown(model *claudeSubagentResourceModel) (string, error) {
-	frontmatter := subagentFrontmatter{
+	fm := subagentFrontmatter{
 		Name: model.Name.ValueString(),
 	}
 
 	if !model.Model.IsNull() {
-		frontmatter.Model = model.Model.ValueString()
+		fm.Model = model.Model.ValueString()
 	}
 	if !model.Description.IsNull() {
-		frontmatter.Description = model.Description.ValueString()
+		fm.Description = model.Description.ValueString()
 	}
 	if !model.Color.IsNull() {
-		frontmatter.Color = model.Color.ValueString()
+		fm.Color = model.Color.ValueString()
 	}
+	fm.Tools = frontmatterListFrom(model.Tools)
 
-	yamlData, err := yaml.Marshal(frontmatter)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal YAML frontmatter: %%w", err)
+	extra := make(map[string]interface{}, len(model.ExtraFrontmatter.Elements()))
+	for k, v := range model.ExtraFrontmatter.Elements() {
+		if value, ok := v.(types.String); ok {
+			extra[k] = frontmatterValue(value.ValueString())
+		}
 	}
 
-	prompt := model.Prompt.ValueString()
-	content := fmt.Sprintf("---\n%%s---\n%%s", yamlData, prompt)
+	content, err := frontmatter.Marshal([]byte(model.Prompt.ValueString()), fm, extra)
+	if err != nil {
+		return "", err
+	}
 
-	return content, nil
+	return string(content), nil
 }
 
 func (r *claudeSubagentResource) markdownToModel(model *claudeSubagentResourceModel, content string) error {
-	parts := strings.SplitN(content, "---", 3)
-	if len(parts) < 3 {
-		return fmt.Errorf("invalid format: missing YAML frontmatter separators")
+	doc, err := frontmatter.Parse([]byte(content))
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
 	}
 
-	var frontmatter subagentFrontmatter
-	if err := yaml.Unmarshal([]byte(parts[1]), &frontmatter); err != nil {
-		return fmt.Errorf("failed to parse YAML frontmatter: %%w", err)
+	fm, extra, err := parseSubagentFrontmatter(doc)
+	if err != nil {
+		return err
 	}
 
 	// Update model with parsed data
-	model.Name = types.StringValue(frontmatter.Name)
+	model.Name = types.StringValue(fm.Name)
 
-	if frontmatter.Model != "" {
-		model.Model = types.StringValue(frontmatter.Model)
+	if fm.Model != "" {
+		model.Model = types.StringValue(fm.Model)
 	} else {
 		model.Model = types.StringNull()
 	}
 
-	if frontmatter.Description != "" {
-		model.Description = types.StringValue(frontmatter.Description)
+	if fm.Description != "" {
+		model.Description = types.StringValue(fm.Description)
 	} else {
 		model.Description = types.StringNull()
 	}
 
-	if frontmatter.Color != "" {
-		model.Color = types.StringValue(frontmatter.Color)
+	if fm.Color != "" {
+		model.Color = types.StringValue(fm.Color)
 	} else {
 		model.Color = types.StringNull()
 	}
 
-	model.Prompt = types.StringValue(strings.TrimSpace(parts[2]))
+	model.Tools = frontmatterListValue(fm.Tools)
+	// Values written differently but with the same YAML value, e.g. 'a' and "a", keep the prior spelling
+	for k, v := range model.ExtraFrontmatter.Elements() {
+		prior, ok := v.(types.String)
+		if current, found := extra[k]; ok && found && reflect.DeepEqual(frontmatterValue(prior.ValueString()), frontmatterValue(current)) {
+			extra[k] = prior.ValueString()
+		}
+	}
+	model.ExtraFrontmatter = subagentExtraFrontmatterValue(extra)
+
+	// The body is kept byte for byte so prompts ending in a newline do not show a diff
+	model.Prompt = types.StringValue(string(doc.Body))
 
 	return nil
 }
+
+// parseSubagentFrontmatter decodes the frontmatter of a subagent file into the known keys and
+// the remaining keys. Values of the remaining keys are returned as YAML, except for strings that
+// read the same as plain text.
+func parseSubagentFrontmatter(doc frontmatter.Document) (subagentFrontmatter, map[string]string, error) {
+	var fm subagentFrontmatter
+	if !doc.HasFrontmatter() {
+		return fm, nil, fmt.Errorf("invalid format: missing YAML frontmatter")
+	}
+	if err := doc.Decode(&fm); err != nil {
+		return fm, nil, err
+	}
+
+	all, err := doc.Extra(subagentFrontmatterKeys...)
+	if err != nil {
+		return fm, nil, err
+	}
+	extra := make(map[string]string, len(all))
+	for k, v := range all {
+		if s, ok := v.(string); ok {
+			if d, ok := frontmatterValue(s).(string); ok && d == s {
+				extra[k] = s
+				continue
+			}
+		}
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return fm, nil, fmt.Errorf("failed to encode frontmatter key %q: %w", k, err)
+		}
+		extra[k] = strings.TrimSpace(string(b))
+	}
+	return fm, extra, nil
+}
+
+// subagentExtraFrontmatterValue returns the extra frontmatter map, or null when there is none.
+func subagentExtraFrontmatterValue(extra map[string]string) types.Map {
+	if len(extra) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	elems := make(map[string]attr.Value, len(extra))
+	for k, v := range extra {
+		elems[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elems)
+}
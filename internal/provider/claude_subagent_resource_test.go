@@ -227,6 +227,18 @@ Simple prompt.`,
 				}
 			},
 		},
+		{
+			name:    "horizontal_rule_and_trailing_newline",
+			content: "---\nname: writer\ndescription: Uses --- in prose\n---\nIntro\n\n---\n\nOutro\n",
+			validate: func(t *testing.T, model *claudeSubagentResourceModel) {
+				if model.Description.ValueString() != "Uses --- in prose" {
+					t.Errorf("Expected description with dashes, got %q", model.Description.ValueString())
+				}
+				if model.Prompt.ValueString() != "Intro\n\n---\n\nOutro\n" {
+					t.Errorf("Expected prompt kept byte for byte, got %q", model.Prompt.ValueString())
+				}
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -242,6 +254,78 @@ Simple prompt.`,
 	}
 }
 
+func TestClaudeSubagentResource_toolsAndExtraFrontmatter(t *testing.T) {
+	r := &claudeSubagentResource{}
+
+	model := claudeSubagentResourceModel{
+		Name:  types.StringValue("reviewer"),
+		Tools: frontmatterListValue(frontmatterList{"Read", "Grep", "Glob", "mcp__github__get_issue"}),
+		ExtraFrontmatter: subagentExtraFrontmatterValue(map[string]string{
+			"permissionMode": "plan",
+			"version":        "2",
+			"experimental":   "true",
+			"label":          "'true'",
+		}),
+		Prompt: types.StringValue("Review the change."),
+	}
+
+	content, err := r.modelToMarkdown(&model)
+	if err != nil {
+		t.Fatalf("Failed to convert model to markdown: %v", err)
+	}
+	if !strings.Contains(content, "tools: Read, Grep, Glob, mcp__github__get_issue\n") {
+		t.Errorf("Expected tools as a comma-separated string, got:\n%s", content)
+	}
+	if !strings.Contains(content, "permissionMode: plan\n") {
+		t.Errorf("Expected extra frontmatter keys, got:\n%s", content)
+	}
+	// Values keep their YAML types
+	for _, want := range []string{"version: 2\n", "experimental: true\n", "label: \"true\"\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q, got:\n%s", want, content)
+		}
+	}
+
+	var read claudeSubagentResourceModel
+	if err := r.markdownToModel(&read, content); err != nil {
+		t.Fatalf("Failed to parse markdown: %v", err)
+	}
+	if !read.Tools.Equal(model.Tools) {
+		t.Errorf("Expected tools %v, got %v", model.Tools, read.Tools)
+	}
+	if v := read.ExtraFrontmatter.Elements()["label"]; !v.Equal(types.StringValue(`"true"`)) {
+		t.Errorf("Expected label to be read as a quoted string, got %v", v)
+	}
+
+	// Values spelled differently in state but equal as YAML keep their spelling
+	read = model
+	if err := r.markdownToModel(&read, content); err != nil {
+		t.Fatalf("Failed to parse markdown: %v", err)
+	}
+	if !read.ExtraFrontmatter.Equal(model.ExtraFrontmatter) {
+		t.Errorf("Expected extra frontmatter %v, got %v", model.ExtraFrontmatter, read.ExtraFrontmatter)
+	}
+
+	// Hand-written files may list tools as a YAML sequence and use non-string values
+	if err := r.markdownToModel(&read, "---\nname: reviewer\ntools:\n  - Read\n  - Grep\nmaxTurns: 5\n---\nPrompt."); err != nil {
+		t.Fatalf("Failed to parse markdown: %v", err)
+	}
+	if len(read.Tools.Elements()) != 2 {
+		t.Errorf("Expected 2 tools, got %v", read.Tools)
+	}
+	if v := read.ExtraFrontmatter.Elements()["maxTurns"]; !v.Equal(types.StringValue("5")) {
+		t.Errorf("Expected maxTurns to be rendered as YAML, got %v", v)
+	}
+
+	// Files without tools or extra keys read back as null
+	if err := r.markdownToModel(&read, "---\nname: reviewer\n---\nPrompt."); err != nil {
+		t.Fatalf("Failed to parse markdown: %v", err)
+	}
+	if !read.Tools.IsNull() || !read.ExtraFrontmatter.IsNull() {
+		t.Errorf("Expected null tools and extra frontmatter, got %v and %v", read.Tools, read.ExtraFrontmatter)
+	}
+}
+
 func TestClaudeSubagentResource_YAMLFrontmatterParsing(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -541,6 +625,10 @@ func TestAccClaudeSubagentResource_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("agentsmith_claude_subagent.test", "description", "A test agent"),
 					resource.TestCheckResourceAttr("agentsmith_claude_subagent.test", "color", "blue"),
 					resource.TestCheckResourceAttr("agentsmith_claude_subagent.test", "prompt", "You are a helpful test assistant."),
+					resource.TestCheckResourceAttr("agentsmith_claude_subagent.test", "tools.#", "3"),
+					resource.TestCheckResourceAttr("agentsmith_claude_subagent.test", "tools.2", "Glob"),
+					resource.TestCheckResourceAttr("agentsmith_claude_subagent.test", "extra_frontmatter.permissionMode", "plan"),
+					resource.TestCheckResourceAttr("agentsmith_claude_subagent.test", "extra_frontmatter.maxTurns", "5"),
 					resource.TestCheckResourceAttrSet("agentsmith_claude_subagent.test", "id"),
 				),
 			},
@@ -579,6 +667,12 @@ resource "agentsmith_claude_subagent" "test" {
   description = "%s"
   color       = "%s"
   prompt      = "%s"
+  tools       = ["Read", "Grep", "Glob"]
+
+  extra_frontmatter = {
+    permissionMode = "plan"
+    maxTurns       = "5"
+  }
 }
 `, workDir, scope, name, model, description, color, prompt)
 }
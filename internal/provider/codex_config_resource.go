@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -14,19 +15,35 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"terraform-provider-agentsmith/internal/codex/configio"
+	"terraform-provider-agentsmith/internal/fileio"
 
 	toml "github.com/pelletier/go-toml/v2"
 	"os"
 )
 
 var (
-	_ resource.Resource                = &codexConfigResource{}
-	_ resource.ResourceWithConfigure   = &codexConfigResource{}
-	_ resource.ResourceWithImportState = &codexConfigResource{}
+	_ resource.Resource                   = &codexConfigResource{}
+	_ resource.ResourceWithConfigure      = &codexConfigResource{}
+	_ resource.ResourceWithImportState    = &codexConfigResource{}
+	_ resource.ResourceWithValidateConfig = &codexConfigResource{}
+	_ resource.ResourceWithModifyPlan     = &codexConfigResource{}
 )
 
 func NewCodexConfigResource() resource.Resource { return &codexConfigResource{} }
 
+// codexMergeTables are the tables of config.toml that merge entry by entry unless listed in
+// owned_tables.
+var codexMergeTables = []string{
+	"history", "mcp_servers", "model_providers", "profiles", "sandbox_workspace_write", "shell_environment_policy", "tui",
+}
+
+// codexConfigOwner records the keys written by agentsmith_codex_config in the marker table.
+const codexConfigOwner = "agentsmith_codex_config"
+
+// codexConfigImportedKey is the private state key set by ImportState, so that the first Read
+// populates every attribute from the file rather than only the ones already in state.
+const codexConfigImportedKey = "imported"
+
 type codexConfigResource struct {
 	client *FileClient
 }
@@ -34,17 +51,19 @@ type codexConfigResource struct {
 // Resource model
 type codexConfigResourceModel struct {
 	// Meta
-	ID                      types.String `tfsdk:"id"`
-	Scope                   types.String `tfsdk:"scope"`
-	Path                    types.String `tfsdk:"path"`
-	ResolvedPath            types.String `tfsdk:"resolved_path"`
-	MergeStrategy           types.String `tfsdk:"merge_strategy"`
-	CreateDirectories       types.Bool   `tfsdk:"create_directories"`
-	FileMode                types.String `tfsdk:"file_mode"`
-	BackupOnWrite           types.Bool   `tfsdk:"backup_on_write"`
-	AllowSensitiveEnvWrites types.Bool   `tfsdk:"allow_sensitive_env_writes"`
-	KeepFileOnDestroy       types.Bool   `tfsdk:"keep_file_on_destroy"`
-	ValidateStrict          types.Bool   `tfsdk:"validate_strict"`
+	ID                      types.String   `tfsdk:"id"`
+	Scope                   types.String   `tfsdk:"scope"`
+	Path                    types.String   `tfsdk:"path"`
+	ResolvedPath            types.String   `tfsdk:"resolved_path"`
+	MergeStrategy           types.String   `tfsdk:"merge_strategy"`
+	OwnedTables             []types.String `tfsdk:"owned_tables"`
+	CreateDirectories       types.Bool     `tfsdk:"create_directories"`
+	FileMode                types.String   `tfsdk:"file_mode"`
+	BackupOnWrite           types.Bool     `tfsdk:"backup_on_write"`
+	AllowSensitiveEnvWrites types.Bool     `tfsdk:"allow_sensitive_env_writes"`
+	KeepFileOnDestroy       types.Bool     `tfsdk:"keep_file_on_destroy"`
+	ValidateStrict          types.Bool     `tfsdk:"validate_strict"`
+	ExtraSettings           types.String   `tfsdk:"extra_settings"`
 
 	// Config root
 	Profile                         types.String   `tfsdk:"profile"`
@@ -109,18 +128,41 @@ type codexModelProviderWriteModel struct {
 }
 
 type codexMCPServerWriteModel struct {
-	ID      types.String   `tfsdk:"id"`
-	Command types.String   `tfsdk:"command"`
-	Args    []types.String `tfsdk:"args"`
-	Env     types.Map      `tfsdk:"env"`
+	ID                types.String   `tfsdk:"id"`
+	Command           types.String   `tfsdk:"command"`
+	Args              []types.String `tfsdk:"args"`
+	Env               types.Map      `tfsdk:"env"`
+	Cwd               types.String   `tfsdk:"cwd"`
+	URL               types.String   `tfsdk:"url"`
+	BearerTokenEnvVar types.String   `tfsdk:"bearer_token_env_var"`
+	HTTPHeaders       types.Map      `tfsdk:"http_headers"`
+	EnvHTTPHeaders    types.Map      `tfsdk:"env_http_headers"`
+	StartupTimeoutSec types.Float64  `tfsdk:"startup_timeout_sec"`
+	ToolTimeoutSec    types.Float64  `tfsdk:"tool_timeout_sec"`
+	Enabled           types.Bool     `tfsdk:"enabled"`
+	EnabledTools      []types.String `tfsdk:"enabled_tools"`
+	DisabledTools     []types.String `tfsdk:"disabled_tools"`
 }
 
 type codexProfileWriteModel struct {
-	Name           types.String `tfsdk:"name"`
-	Model          types.String `tfsdk:"model"`
-	ModelProvider  types.String `tfsdk:"model_provider"`
-	ApprovalPolicy types.String `tfsdk:"approval_policy"`
-	SandboxMode    types.String `tfsdk:"sandbox_mode"`
+	Name                            types.String `tfsdk:"name"`
+	Model                           types.String `tfsdk:"model"`
+	ModelProvider                   types.String `tfsdk:"model_provider"`
+	ModelContextWindow              types.Int64  `tfsdk:"model_context_window"`
+	ModelMaxOutputTokens            types.Int64  `tfsdk:"model_max_output_tokens"`
+	ApprovalPolicy                  types.String `tfsdk:"approval_policy"`
+	SandboxMode                     types.String `tfsdk:"sandbox_mode"`
+	FileOpener                      types.String `tfsdk:"file_opener"`
+	HideAgentReasoning              types.Bool   `tfsdk:"hide_agent_reasoning"`
+	ShowRawAgentReasoning           types.Bool   `tfsdk:"show_raw_agent_reasoning"`
+	ModelReasoningEffort            types.String `tfsdk:"model_reasoning_effort"`
+	ModelReasoningSummary           types.String `tfsdk:"model_reasoning_summary"`
+	ModelVerbosity                  types.String `tfsdk:"model_verbosity"`
+	ModelSupportsReasoningSummaries types.Bool   `tfsdk:"model_supports_reasoning_summaries"`
+	ProjectDocMaxBytes              types.Int64  `tfsdk:"project_doc_max_bytes"`
+
+	SandboxWorkspaceWrite *codexSandboxWorkspaceWriteModelR `tfsdk:"sandbox_workspace_write"`
+	ShellEnvPolicy        *codexShellEnvPolicyModelR        `tfsdk:"shell_environment_policy"`
 }
 
 func (r *codexConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -152,15 +194,21 @@ func (r *codexConfigResource) Schema(_ context.Context, _ resource.SchemaRequest
 				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"merge_strategy": schema.StringAttribute{
-				Description: "Defines how to handle existing `config.toml` files. `preserve_unknown` (default) merges managed settings while keeping unmanaged keys. `replace_all` overwrites the file with only managed settings. `fail_on_unknown` fails if the existing file contains unmanaged keys.",
+				Description: "Defines how to handle existing `config.toml` files. `preserve_unknown` (default) merges managed settings while keeping unmanaged keys, comments and formatting. `replace_all` overwrites the file with only managed settings. `fail_on_unknown` fails if the existing file contains unmanaged keys.",
+				Optional:    true,
+			},
+			"owned_tables": schema.ListAttribute{
+				Description: "Tables that Terraform owns wholesale when merging into an existing file. Entries and keys of these tables that are not configured are removed from the file. Other tables merge entry by entry, keeping MCP servers, model providers, profiles and keys configured outside Terraform. One of `" + strings.Join(codexMergeTables, "`, `") + "`.",
+				ElementType: types.StringType,
 				Optional:    true,
 			},
 			"create_directories":         schema.BoolAttribute{Description: "If true, creates parent directories for the config file if they do not exist. Defaults to `true`.", Optional: true},
-			"file_mode":                  schema.StringAttribute{Description: "The file mode to set on the `config.toml` file, in octal format (e.g., `0600`). Defaults to `0600`.", Optional: true},
+			"file_mode":                  schema.StringAttribute{Description: "The file mode to set on the `config.toml` file, in octal format (e.g., `0600`). Defaults to the mode of the existing file, or `0600` for a new file.", Optional: true},
 			"backup_on_write":            schema.BoolAttribute{Description: "If true, creates a `.bak` file before writing changes. Defaults to `true`.", Optional: true},
 			"allow_sensitive_env_writes": schema.BoolAttribute{Description: "If true, allows writing sensitive environment variables for MCP servers to the config file. Defaults to `false`.", Optional: true},
-			"keep_file_on_destroy":       schema.BoolAttribute{Description: "If true, the `config.toml` file will be kept on disk when the resource is destroyed. Defaults to `true`.", Optional: true},
-			"validate_strict":            schema.BoolAttribute{Description: "If true, performs strict validation of the final configuration against the Codex schema. Defaults to `true`.", Optional: true},
+			"keep_file_on_destroy":       schema.BoolAttribute{Description: "If true, the `config.toml` file is kept on disk when the resource is destroyed, and only the keys written by this resource are removed from it. Defaults to `true`.", Optional: true},
+			"validate_strict":            schema.BoolAttribute{Description: "If true, performs strict validation of the final configuration against the Codex schema, including enum values and references between `profile`, `profiles`, `model_provider` and `model_providers`. Defaults to `true`.", Optional: true},
+			"extra_settings":             extraSettingsAttribute("`config.toml`"),
 
 			// Top-level scalars
 			"profile":                            schema.StringAttribute{Description: "The name of the active profile to use from the `profiles` block.", Optional: true},
@@ -182,15 +230,7 @@ func (r *codexConfigResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"tui":                                schema.MapAttribute{Description: "A map of options specific to the Text User Interface (TUI).", ElementType: types.StringType, Optional: true},
 		},
 		Blocks: map[string]schema.Block{
-			"sandbox_workspace_write": schema.SingleNestedBlock{
-				Description: "Specific settings for the `workspace-write` sandbox mode.",
-				Attributes: map[string]schema.Attribute{
-					"writable_roots":         schema.ListAttribute{Description: "A list of additional writable root paths beyond the defaults.", ElementType: types.StringType, Optional: true},
-					"network_access":         schema.BoolAttribute{Description: "If true, allows the command being run inside the sandbox to make outbound network requests. Default: false.", Optional: true},
-					"exclude_tmpdir_env_var": schema.BoolAttribute{Description: "If true, excludes the `$TMPDIR` environment variable from writable roots.", Optional: true},
-					"exclude_slash_tmp":      schema.BoolAttribute{Description: "If true, excludes the `/tmp` directory from writable roots.", Optional: true},
-				},
-			},
+			"sandbox_workspace_write": codexSandboxWorkspaceWriteBlock(),
 			"history": schema.SingleNestedBlock{
 				Description: "Settings for command history persistence.",
 				Attributes: map[string]schema.Attribute{
@@ -198,55 +238,216 @@ func (r *codexConfigResource) Schema(_ context.Context, _ resource.SchemaRequest
 					"max_bytes":   schema.Int64Attribute{Description: "The maximum size of the history file in bytes.", Optional: true},
 				},
 			},
-			"shell_environment_policy": schema.SingleNestedBlock{
-				Description: "Policy for managing environment variables passed to subprocesses.",
-				Attributes: map[string]schema.Attribute{
-					"inherit":                 schema.StringAttribute{Description: "The starting template for the environment: `all` (default), `core`, or `none`.", Optional: true},
-					"ignore_default_excludes": schema.BoolAttribute{Description: "If false (default), automatically removes variables containing `KEY`, `SECRET`, or `TOKEN`.", Optional: true},
-					"exclude":                 schema.ListAttribute{Description: "A list of case-insensitive glob patterns for environment variables to exclude.", ElementType: types.StringType, Optional: true},
-					"set":                     schema.MapAttribute{Description: "A map of key/value pairs to explicitly set or override.", ElementType: types.StringType, Optional: true},
-					"include_only":            schema.ListAttribute{Description: "If non-empty, acts as a whitelist of glob patterns for variables to keep.", ElementType: types.StringType, Optional: true},
-				},
-			},
+			"shell_environment_policy": codexShellEnvPolicyBlock(),
 			"model_providers": schema.ListNestedBlock{
-				Description: "Defines a set of model providers that can be used by Codex.",
-				NestedObject: schema.NestedBlockObject{Attributes: map[string]schema.Attribute{
-					"id":                     schema.StringAttribute{Description: "The unique identifier for the model provider (e.g., `openai-chat-completions`).", Required: true},
-					"name":                   schema.StringAttribute{Description: "The display name of the provider.", Optional: true},
-					"base_url":               schema.StringAttribute{Description: "The base URL for the provider's API.", Optional: true},
-					"env_key":                schema.StringAttribute{Description: "The environment variable that holds the API key for this provider.", Optional: true},
-					"wire_api":               schema.StringAttribute{Description: "The wire protocol to use (`chat` or `responses`). Defaults to `chat`.", Optional: true},
-					"query_params":           schema.MapAttribute{Description: "A map of extra query parameters to add to requests (e.g., `api-version` for Azure).", ElementType: types.StringType, Optional: true},
-					"http_headers":           schema.MapAttribute{Description: "A map of static HTTP headers to add to requests.", ElementType: types.StringType, Optional: true},
-					"env_http_headers":       schema.MapAttribute{Description: "A map of HTTP headers to add to requests, with values sourced from environment variables.", ElementType: types.StringType, Optional: true},
-					"request_max_retries":    schema.Int64Attribute{Description: "How many times to retry a failed HTTP request. Default: 4.", Optional: true},
-					"stream_max_retries":     schema.Int64Attribute{Description: "How many times to reconnect a dropped streaming response. Default: 5.", Optional: true},
-					"stream_idle_timeout_ms": schema.Int64Attribute{Description: "How long in milliseconds to wait for activity on a streaming response before timing out. Default: 300000 (5 minutes).", Optional: true},
-				}},
+				Description:  "Defines a set of model providers that can be used by Codex.",
+				NestedObject: schema.NestedBlockObject{Attributes: codexModelProviderAttributes()},
 			},
 			"mcp_servers": schema.ListNestedBlock{
-				Description: "Defines a set of MCP (Model-Context Protocol) servers for custom tool discovery.",
-				NestedObject: schema.NestedBlockObject{Attributes: map[string]schema.Attribute{
-					"id":      schema.StringAttribute{Description: "The unique identifier for the MCP server.", Required: true},
-					"command": schema.StringAttribute{Description: "The command to execute to start the server.", Required: true},
-					"args":    schema.ListAttribute{Description: "A list of arguments for the command.", ElementType: types.StringType, Optional: true},
-					"env":     schema.MapAttribute{Description: "A map of environment variables to set for the server process.", ElementType: types.StringType, Optional: true, Sensitive: true},
-				}},
+				Description:  "Defines a set of MCP (Model-Context Protocol) servers for custom tool discovery.",
+				NestedObject: schema.NestedBlockObject{Attributes: codexMCPServerAttributes()},
 			},
 			"profiles": schema.ListNestedBlock{
 				Description: "Defines a set of configuration profiles.",
-				NestedObject: schema.NestedBlockObject{Attributes: map[string]schema.Attribute{
-					"name":            schema.StringAttribute{Description: "The name of the profile.", Required: true},
-					"model":           schema.StringAttribute{Description: "The model associated with this profile.", Optional: true},
-					"model_provider":  schema.StringAttribute{Description: "The model provider associated with this profile.", Optional: true},
-					"approval_policy": schema.StringAttribute{Description: "The approval policy associated with this profile.", Optional: true},
-					"sandbox_mode":    schema.StringAttribute{Description: "The sandbox mode associated with this profile.", Optional: true},
-				}},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: codexProfileAttributes(),
+					Blocks:     codexProfileBlocks(),
+				},
 			},
 		},
 	}
 }
 
+// codexModelProviderAttributes describes a `[model_providers.<id>]` table, for the
+// model_providers block and agentsmith_codex_model_provider.
+func codexModelProviderAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id":                     schema.StringAttribute{Description: "The unique identifier for the model provider (e.g., `openai-chat-completions`).", Required: true},
+		"name":                   schema.StringAttribute{Description: "The display name of the provider.", Optional: true},
+		"base_url":               schema.StringAttribute{Description: "The base URL for the provider's API.", Optional: true},
+		"env_key":                schema.StringAttribute{Description: "The environment variable that holds the API key for this provider.", Optional: true},
+		"wire_api":               schema.StringAttribute{Description: "The wire protocol to use (`chat` or `responses`). Defaults to `chat`.", Optional: true},
+		"query_params":           schema.MapAttribute{Description: "A map of extra query parameters to add to requests (e.g., `api-version` for Azure).", ElementType: types.StringType, Optional: true},
+		"http_headers":           schema.MapAttribute{Description: "A map of static HTTP headers to add to requests.", ElementType: types.StringType, Optional: true},
+		"env_http_headers":       schema.MapAttribute{Description: "A map of HTTP headers to add to requests, with values sourced from environment variables.", ElementType: types.StringType, Optional: true},
+		"request_max_retries":    schema.Int64Attribute{Description: "How many times to retry a failed HTTP request. Default: 4.", Optional: true},
+		"stream_max_retries":     schema.Int64Attribute{Description: "How many times to reconnect a dropped streaming response. Default: 5.", Optional: true},
+		"stream_idle_timeout_ms": schema.Int64Attribute{Description: "How long in milliseconds to wait for activity on a streaming response before timing out. Default: 300000 (5 minutes).", Optional: true},
+	}
+}
+
+// codexMCPServerAttributes describes a `[mcp_servers.<id>]` table, for the mcp_servers block and
+// agentsmith_codex_mcp_server.
+func codexMCPServerAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id":                   schema.StringAttribute{Description: "The unique identifier for the MCP server.", Required: true},
+		"command":              schema.StringAttribute{Description: "The command to execute to start a stdio server. Exactly one of `command` and `url` must be set.", Optional: true},
+		"args":                 schema.ListAttribute{Description: "A list of arguments for the command.", ElementType: types.StringType, Optional: true},
+		"env":                  schema.MapAttribute{Description: "A map of environment variables to set for the server process.", ElementType: types.StringType, Optional: true, Sensitive: true},
+		"cwd":                  schema.StringAttribute{Description: "The working directory of the server process.", Optional: true},
+		"url":                  schema.StringAttribute{Description: "The URL of a streamable HTTP server.", Optional: true},
+		"bearer_token_env_var": schema.StringAttribute{Description: "The environment variable that holds the bearer token sent to an HTTP server.", Optional: true},
+		"http_headers":         schema.MapAttribute{Description: "A map of static HTTP headers sent to an HTTP server.", ElementType: types.StringType, Optional: true},
+		"env_http_headers":     schema.MapAttribute{Description: "A map of HTTP headers sent to an HTTP server, with values sourced from the named environment variables.", ElementType: types.StringType, Optional: true},
+		"startup_timeout_sec":  schema.Float64Attribute{Description: "How long in seconds to wait for the server to start. Default: 10.", Optional: true},
+		"tool_timeout_sec":     schema.Float64Attribute{Description: "How long in seconds to wait for a tool call to complete. Default: 60.", Optional: true},
+		"enabled":              schema.BoolAttribute{Description: "Whether the server is enabled. Default: true.", Optional: true},
+		"enabled_tools":        schema.ListAttribute{Description: "If set, only these tools of the server are exposed.", ElementType: types.StringType, Optional: true},
+		"disabled_tools":       schema.ListAttribute{Description: "Tools of the server that are not exposed.", ElementType: types.StringType, Optional: true},
+	}
+}
+
+// codexProfileAttributes describes a `[profiles.<name>]` table, for the profiles block and
+// agentsmith_codex_profile.
+func codexProfileAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name":                               schema.StringAttribute{Description: "The name of the profile.", Required: true},
+		"model":                              schema.StringAttribute{Description: "The model associated with this profile.", Optional: true},
+		"model_provider":                     schema.StringAttribute{Description: "The model provider associated with this profile.", Optional: true},
+		"model_context_window":               schema.Int64Attribute{Description: "The context window size for the model of this profile, in tokens.", Optional: true},
+		"model_max_output_tokens":            schema.Int64Attribute{Description: "The maximum number of output tokens for the model of this profile.", Optional: true},
+		"approval_policy":                    schema.StringAttribute{Description: "The approval policy associated with this profile.", Optional: true},
+		"sandbox_mode":                       schema.StringAttribute{Description: "The sandbox mode associated with this profile.", Optional: true},
+		"file_opener":                        schema.StringAttribute{Description: "The editor/URI scheme for hyperlinking file citations in this profile.", Optional: true},
+		"hide_agent_reasoning":               schema.BoolAttribute{Description: "If true, this profile suppresses the model's internal 'thinking' events.", Optional: true},
+		"show_raw_agent_reasoning":           schema.BoolAttribute{Description: "If true, this profile surfaces the model’s raw chain-of-thought, if available.", Optional: true},
+		"model_reasoning_effort":             schema.StringAttribute{Description: "Reasoning effort for this profile (`minimal`, `low`, `medium`, `high`).", Optional: true},
+		"model_reasoning_summary":            schema.StringAttribute{Description: "Reasoning summary detail for this profile (`auto`, `concise`, `detailed`, `none`).", Optional: true},
+		"model_verbosity":                    schema.StringAttribute{Description: "Output verbosity for this profile (`low`, `medium`, `high`).", Optional: true},
+		"model_supports_reasoning_summaries": schema.BoolAttribute{Description: "If true, this profile forces reasoning to be set on requests.", Optional: true},
+		"project_doc_max_bytes":              schema.Int64Attribute{Description: "Maximum number of bytes this profile reads from an `AGENTS.md` file.", Optional: true},
+	}
+}
+
+func codexProfileBlocks() map[string]schema.Block {
+	return map[string]schema.Block{
+		"sandbox_workspace_write":  codexSandboxWorkspaceWriteBlock(),
+		"shell_environment_policy": codexShellEnvPolicyBlock(),
+	}
+}
+
+// codexSandboxWorkspaceWriteBlock is shared by the top level and profiles.
+func codexSandboxWorkspaceWriteBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Specific settings for the `workspace-write` sandbox mode.",
+		Attributes: map[string]schema.Attribute{
+			"writable_roots":         schema.ListAttribute{Description: "A list of additional writable root paths beyond the defaults.", ElementType: types.StringType, Optional: true},
+			"network_access":         schema.BoolAttribute{Description: "If true, allows the command being run inside the sandbox to make outbound network requests. Default: false.", Optional: true},
+			"exclude_tmpdir_env_var": schema.BoolAttribute{Description: "If true, excludes the `$TMPDIR` environment variable from writable roots.", Optional: true},
+			"exclude_slash_tmp":      schema.BoolAttribute{Description: "If true, excludes the `/tmp` directory from writable roots.", Optional: true},
+		},
+	}
+}
+
+// codexShellEnvPolicyBlock is shared by the top level and profiles.
+func codexShellEnvPolicyBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Policy for managing environment variables passed to subprocesses.",
+		Attributes: map[string]schema.Attribute{
+			"inherit":                 schema.StringAttribute{Description: "The starting template for the environment: `all` (default), `core`, or `none`.", Optional: true},
+			"ignore_default_excludes": schema.BoolAttribute{Description: "If false (default), automatically removes variables containing `KEY`, `SECRET`, or `TOKEN`.", Optional: true},
+			"exclude":                 schema.ListAttribute{Description: "A list of case-insensitive glob patterns for environment variables to exclude.", ElementType: types.StringType, Optional: true},
+			"set":                     schema.MapAttribute{Description: "A map of key/value pairs to explicitly set or override.", ElementType: types.StringType, Optional: true},
+			"include_only":            schema.ListAttribute{Description: "If non-empty, acts as a whitelist of glob patterns for variables to keep.", ElementType: types.StringType, Optional: true},
+		},
+	}
+}
+
+func (r *codexConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config codexConfigResourceModel
+	// Blocks that are not known yet cannot be decoded; they are checked again on apply.
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		return
+	}
+
+	for i, server := range config.MCPServers {
+		validateCodexMCPServer(&resp.Diagnostics, path.Root("mcp_servers").AtListIndex(i), server)
+	}
+	validateCodexUniqueIDs(&resp.Diagnostics, "mcp_servers", "id", config.MCPServers, func(m codexMCPServerWriteModel) types.String { return m.ID })
+	validateCodexUniqueIDs(&resp.Diagnostics, "model_providers", "id", config.ModelProviders, func(m codexModelProviderWriteModel) types.String { return m.ID })
+	validateCodexUniqueIDs(&resp.Diagnostics, "profiles", "name", config.Profiles, func(m codexProfileWriteModel) types.String { return m.Name })
+
+	for i, table := range config.OwnedTables {
+		if table.IsNull() || table.IsUnknown() || slices.Contains(codexMergeTables, table.ValueString()) {
+			continue
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("owned_tables").AtListIndex(i),
+			"Invalid owned table",
+			fmt.Sprintf("%q is not a table of config.toml. Must be one of: %s.", table.ValueString(), strings.Join(codexMergeTables, ", ")),
+		)
+	}
+
+	if config.ExtraSettings.IsNull() {
+		return
+	}
+
+	if extra, err := parseExtraSettings(config.ExtraSettings); err == nil {
+		if nulls := extraSettingsNullPaths(extra); len(nulls) > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("extra_settings"),
+				"Invalid extra_settings",
+				fmt.Sprintf("TOML has no null value; remove these keys instead: %s.", strings.Join(nulls, ", ")),
+			)
+		}
+	}
+
+	var d diag.Diagnostics
+	if typed, err := r.planToMap(ctx, &d, &config); err == nil {
+		validateExtraSettings(&resp.Diagnostics, config.ExtraSettings, typed)
+	}
+}
+
+// ModifyPlan checks the file that will be written for enum values outside the documented sets
+// and references that do not resolve, reporting them at the attributes that set them. Keys of
+// the file that Terraform does not set are left to Codex.
+func (r *codexConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan codexConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !boolOrDefault(plan.ValidateStrict, true) {
+		return
+	}
+
+	// Rendering errors are reported by ValidateConfig or on apply.
+	var d diag.Diagnostics
+	typed, err := r.planToMap(ctx, &d, &plan)
+	if err != nil {
+		return
+	}
+	desired, err := mergeExtraSettings(typed, plan.ExtraSettings)
+	if err != nil {
+		return
+	}
+
+	merged := desired
+	if !strings.EqualFold(plan.MergeStrategy.ValueString(), "replace_all") {
+		if resolvedPath, err := r.resolveTargetPath(plan); err == nil {
+			if existing, err := configio.ReadTOMLMap(resolvedPath); err == nil {
+				merged = configio.MergePreserveUnknown(existing, desired, codexConfigOwner, stringsFromList(plan.OwnedTables)...)
+			}
+		}
+	}
+
+	for _, issue := range configio.Validate(merged) {
+		if _, ok := configio.LookupKeyPath(desired, issue.Path); !ok {
+			continue
+		}
+		attr := path.Root("extra_settings")
+		if _, ok := configio.LookupKeyPath(typed, issue.Path); ok {
+			attr = codexAttributePath(&plan, issue.Path)
+		}
+		if issue.Reference {
+			resp.Diagnostics.AddAttributeWarning(attr, "Unresolved Codex reference", issue.Message+" It must be defined in the file by the time Codex reads it, for example by another resource that writes to the same file.")
+			continue
+		}
+		resp.Diagnostics.AddAttributeError(attr, "Invalid Codex configuration", issue.Message)
+	}
+}
+
 func (r *codexConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan codexConfigResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -260,9 +461,13 @@ func (r *codexConfigResource) Create(ctx context.Context, req resource.CreateReq
 		resp.Diagnostics.AddError("Path resolution error", err.Error())
 		return
 	}
+	defer fileio.Lock(path)()
 
 	merged, resolvedPath, err := r.mergeIntoExisting(ctx, &resp.Diagnostics, plan, path)
-	if err != nil || resp.Diagnostics.HasError() {
+	if err != nil && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddError("Failed to render config.toml", err.Error())
+	}
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -271,7 +476,7 @@ func (r *codexConfigResource) Create(ctx context.Context, req resource.CreateReq
 	if strings.EqualFold(plan.MergeStrategy.ValueString(), "replace_all") {
 		header = "# Managed by terraform-provider-agentsmith: agentsmith_codex_config"
 	}
-	if err := configio.AtomicWrite(resolvedPath, merged, strOrDefault(plan.FileMode, "0600"), boolOrDefault(plan.BackupOnWrite, true), boolOrDefault(plan.CreateDirectories, true), header); err != nil {
+	if err := configio.AtomicWrite(resolvedPath, merged, plan.FileMode.ValueString(), boolOrDefault(plan.BackupOnWrite, true), boolOrDefault(plan.CreateDirectories, true), header); err != nil {
 		resp.Diagnostics.AddError("Failed to write config.toml", err.Error())
 		return
 	}
@@ -308,7 +513,31 @@ func (r *codexConfigResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	// Keep meta; we deliberately avoid full content round-trip in MVP
+	existing, err := configio.ReadTOMLMap(path)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read config.toml", err.Error())
+		return
+	}
+
+	// A freshly imported resource, or one that replaces the whole file, owns every known key that
+	// it finds, or those recorded in the marker table.
+	imported, d := req.Private.GetKey(ctx, codexConfigImportedKey)
+	resp.Diagnostics.Append(d...)
+	ownAll := len(imported) > 0 || strings.EqualFold(state.MergeStrategy.ValueString(), "replace_all")
+	scoped := existing
+	if len(imported) > 0 {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, codexConfigImportedKey, nil)...)
+		if owned, ok := configio.Owned(existing, codexConfigOwner); ok {
+			scoped = owned
+		}
+	}
+	if err := r.refreshFromFile(ctx, &resp.Diagnostics, &state, scoped, ownAll); err != nil {
+		resp.Diagnostics.AddError("Failed to parse config.toml", err.Error())
+		return
+	}
+	if !state.ExtraSettings.IsNull() {
+		state.ExtraSettings = refreshExtraSettings(state.ExtraSettings, existing)
+	}
 	state.ResolvedPath = types.StringValue(path)
 	state.ID = types.StringValue(configio.SHA256Hex(path))
 	diags = resp.State.Set(ctx, state)
@@ -328,9 +557,13 @@ func (r *codexConfigResource) Update(ctx context.Context, req resource.UpdateReq
 		resp.Diagnostics.AddError("Path resolution error", err.Error())
 		return
 	}
+	defer fileio.Lock(path)()
 
 	merged, resolvedPath, err := r.mergeIntoExisting(ctx, &resp.Diagnostics, plan, path)
-	if err != nil || resp.Diagnostics.HasError() {
+	if err != nil && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddError("Failed to render config.toml", err.Error())
+	}
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -338,7 +571,7 @@ func (r *codexConfigResource) Update(ctx context.Context, req resource.UpdateReq
 	if strings.EqualFold(plan.MergeStrategy.ValueString(), "replace_all") {
 		header = "# Managed by terraform-provider-agentsmith: agentsmith_codex_config"
 	}
-	if err := configio.AtomicWrite(resolvedPath, merged, strOrDefault(plan.FileMode, "0600"), boolOrDefault(plan.BackupOnWrite, true), boolOrDefault(plan.CreateDirectories, true), header); err != nil {
+	if err := configio.AtomicWrite(resolvedPath, merged, plan.FileMode.ValueString(), boolOrDefault(plan.BackupOnWrite, true), boolOrDefault(plan.CreateDirectories, true), header); err != nil {
 		resp.Diagnostics.AddError("Failed to write config.toml", err.Error())
 		return
 	}
@@ -357,11 +590,6 @@ func (r *codexConfigResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	keep := boolOrDefault(state.KeepFileOnDestroy, true)
-	if keep {
-		// For MVP, we leave the file intact. Future: strip managed keys when preserve_unknown.
-		return
-	}
 	path, err := r.resolveTargetPath(state)
 	if err != nil {
 		resp.Diagnostics.AddError("Path resolution error", err.Error())
@@ -370,11 +598,43 @@ func (r *codexConfigResource) Delete(ctx context.Context, req resource.DeleteReq
 	if path == "" {
 		return
 	}
+	if boolOrDefault(state.KeepFileOnDestroy, true) {
+		if err := r.removeOwnedKeys(state, path); err != nil {
+			resp.Diagnostics.AddError("Failed to update config.toml", err.Error())
+		}
+		return
+	}
 	if err := osRemove(path); err != nil && !os.IsNotExist(err) {
 		resp.Diagnostics.AddError("Failed to delete file", err.Error())
 	}
 }
 
+// removeOwnedKeys removes the keys recorded for this resource from the file at filePath,
+// editing it in place so that the rest of the file is left as it was.
+func (r *codexConfigResource) removeOwnedKeys(state codexConfigResourceModel, filePath string) error {
+	defer fileio.Lock(filePath)()
+
+	original, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	existing, err := configio.ReadTOMLMap(filePath)
+	if err != nil {
+		return err
+	}
+	if _, ok := configio.Owned(existing, codexConfigOwner); !ok {
+		return nil
+	}
+	b, err := configio.EditTOML(original, configio.RemoveOwned(existing, codexConfigOwner))
+	if err != nil {
+		return err
+	}
+	return configio.AtomicWrite(filePath, b, state.FileMode.ValueString(), boolOrDefault(state.BackupOnWrite, true), boolOrDefault(state.CreateDirectories, true), "")
+}
+
 func (r *codexConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Import by absolute path
 	p := strings.TrimSpace(req.ID)
@@ -386,6 +646,7 @@ func (r *codexConfigResource) ImportState(ctx context.Context, req resource.Impo
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("scope"), "custom")...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resolved_path"), p)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), configio.SHA256Hex(p))...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, codexConfigImportedKey, []byte("true"))...)
 }
 
 func (r *codexConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -403,10 +664,15 @@ func (r *codexConfigResource) Configure(_ context.Context, req resource.Configur
 // mergeIntoExisting performs merge according to merge_strategy and returns encoded TOML bytes.
 func (r *codexConfigResource) mergeIntoExisting(ctx context.Context, diags *diag.Diagnostics, plan codexConfigResourceModel, resolvedPath string) ([]byte, string, error) {
 	// Prepare desired map
-	desired, derr := r.planToMap(ctx, diags, &plan)
+	typed, derr := r.planToMap(ctx, diags, &plan)
 	if derr != nil {
 		return nil, resolvedPath, derr
 	}
+	desired, derr := mergeExtraSettings(typed, plan.ExtraSettings)
+	if derr != nil {
+		diags.AddError("Invalid extra_settings", derr.Error())
+		return nil, resolvedPath, derr
+	}
 
 	// Read existing
 	existing, err := configio.ReadTOMLMap(resolvedPath)
@@ -421,10 +687,17 @@ func (r *codexConfigResource) mergeIntoExisting(ctx context.Context, diags *diag
 	}
 
 	if strings.EqualFold(strategy, "fail_on_unknown") {
-		unk := configio.ValidateUnknownKeys(existing)
+		var unk []string
+		for _, k := range configio.ValidateUnknownKeys(existing) {
+			if _, managed := desired[k]; !managed {
+				unk = append(unk, k)
+			}
+		}
 		if len(unk) > 0 {
 			sort.Strings(unk)
-			return nil, resolvedPath, fmt.Errorf("fail_on_unknown: file contains unknown keys: %s", strings.Join(unk, ", "))
+			err := fmt.Errorf("fail_on_unknown: file contains unknown keys: %s", strings.Join(unk, ", "))
+			diags.AddError("Unknown keys in config.toml", err.Error())
+			return nil, resolvedPath, err
 		}
 	}
 
@@ -433,9 +706,9 @@ func (r *codexConfigResource) mergeIntoExisting(ctx context.Context, diags *diag
 	case "replace_all":
 		merged = desired
 	case "preserve_unknown", "fail_on_unknown":
-		merged = configio.MergePreserveUnknown(existing, desired)
+		merged = configio.MergePreserveUnknown(existing, desired, codexConfigOwner, stringsFromList(plan.OwnedTables)...)
 	default:
-		merged = configio.MergePreserveUnknown(existing, desired)
+		merged = configio.MergePreserveUnknown(existing, desired, codexConfigOwner, stringsFromList(plan.OwnedTables)...)
 	}
 
 	// Strict validation: try marshal/unmarshal via typed struct to catch shape errors
@@ -443,15 +716,41 @@ func (r *codexConfigResource) mergeIntoExisting(ctx context.Context, diags *diag
 		var typed rawCodexConfig
 		b, err := configio.MarshalTOML(merged)
 		if err != nil {
+			diags.AddError("Invalid Codex config", err.Error())
 			return nil, resolvedPath, err
 		}
 		if err := toml.Unmarshal(b, &typed); err != nil {
-			return nil, resolvedPath, fmt.Errorf("strict validation failed: %w", err)
+			err = fmt.Errorf("strict validation failed: %w", err)
+			diags.AddError("Invalid Codex config", err.Error())
+			return nil, resolvedPath, err
+		}
+		for _, issue := range configio.Validate(merged) {
+			if _, ok := configio.LookupKeyPath(desired, issue.Path); ok && !issue.Reference {
+				err := fmt.Errorf("strict validation failed: %s", issue)
+				diags.AddError("Invalid Codex config", err.Error())
+				return nil, resolvedPath, err
+			}
 		}
-		return b, resolvedPath, nil
 	}
 
-	b, err := configio.MarshalTOML(merged)
+	if strategy == "replace_all" {
+		b, err := configio.MarshalTOML(merged)
+		if err != nil {
+			diags.AddError("Invalid Codex config", err.Error())
+		}
+		return b, resolvedPath, err
+	}
+
+	// Edit the existing file in place so that the user's comments and layout survive
+	original, err := os.ReadFile(resolvedPath)
+	if err != nil && !os.IsNotExist(err) {
+		diags.AddError("Failed to read existing file", err.Error())
+		return nil, resolvedPath, err
+	}
+	b, err := configio.EditTOML(original, merged)
+	if err != nil {
+		diags.AddError("Failed to edit config.toml", err.Error())
+	}
 	return b, resolvedPath, err
 }
 
@@ -522,31 +821,8 @@ func (r *codexConfigResource) planToMap(ctx context.Context, diags *diag.Diagnos
 		out["tui"] = mp
 	}
 
-	if m.SandboxWorkspaceWrite != nil {
-		sw := map[string]any{}
-		if m.SandboxWorkspaceWrite.WritableRoots != nil {
-			var arr []string
-			for _, s := range m.SandboxWorkspaceWrite.WritableRoots {
-				if !s.IsNull() && !s.IsUnknown() {
-					arr = append(arr, s.ValueString())
-				}
-			}
-			if len(arr) > 0 {
-				sw["writable_roots"] = arr
-			}
-		}
-		if !m.SandboxWorkspaceWrite.NetworkAccess.IsNull() && !m.SandboxWorkspaceWrite.NetworkAccess.IsUnknown() {
-			sw["network_access"] = m.SandboxWorkspaceWrite.NetworkAccess.ValueBool()
-		}
-		if !m.SandboxWorkspaceWrite.ExcludeTmpdirEnvVar.IsNull() && !m.SandboxWorkspaceWrite.ExcludeTmpdirEnvVar.IsUnknown() {
-			sw["exclude_tmpdir_env_var"] = m.SandboxWorkspaceWrite.ExcludeTmpdirEnvVar.ValueBool()
-		}
-		if !m.SandboxWorkspaceWrite.ExcludeSlashTmp.IsNull() && !m.SandboxWorkspaceWrite.ExcludeSlashTmp.IsUnknown() {
-			sw["exclude_slash_tmp"] = m.SandboxWorkspaceWrite.ExcludeSlashTmp.ValueBool()
-		}
-		if len(sw) > 0 {
-			out["sandbox_workspace_write"] = sw
-		}
+	if sw := codexSandboxWorkspaceWriteToMap(m.SandboxWorkspaceWrite); len(sw) > 0 {
+		out["sandbox_workspace_write"] = sw
 	}
 
 	if m.History != nil {
@@ -562,48 +838,12 @@ func (r *codexConfigResource) planToMap(ctx context.Context, diags *diag.Diagnos
 		}
 	}
 
-	if m.ShellEnvPolicy != nil {
-		sp := map[string]any{}
-		if !m.ShellEnvPolicy.Inherit.IsNull() && !m.ShellEnvPolicy.Inherit.IsUnknown() {
-			sp["inherit"] = m.ShellEnvPolicy.Inherit.ValueString()
-		}
-		if !m.ShellEnvPolicy.IgnoreDefaultExcludes.IsNull() && !m.ShellEnvPolicy.IgnoreDefaultExcludes.IsUnknown() {
-			sp["ignore_default_excludes"] = m.ShellEnvPolicy.IgnoreDefaultExcludes.ValueBool()
-		}
-		if m.ShellEnvPolicy.Exclude != nil {
-			var arr []string
-			for _, s := range m.ShellEnvPolicy.Exclude {
-				if !s.IsNull() && !s.IsUnknown() {
-					arr = append(arr, s.ValueString())
-				}
-			}
-			if len(arr) > 0 {
-				sp["exclude"] = arr
-			}
-		}
-		if !m.ShellEnvPolicy.Set.IsNull() && !m.ShellEnvPolicy.Set.IsUnknown() {
-			var sm map[string]string
-			d := m.ShellEnvPolicy.Set.ElementsAs(ctx, &sm, false)
-			if d.HasError() {
-				diags.Append(d...)
-				return nil, fmt.Errorf("invalid shell_environment_policy.set")
-			}
-			sp["set"] = sm
-		}
-		if m.ShellEnvPolicy.IncludeOnly != nil {
-			var arr []string
-			for _, s := range m.ShellEnvPolicy.IncludeOnly {
-				if !s.IsNull() && !s.IsUnknown() {
-					arr = append(arr, s.ValueString())
-				}
-			}
-			if len(arr) > 0 {
-				sp["include_only"] = arr
-			}
-		}
-		if len(sp) > 0 {
-			out["shell_environment_policy"] = sp
-		}
+	sp, err := codexShellEnvPolicyToMap(ctx, diags, m.ShellEnvPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if len(sp) > 0 {
+		out["shell_environment_policy"] = sp
 	}
 
 	if len(m.ModelProviders) > 0 {
@@ -612,57 +852,11 @@ func (r *codexConfigResource) planToMap(ctx context.Context, diags *diag.Diagnos
 			if mp.ID.IsNull() || mp.ID.IsUnknown() || mp.ID.ValueString() == "" {
 				continue
 			}
-			id := mp.ID.ValueString()
-			row := map[string]any{}
-			if !mp.Name.IsNull() && !mp.Name.IsUnknown() {
-				row["name"] = mp.Name.ValueString()
-			}
-			if !mp.BaseURL.IsNull() && !mp.BaseURL.IsUnknown() {
-				row["base_url"] = mp.BaseURL.ValueString()
+			row, err := codexModelProviderToMap(ctx, diags, mp)
+			if err != nil {
+				return nil, err
 			}
-			if !mp.EnvKey.IsNull() && !mp.EnvKey.IsUnknown() {
-				row["env_key"] = mp.EnvKey.ValueString()
-			}
-			if !mp.WireAPI.IsNull() && !mp.WireAPI.IsUnknown() {
-				row["wire_api"] = mp.WireAPI.ValueString()
-			}
-			if !mp.QueryParams.IsNull() && !mp.QueryParams.IsUnknown() {
-				var mm map[string]string
-				d := mp.QueryParams.ElementsAs(ctx, &mm, false)
-				if d.HasError() {
-					diags.Append(d...)
-					return nil, fmt.Errorf("invalid model_providers.query_params")
-				}
-				row["query_params"] = mm
-			}
-			if !mp.HTTPHeaders.IsNull() && !mp.HTTPHeaders.IsUnknown() {
-				var mm map[string]string
-				d := mp.HTTPHeaders.ElementsAs(ctx, &mm, false)
-				if d.HasError() {
-					diags.Append(d...)
-					return nil, fmt.Errorf("invalid model_providers.http_headers")
-				}
-				row["http_headers"] = mm
-			}
-			if !mp.EnvHTTPHeaders.IsNull() && !mp.EnvHTTPHeaders.IsUnknown() {
-				var mm map[string]string
-				d := mp.EnvHTTPHeaders.ElementsAs(ctx, &mm, false)
-				if d.HasError() {
-					diags.Append(d...)
-					return nil, fmt.Errorf("invalid model_providers.env_http_headers")
-				}
-				row["env_http_headers"] = mm
-			}
-			if !mp.RequestMaxRetries.IsNull() && !mp.RequestMaxRetries.IsUnknown() {
-				row["request_max_retries"] = mp.RequestMaxRetries.ValueInt64()
-			}
-			if !mp.StreamMaxRetries.IsNull() && !mp.StreamMaxRetries.IsUnknown() {
-				row["stream_max_retries"] = mp.StreamMaxRetries.ValueInt64()
-			}
-			if !mp.StreamIdleTimeoutMS.IsNull() && !mp.StreamIdleTimeoutMS.IsUnknown() {
-				row["stream_idle_timeout_ms"] = mp.StreamIdleTimeoutMS.ValueInt64()
-			}
-			t[id] = row
+			t[mp.ID.ValueString()] = row
 		}
 		if len(t) > 0 {
 			out["model_providers"] = t
@@ -676,30 +870,9 @@ func (r *codexConfigResource) planToMap(ctx context.Context, diags *diag.Diagnos
 				continue
 			}
 			id := s.ID.ValueString()
-			row := map[string]any{}
-			if !s.Command.IsNull() && !s.Command.IsUnknown() {
-				row["command"] = s.Command.ValueString()
-			}
-			if s.Args != nil {
-				var arr []string
-				for _, a := range s.Args {
-					if !a.IsNull() && !a.IsUnknown() {
-						arr = append(arr, a.ValueString())
-					}
-				}
-				if len(arr) > 0 {
-					row["args"] = arr
-				}
-			}
-			// Only persist env when allowed
-			if boolOrDefault(m.AllowSensitiveEnvWrites, false) && !s.Env.IsNull() && !s.Env.IsUnknown() {
-				var mm map[string]string
-				d := s.Env.ElementsAs(ctx, &mm, false)
-				if d.HasError() {
-					diags.Append(d...)
-					return nil, fmt.Errorf("invalid mcp_servers.env")
-				}
-				row["env"] = mm
+			row, err := codexMCPServerToMap(ctx, diags, s, boolOrDefault(m.AllowSensitiveEnvWrites, false))
+			if err != nil {
+				return nil, err
 			}
 			t[id] = row
 		}
@@ -714,21 +887,11 @@ func (r *codexConfigResource) planToMap(ctx context.Context, diags *diag.Diagnos
 			if p.Name.IsNull() || p.Name.IsUnknown() || p.Name.ValueString() == "" {
 				continue
 			}
-			name := p.Name.ValueString()
-			row := map[string]any{}
-			if !p.Model.IsNull() && !p.Model.IsUnknown() {
-				row["model"] = p.Model.ValueString()
+			row, err := codexProfileToMap(ctx, diags, p)
+			if err != nil {
+				return nil, err
 			}
-			if !p.ModelProvider.IsNull() && !p.ModelProvider.IsUnknown() {
-				row["model_provider"] = p.ModelProvider.ValueString()
-			}
-			if !p.ApprovalPolicy.IsNull() && !p.ApprovalPolicy.IsUnknown() {
-				row["approval_policy"] = p.ApprovalPolicy.ValueString()
-			}
-			if !p.SandboxMode.IsNull() && !p.SandboxMode.IsUnknown() {
-				row["sandbox_mode"] = p.SandboxMode.ValueString()
-			}
-			t[name] = row
+			t[p.Name.ValueString()] = row
 		}
 		if len(t) > 0 {
 			out["profiles"] = t
@@ -737,13 +900,625 @@ func (r *codexConfigResource) planToMap(ctx context.Context, diags *diag.Diagnos
 	return out, nil
 }
 
-// Small helpers for testability and value defaults
-func strOrDefault(v types.String, def string) string {
-	if !v.IsNull() && !v.IsUnknown() && v.ValueString() != "" {
-		return v.ValueString()
+// codexModelProviderToMap renders a model provider entry, without its id.
+func codexModelProviderToMap(ctx context.Context, diags *diag.Diagnostics, mp codexModelProviderWriteModel) (map[string]any, error) {
+	row := map[string]any{}
+	if !mp.Name.IsNull() && !mp.Name.IsUnknown() {
+		row["name"] = mp.Name.ValueString()
 	}
-	return def
+	if !mp.BaseURL.IsNull() && !mp.BaseURL.IsUnknown() {
+		row["base_url"] = mp.BaseURL.ValueString()
+	}
+	if !mp.EnvKey.IsNull() && !mp.EnvKey.IsUnknown() {
+		row["env_key"] = mp.EnvKey.ValueString()
+	}
+	if !mp.WireAPI.IsNull() && !mp.WireAPI.IsUnknown() {
+		row["wire_api"] = mp.WireAPI.ValueString()
+	}
+	if !mp.QueryParams.IsNull() && !mp.QueryParams.IsUnknown() {
+		var mm map[string]string
+		d := mp.QueryParams.ElementsAs(ctx, &mm, false)
+		if d.HasError() {
+			diags.Append(d...)
+			return nil, fmt.Errorf("invalid model_providers.query_params")
+		}
+		row["query_params"] = mm
+	}
+	if !mp.HTTPHeaders.IsNull() && !mp.HTTPHeaders.IsUnknown() {
+		var mm map[string]string
+		d := mp.HTTPHeaders.ElementsAs(ctx, &mm, false)
+		if d.HasError() {
+			diags.Append(d...)
+			return nil, fmt.Errorf("invalid model_providers.http_headers")
+		}
+		row["http_headers"] = mm
+	}
+	if !mp.EnvHTTPHeaders.IsNull() && !mp.EnvHTTPHeaders.IsUnknown() {
+		var mm map[string]string
+		d := mp.EnvHTTPHeaders.ElementsAs(ctx, &mm, false)
+		if d.HasError() {
+			diags.Append(d...)
+			return nil, fmt.Errorf("invalid model_providers.env_http_headers")
+		}
+		row["env_http_headers"] = mm
+	}
+	if !mp.RequestMaxRetries.IsNull() && !mp.RequestMaxRetries.IsUnknown() {
+		row["request_max_retries"] = mp.RequestMaxRetries.ValueInt64()
+	}
+	if !mp.StreamMaxRetries.IsNull() && !mp.StreamMaxRetries.IsUnknown() {
+		row["stream_max_retries"] = mp.StreamMaxRetries.ValueInt64()
+	}
+	if !mp.StreamIdleTimeoutMS.IsNull() && !mp.StreamIdleTimeoutMS.IsUnknown() {
+		row["stream_idle_timeout_ms"] = mp.StreamIdleTimeoutMS.ValueInt64()
+	}
+	return row, nil
+}
+
+// codexModelProviderFromConfig is the inverse of codexModelProviderToMap.
+func codexModelProviderFromConfig(id string, mp modelProviderConfig) codexModelProviderWriteModel {
+	return codexModelProviderWriteModel{
+		ID:                  types.StringValue(id),
+		Name:                stringValueOrNull(mp.Name),
+		BaseURL:             stringValueOrNull(mp.BaseURL),
+		EnvKey:              stringValueOrNull(mp.EnvKey),
+		WireAPI:             stringValueOrNull(mp.WireAPI),
+		QueryParams:         mapStringValueOrNull(mp.QueryParams),
+		HTTPHeaders:         mapStringValueOrNull(mp.HTTPHeaders),
+		EnvHTTPHeaders:      mapStringValueOrNull(mp.EnvHTTPHeaders),
+		RequestMaxRetries:   int64PtrValue(mp.RequestMaxRetries),
+		StreamMaxRetries:    int64PtrValue(mp.StreamMaxRetries),
+		StreamIdleTimeoutMS: int64PtrValue(mp.StreamIdleTimeoutMS),
+	}
+}
+
+// codexProfileToMap renders a profile entry, without its name.
+func codexProfileToMap(ctx context.Context, diags *diag.Diagnostics, p codexProfileWriteModel) (map[string]any, error) {
+	row := map[string]any{}
+	setString := func(key string, v types.String) {
+		if !v.IsNull() && !v.IsUnknown() && v.ValueString() != "" {
+			row[key] = v.ValueString()
+		}
+	}
+	setInt := func(key string, v types.Int64) {
+		if !v.IsNull() && !v.IsUnknown() {
+			row[key] = v.ValueInt64()
+		}
+	}
+	setBool := func(key string, v types.Bool) {
+		if !v.IsNull() && !v.IsUnknown() {
+			row[key] = v.ValueBool()
+		}
+	}
+
+	setString("model", p.Model)
+	setString("model_provider", p.ModelProvider)
+	setInt("model_context_window", p.ModelContextWindow)
+	setInt("model_max_output_tokens", p.ModelMaxOutputTokens)
+	setString("approval_policy", p.ApprovalPolicy)
+	setString("sandbox_mode", p.SandboxMode)
+	setString("file_opener", p.FileOpener)
+	setBool("hide_agent_reasoning", p.HideAgentReasoning)
+	setBool("show_raw_agent_reasoning", p.ShowRawAgentReasoning)
+	setString("model_reasoning_effort", p.ModelReasoningEffort)
+	setString("model_reasoning_summary", p.ModelReasoningSummary)
+	setString("model_verbosity", p.ModelVerbosity)
+	setBool("model_supports_reasoning_summaries", p.ModelSupportsReasoningSummaries)
+	setInt("project_doc_max_bytes", p.ProjectDocMaxBytes)
+
+	if sw := codexSandboxWorkspaceWriteToMap(p.SandboxWorkspaceWrite); len(sw) > 0 {
+		row["sandbox_workspace_write"] = sw
+	}
+	sp, err := codexShellEnvPolicyToMap(ctx, diags, p.ShellEnvPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if len(sp) > 0 {
+		row["shell_environment_policy"] = sp
+	}
+	return row, nil
+}
+
+// codexProfileFromConfig is the inverse of codexProfileToMap.
+func codexProfileFromConfig(name string, p rawCodexConfig) codexProfileWriteModel {
+	return codexProfileWriteModel{
+		Name:                            types.StringValue(name),
+		Model:                           stringValueOrNull(p.Model),
+		ModelProvider:                   stringValueOrNull(p.ModelProvider),
+		ModelContextWindow:              int64PtrValue(p.ModelContextWindow),
+		ModelMaxOutputTokens:            int64PtrValue(p.ModelMaxOutputTokens),
+		ApprovalPolicy:                  stringValueOrNull(p.ApprovalPolicy),
+		SandboxMode:                     stringValueOrNull(p.SandboxMode),
+		FileOpener:                      stringValueOrNull(p.FileOpener),
+		HideAgentReasoning:              boolPtrValue(p.HideAgentReasoning),
+		ShowRawAgentReasoning:           boolPtrValue(p.ShowRawAgentReasoning),
+		ModelReasoningEffort:            stringValueOrNull(p.ModelReasoningEffort),
+		ModelReasoningSummary:           stringValueOrNull(p.ModelReasoningSummary),
+		ModelVerbosity:                  stringValueOrNull(p.ModelVerbosity),
+		ModelSupportsReasoningSummaries: boolPtrValue(p.ModelSupportsReasoningSummaries),
+		ProjectDocMaxBytes:              int64PtrValue(p.ProjectDocMaxBytes),
+		SandboxWorkspaceWrite:           codexSandboxWorkspaceWriteFromConfig(p.SandboxWorkspaceWrite),
+		ShellEnvPolicy:                  codexShellEnvPolicyFromConfig(p.ShellEnvironmentPolicy),
+	}
+}
+
+func codexSandboxWorkspaceWriteToMap(m *codexSandboxWorkspaceWriteModelR) map[string]any {
+	if m == nil {
+		return nil
+	}
+	sw := map[string]any{}
+	if arr := stringsFromList(m.WritableRoots); len(arr) > 0 {
+		sw["writable_roots"] = arr
+	}
+	if !m.NetworkAccess.IsNull() && !m.NetworkAccess.IsUnknown() {
+		sw["network_access"] = m.NetworkAccess.ValueBool()
+	}
+	if !m.ExcludeTmpdirEnvVar.IsNull() && !m.ExcludeTmpdirEnvVar.IsUnknown() {
+		sw["exclude_tmpdir_env_var"] = m.ExcludeTmpdirEnvVar.ValueBool()
+	}
+	if !m.ExcludeSlashTmp.IsNull() && !m.ExcludeSlashTmp.IsUnknown() {
+		sw["exclude_slash_tmp"] = m.ExcludeSlashTmp.ValueBool()
+	}
+	return sw
+}
+
+func codexSandboxWorkspaceWriteFromConfig(sw *sandboxWorkspaceWrite) *codexSandboxWorkspaceWriteModelR {
+	if sw == nil {
+		return nil
+	}
+	return &codexSandboxWorkspaceWriteModelR{
+		WritableRoots:       toTFStringListOrNil(sw.WritableRoots),
+		NetworkAccess:       boolPtrValue(sw.NetworkAccess),
+		ExcludeTmpdirEnvVar: boolPtrValue(sw.ExcludeTmpdirEnvVar),
+		ExcludeSlashTmp:     boolPtrValue(sw.ExcludeSlashTmp),
+	}
+}
+
+func codexShellEnvPolicyToMap(ctx context.Context, diags *diag.Diagnostics, m *codexShellEnvPolicyModelR) (map[string]any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	sp := map[string]any{}
+	if !m.Inherit.IsNull() && !m.Inherit.IsUnknown() {
+		sp["inherit"] = m.Inherit.ValueString()
+	}
+	if !m.IgnoreDefaultExcludes.IsNull() && !m.IgnoreDefaultExcludes.IsUnknown() {
+		sp["ignore_default_excludes"] = m.IgnoreDefaultExcludes.ValueBool()
+	}
+	if arr := stringsFromList(m.Exclude); len(arr) > 0 {
+		sp["exclude"] = arr
+	}
+	if !m.Set.IsNull() && !m.Set.IsUnknown() {
+		var sm map[string]string
+		d := m.Set.ElementsAs(ctx, &sm, false)
+		if d.HasError() {
+			diags.Append(d...)
+			return nil, fmt.Errorf("invalid shell_environment_policy.set")
+		}
+		sp["set"] = sm
+	}
+	if arr := stringsFromList(m.IncludeOnly); len(arr) > 0 {
+		sp["include_only"] = arr
+	}
+	return sp, nil
+}
+
+func codexShellEnvPolicyFromConfig(sp *shellEnvPolicy) *codexShellEnvPolicyModelR {
+	if sp == nil {
+		return nil
+	}
+	return &codexShellEnvPolicyModelR{
+		Inherit:               stringValueOrNull(sp.Inherit),
+		IgnoreDefaultExcludes: boolPtrValue(sp.IgnoreDefaultExcludes),
+		Exclude:               toTFStringListOrNil(sp.Exclude),
+		Set:                   mapStringValueOrNull(sp.Set),
+		IncludeOnly:           toTFStringListOrNil(sp.IncludeOnly),
+	}
+}
+
+// codexMCPServerToMap renders an MCP server entry. env is only included when writeEnv is set.
+func codexMCPServerToMap(ctx context.Context, diags *diag.Diagnostics, s codexMCPServerWriteModel, writeEnv bool) (map[string]any, error) {
+	row := map[string]any{}
+	setString := func(key string, v types.String) {
+		if !v.IsNull() && !v.IsUnknown() && v.ValueString() != "" {
+			row[key] = v.ValueString()
+		}
+	}
+	setList := func(key string, v []types.String) {
+		if items := stringsFromList(v); len(items) > 0 {
+			row[key] = items
+		}
+	}
+	setMap := func(key string, v types.Map) error {
+		if v.IsNull() || v.IsUnknown() {
+			return nil
+		}
+		var mm map[string]string
+		if d := v.ElementsAs(ctx, &mm, false); d.HasError() {
+			diags.Append(d...)
+			return fmt.Errorf("invalid mcp_servers.%s", key)
+		}
+		if len(mm) > 0 {
+			row[key] = mm
+		}
+		return nil
+	}
+
+	setString("command", s.Command)
+	setList("args", s.Args)
+	// Only persist env when allowed
+	if writeEnv {
+		if err := setMap("env", s.Env); err != nil {
+			return nil, err
+		}
+	}
+	setString("cwd", s.Cwd)
+	setString("url", s.URL)
+	setString("bearer_token_env_var", s.BearerTokenEnvVar)
+	if err := setMap("http_headers", s.HTTPHeaders); err != nil {
+		return nil, err
+	}
+	if err := setMap("env_http_headers", s.EnvHTTPHeaders); err != nil {
+		return nil, err
+	}
+	if !s.StartupTimeoutSec.IsNull() && !s.StartupTimeoutSec.IsUnknown() {
+		row["startup_timeout_sec"] = s.StartupTimeoutSec.ValueFloat64()
+	}
+	if !s.ToolTimeoutSec.IsNull() && !s.ToolTimeoutSec.IsUnknown() {
+		row["tool_timeout_sec"] = s.ToolTimeoutSec.ValueFloat64()
+	}
+	if !s.Enabled.IsNull() && !s.Enabled.IsUnknown() {
+		row["enabled"] = s.Enabled.ValueBool()
+	}
+	setList("enabled_tools", s.EnabledTools)
+	setList("disabled_tools", s.DisabledTools)
+	return row, nil
+}
+
+// codexMCPServerFromConfig builds an MCP server entry from the file. env is taken from prior
+// unless writeEnv is set, as it is not written otherwise.
+func codexMCPServerFromConfig(id string, s mcpServerConfig, prior types.Map, writeEnv bool) codexMCPServerWriteModel {
+	env := prior
+	if writeEnv {
+		env = mapStringValueOrNull(s.Env)
+	}
+	return codexMCPServerWriteModel{
+		ID:                types.StringValue(id),
+		Command:           stringValueOrNull(s.Command),
+		Args:              toTFStringListOrNil(s.Args),
+		Env:               env,
+		Cwd:               stringValueOrNull(s.Cwd),
+		URL:               stringValueOrNull(s.URL),
+		BearerTokenEnvVar: stringValueOrNull(s.BearerTokenEnvVar),
+		HTTPHeaders:       mapStringValueOrNull(s.HTTPHeaders),
+		EnvHTTPHeaders:    mapStringValueOrNull(s.EnvHTTPHeaders),
+		StartupTimeoutSec: float64PtrValue(s.StartupTimeoutSec),
+		ToolTimeoutSec:    float64PtrValue(s.ToolTimeoutSec),
+		Enabled:           boolPtrValue(s.Enabled),
+		EnabledTools:      toTFStringListOrNil(s.EnabledTools),
+		DisabledTools:     toTFStringListOrNil(s.DisabledTools),
+	}
+}
+
+// validateCodexMCPServer checks that a server uses exactly one transport, and none of the fields
+// of the other.
+func validateCodexMCPServer(diags *diag.Diagnostics, p path.Path, s codexMCPServerWriteModel) {
+	if s.Command.IsUnknown() || s.URL.IsUnknown() {
+		return
+	}
+	stdio, http := !s.Command.IsNull(), !s.URL.IsNull()
+	if stdio == http {
+		diags.AddAttributeError(p, "Invalid MCP server", "Exactly one of `command` (stdio) and `url` (streamable HTTP) must be set.")
+		return
+	}
+
+	mixed := func(attr, transport string) {
+		diags.AddAttributeError(p.AtName(attr), "Invalid MCP server", fmt.Sprintf("`%s` only applies to %s servers.", attr, transport))
+	}
+	if http {
+		if s.Args != nil {
+			mixed("args", "stdio")
+		}
+		if !s.Env.IsNull() {
+			mixed("env", "stdio")
+		}
+		if !s.Cwd.IsNull() {
+			mixed("cwd", "stdio")
+		}
+	} else {
+		if !s.BearerTokenEnvVar.IsNull() {
+			mixed("bearer_token_env_var", "HTTP")
+		}
+		if !s.HTTPHeaders.IsNull() {
+			mixed("http_headers", "HTTP")
+		}
+		if !s.EnvHTTPHeaders.IsNull() {
+			mixed("env_http_headers", "HTTP")
+		}
+	}
+}
+
+// validateCodexUniqueIDs reports list block entries whose key repeats an earlier entry, since
+// both would be written to the same table of config.toml.
+func validateCodexUniqueIDs[M any](diags *diag.Diagnostics, block, attr string, items []M, key func(M) types.String) {
+	seen := make(map[string]int, len(items))
+	for i, item := range items {
+		k := key(item)
+		if k.IsNull() || k.IsUnknown() {
+			continue
+		}
+		if first, ok := seen[k.ValueString()]; ok {
+			diags.AddAttributeError(
+				path.Root(block).AtListIndex(i).AtName(attr),
+				fmt.Sprintf("Duplicate %s %s", block, attr),
+				fmt.Sprintf("%q is already used by %s.%d.", k.ValueString(), block, first),
+			)
+			continue
+		}
+		seen[k.ValueString()] = i
+	}
+}
+
+// codexMapAttributes are the map attributes of config.toml tables, whose keys are map keys
+// rather than attribute names.
+var codexMapAttributes = []string{"env", "env_http_headers", "http_headers", "query_params", "set"}
+
+// codexAttributePath returns the path of the attribute of m that sets the key path key.
+func codexAttributePath(m *codexConfigResourceModel, key []string) path.Path {
+	p := path.Root(key[0])
+	rest := key[1:]
+	switch key[0] {
+	case "mcp_servers", "model_providers", "profiles":
+		if len(rest) == 0 {
+			return p
+		}
+		index := -1
+		switch key[0] {
+		case "mcp_servers":
+			index = slices.IndexFunc(m.MCPServers, func(s codexMCPServerWriteModel) bool { return s.ID.ValueString() == rest[0] })
+		case "model_providers":
+			index = slices.IndexFunc(m.ModelProviders, func(mp codexModelProviderWriteModel) bool { return mp.ID.ValueString() == rest[0] })
+		case "profiles":
+			index = slices.IndexFunc(m.Profiles, func(pr codexProfileWriteModel) bool { return pr.Name.ValueString() == rest[0] })
+		}
+		if index < 0 {
+			return p
+		}
+		p, rest = p.AtListIndex(index), rest[1:]
+	case "tui":
+		if len(rest) > 0 {
+			return p.AtMapKey(rest[0])
+		}
+	}
+	return codexNestedAttributePath(p, rest)
+}
+
+// codexNestedAttributePath returns the path below p of the attribute that sets the key path key
+// relative to the table at p.
+func codexNestedAttributePath(p path.Path, key []string) path.Path {
+	for i, k := range key {
+		p = p.AtName(k)
+		if slices.Contains(codexMapAttributes, k) && i+1 < len(key) {
+			return p.AtMapKey(key[i+1])
+		}
+	}
+	return p
+}
+
+// refreshFromFile updates the config attributes of state from the file contents in existing.
+// A top-level key is refreshed if state renders it, or whenever it is in the file when ownAll is
+// set. Within tables that merge with the file, only the entries and keys that state renders are
+// refreshed, as the rest is left alone on write. MCP server env values are only read back when
+// they are written, so that secrets do not land in state.
+func (r *codexConfigResource) refreshFromFile(ctx context.Context, diags *diag.Diagnostics, state *codexConfigResourceModel, existing map[string]any, ownAll bool) error {
+	rendered, err := r.planToMap(ctx, diags, state)
+	if err != nil {
+		return err
+	}
+	owned := make(map[string]struct{}, len(rendered))
+	for k := range rendered {
+		owned[k] = struct{}{}
+	}
+	if ownAll {
+		for k := range existing {
+			if _, ok := configio.KnownTopLevelKeys[k]; ok {
+				owned[k] = struct{}{}
+			}
+		}
+	} else {
+		// Entries and keys that merged tables keep from the file are not ours to refresh
+		existing = configio.Managed(existing, rendered, stringsFromList(state.OwnedTables)...)
+	}
+
+	b, err := configio.MarshalTOML(existing)
+	if err != nil {
+		return err
+	}
+	var raw rawCodexConfig
+	if err := toml.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	owns := func(key string) bool {
+		_, ok := owned[key]
+		return ok
+	}
+
+	refreshString := func(key string, dst *types.String, v string) {
+		if owns(key) {
+			*dst = stringValueOrNull(v)
+		}
+	}
+	refreshInt := func(key string, dst *types.Int64, v *int64) {
+		if owns(key) {
+			*dst = int64PtrValue(v)
+		}
+	}
+	refreshBool := func(key string, dst *types.Bool, v *bool) {
+		if owns(key) {
+			*dst = boolPtrValue(v)
+		}
+	}
+
+	refreshString("profile", &state.Profile, raw.Profile)
+	refreshString("model", &state.Model, raw.Model)
+	refreshString("model_provider", &state.ModelProvider, raw.ModelProvider)
+	refreshInt("model_context_window", &state.ModelContextWindow, raw.ModelContextWindow)
+	refreshInt("model_max_output_tokens", &state.ModelMaxOutputTokens, raw.ModelMaxOutputTokens)
+	refreshString("approval_policy", &state.ApprovalPolicy, raw.ApprovalPolicy)
+	refreshString("sandbox_mode", &state.SandboxMode, raw.SandboxMode)
+	refreshString("file_opener", &state.FileOpener, raw.FileOpener)
+	refreshBool("hide_agent_reasoning", &state.HideAgentReasoning, raw.HideAgentReasoning)
+	refreshBool("show_raw_agent_reasoning", &state.ShowRawAgentReasoning, raw.ShowRawAgentReasoning)
+	refreshString("model_reasoning_effort", &state.ModelReasoningEffort, raw.ModelReasoningEffort)
+	refreshString("model_reasoning_summary", &state.ModelReasoningSummary, raw.ModelReasoningSummary)
+	refreshString("model_verbosity", &state.ModelVerbosity, raw.ModelVerbosity)
+	refreshBool("model_supports_reasoning_summaries", &state.ModelSupportsReasoningSummaries, raw.ModelSupportsReasoningSummaries)
+	refreshInt("project_doc_max_bytes", &state.ProjectDocMaxBytes, raw.ProjectDocMaxBytes)
+
+	if owns("notify") {
+		state.Notify = toTFStringListOrNil(raw.Notify)
+	}
+	if owns("tui") {
+		// tui values are written as strings but may be hand-edited into other TOML types.
+		tui, _ := existing["tui"].(map[string]any)
+		state.TUI = types.MapNull(types.StringType)
+		if len(tui) > 0 {
+			items := make(map[string]string, len(tui))
+			for k, v := range tui {
+				items[k] = fmt.Sprint(v)
+			}
+			state.TUI = mapToTypesMapString(items)
+		}
+	}
+
+	if owns("sandbox_workspace_write") {
+		state.SandboxWorkspaceWrite = codexSandboxWorkspaceWriteFromConfig(raw.SandboxWorkspaceWrite)
+	}
+	if owns("history") {
+		state.History = nil
+		if h := raw.History; h != nil {
+			state.History = &codexHistoryModelR{
+				Persistence: stringValueOrNull(h.Persistence),
+				MaxBytes:    int64PtrValue(h.MaxBytes),
+			}
+		}
+	}
+	if owns("shell_environment_policy") {
+		state.ShellEnvPolicy = codexShellEnvPolicyFromConfig(raw.ShellEnvironmentPolicy)
+	}
+
+	if owns("model_providers") {
+		var providers []codexModelProviderWriteModel
+		for _, id := range codexTableOrder(state.ModelProviders, func(m codexModelProviderWriteModel) string { return m.ID.ValueString() }, raw.ModelProviders) {
+			providers = append(providers, codexModelProviderFromConfig(id, raw.ModelProviders[id]))
+		}
+		state.ModelProviders = providers
+	}
+
+	if owns("mcp_servers") {
+		priorEnv := make(map[string]types.Map)
+		for _, s := range state.MCPServers {
+			priorEnv[s.ID.ValueString()] = s.Env
+		}
+		writeEnv := boolOrDefault(state.AllowSensitiveEnvWrites, false)
+
+		var servers []codexMCPServerWriteModel
+		for _, id := range codexTableOrder(state.MCPServers, func(m codexMCPServerWriteModel) string { return m.ID.ValueString() }, raw.MCPServers) {
+			env, ok := priorEnv[id]
+			if !ok {
+				env = types.MapNull(types.StringType)
+			}
+			servers = append(servers, codexMCPServerFromConfig(id, raw.MCPServers[id], env, writeEnv))
+		}
+		state.MCPServers = servers
+	}
+
+	if owns("profiles") {
+		var profiles []codexProfileWriteModel
+		for _, name := range codexTableOrder(state.Profiles, func(m codexProfileWriteModel) string { return m.Name.ValueString() }, raw.Profiles) {
+			profiles = append(profiles, codexProfileFromConfig(name, raw.Profiles[name]))
+		}
+		state.Profiles = profiles
+	}
+
+	return nil
 }
+
+// codexTableOrder returns the ids of table in the order of the prior block list, followed by
+// ids that are new in the file in sorted order. Ids no longer in the file are dropped.
+func codexTableOrder[M any, V any](prior []M, id func(M) string, table map[string]V) []string {
+	var out []string
+	seen := make(map[string]struct{})
+	for _, m := range prior {
+		k := id(m)
+		if _, ok := table[k]; !ok {
+			continue
+		}
+		if _, dup := seen[k]; dup {
+			continue
+		}
+		seen[k] = struct{}{}
+		out = append(out, k)
+	}
+
+	var added []string
+	for k := range table {
+		if _, ok := seen[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	return append(out, added...)
+}
+
+func int64PtrValue(v *int64) types.Int64 {
+	if v == nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(*v)
+}
+
+func float64PtrValue(v *float64) types.Float64 {
+	if v == nil {
+		return types.Float64Null()
+	}
+	return types.Float64Value(*v)
+}
+
+func boolPtrValue(v *bool) types.Bool {
+	if v == nil {
+		return types.BoolNull()
+	}
+	return types.BoolValue(*v)
+}
+
+// toTFStringListOrNil is toTFStringList for optional list attributes, which are null rather
+// than empty when absent.
+func toTFStringListOrNil(items []string) []types.String {
+	if len(items) == 0 {
+		return nil
+	}
+	return toTFStringList(items)
+}
+
+// stringsFromList returns the known values of a list attribute.
+func stringsFromList(items []types.String) []string {
+	var out []string
+	for _, item := range items {
+		if !item.IsNull() && !item.IsUnknown() {
+			out = append(out, item.ValueString())
+		}
+	}
+	return out
+}
+
+func mapStringValueOrNull(in map[string]string) types.Map {
+	if len(in) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	return mapToTypesMapString(in)
+}
+
+// Small helpers for testability and value defaults
 func boolOrDefault(v types.Bool, def bool) bool {
 	if !v.IsNull() && !v.IsUnknown() {
 		return v.ValueBool()
@@ -1,14 +1,450 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	toml "github.com/pelletier/go-toml/v2"
+
+	"terraform-provider-agentsmith/internal/codex/configio"
 )
 
+func TestCodexConfigResource_refreshFromFile(t *testing.T) {
+	r := &codexConfigResource{}
+	ctx := context.Background()
+	existing := map[string]any{
+		"model":           "o3",
+		"approval_policy": "never",
+		"file_opener":     "cursor",
+		"history":         map[string]any{"persistence": "none"},
+		"tui":             map[string]any{"theme": "dark", "rows": int64(40)},
+		"mcp_servers": map[string]any{
+			"b": map[string]any{"command": "b-server", "args": []any{"--hand"}, "env": map[string]any{"TOKEN": "secret"}},
+			"a": map[string]any{"command": "a-server", "args": []any{"--stdio"}},
+			"c": map[string]any{"command": "c-server"},
+		},
+	}
+	newState := func() codexConfigResourceModel {
+		return codexConfigResourceModel{
+			Model:          types.StringValue("gpt-5"),
+			ApprovalPolicy: types.StringValue("on-request"),
+			SandboxMode:    types.StringValue("read-only"),
+			TUI:            types.MapNull(types.StringType),
+			ExtraSettings:  types.StringNull(),
+			MCPServers: []codexMCPServerWriteModel{
+				{ID: types.StringValue("b"), Command: types.StringValue("b-server"), Env: mapToTypesMapString(map[string]string{"TOKEN": "prior"})},
+				{ID: types.StringValue("gone"), Command: types.StringValue("gone"), Env: types.MapNull(types.StringType)},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		ownAll      bool
+		ownedTables []string
+		servers     string
+		check       func(t *testing.T, s codexConfigResourceModel)
+	}{
+		{
+			name:    "owned keys only",
+			servers: "[b]",
+			check: func(t *testing.T, s codexConfigResourceModel) {
+				if s.Model.ValueString() != "o3" || s.ApprovalPolicy.ValueString() != "never" {
+					t.Errorf("Expected owned scalars to be refreshed, got %v and %v", s.Model, s.ApprovalPolicy)
+				}
+				if !s.SandboxMode.IsNull() {
+					t.Errorf("Expected a key missing from the file to be null, got %v", s.SandboxMode)
+				}
+				if !s.FileOpener.IsNull() || s.History != nil || !s.TUI.IsNull() {
+					t.Error("Expected keys that are not owned to be left alone")
+				}
+				if len(s.MCPServers[0].Args) != 0 {
+					t.Errorf("Expected keys added by hand to a merged entry to be left alone, got %v", s.MCPServers[0].Args)
+				}
+			},
+		},
+		{
+			name:        "owned tables",
+			ownedTables: []string{"mcp_servers"},
+			servers:     "[b a c]",
+			check: func(t *testing.T, s codexConfigResourceModel) {
+				if !s.MCPServers[1].Env.IsNull() || len(s.MCPServers[1].Args) != 1 {
+					t.Errorf("Expected new server to be read without env, got %+v", s.MCPServers[1])
+				}
+			},
+		},
+		{
+			name:    "all keys",
+			ownAll:  true,
+			servers: "[b a c]",
+			check: func(t *testing.T, s codexConfigResourceModel) {
+				if s.FileOpener.ValueString() != "cursor" || s.History == nil || s.History.Persistence.ValueString() != "none" {
+					t.Errorf("Expected every key to be read, got %v and %v", s.FileOpener, s.History)
+				}
+				if !s.History.MaxBytes.IsNull() {
+					t.Errorf("Expected unset nested values to be null, got %v", s.History.MaxBytes)
+				}
+				if s.TUI.Elements()["rows"].(types.String).ValueString() != "40" {
+					t.Errorf("Expected tui values to be stringified, got %v", s.TUI)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newState()
+			s.OwnedTables = toTFStringListOrNil(tc.ownedTables)
+			var diags diag.Diagnostics
+			if err := r.refreshFromFile(ctx, &diags, &s, existing, tc.ownAll); err != nil || diags.HasError() {
+				t.Fatalf("Failed to refresh state: %v %v", err, diags)
+			}
+			tc.check(t, s)
+
+			// Servers keep their state order, new ones are appended sorted, removed ones dropped
+			var ids []string
+			for _, server := range s.MCPServers {
+				ids = append(ids, server.ID.ValueString())
+			}
+			if fmt.Sprint(ids) != tc.servers {
+				t.Errorf("Expected servers %s, got %v", tc.servers, ids)
+			}
+			if s.MCPServers[0].Env.Elements()["TOKEN"].(types.String).ValueString() != "prior" {
+				t.Errorf("Expected env to be kept from state, got %v", s.MCPServers[0].Env)
+			}
+		})
+	}
+}
+
+func TestCodexConfigResource_mergeIntoExisting(t *testing.T) {
+	r := &codexConfigResource{}
+	ctx := context.Background()
+
+	testCases := []struct {
+		name          string
+		existing      string
+		plan          codexConfigResourceModel
+		expectSummary string
+	}{
+		{
+			name:          "invalid value unknown at plan time",
+			plan:          codexConfigResourceModel{ApprovalPolicy: types.StringValue("sometimes")},
+			expectSummary: "Invalid Codex config",
+		},
+		{
+			name:          "fail_on_unknown",
+			existing:      "team_defaults = true\n",
+			plan:          codexConfigResourceModel{MergeStrategy: types.StringValue("fail_on_unknown"), Model: types.StringValue("o3")},
+			expectSummary: "Unknown keys in config.toml",
+		},
+		{
+			name:          "unparsable file",
+			existing:      "model = \n",
+			plan:          codexConfigResourceModel{Model: types.StringValue("o3")},
+			expectSummary: "Failed to read existing file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "config.toml")
+			if tc.existing != "" {
+				if err := os.WriteFile(filePath, []byte(tc.existing), 0600); err != nil {
+					t.Fatalf("Failed to write config.toml: %v", err)
+				}
+			}
+
+			var diags diag.Diagnostics
+			_, _, err := r.mergeIntoExisting(ctx, &diags, tc.plan, filePath)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !diags.HasError() || diags.Errors()[0].Summary() != tc.expectSummary {
+				t.Errorf("Expected a %q diagnostic, got %v", tc.expectSummary, diags)
+			}
+		})
+	}
+}
+
+func TestCodexConfigResource_validateCodexMCPServer(t *testing.T) {
+	env := mapToTypesMapString(map[string]string{"DEBUG": "1"})
+	headers := mapToTypesMapString(map[string]string{"X-Team": "infra"})
+	server := func(modify func(*codexMCPServerWriteModel)) codexMCPServerWriteModel {
+		s := codexMCPServerWriteModel{
+			ID:             types.StringValue("docs"),
+			Command:        types.StringNull(),
+			Env:            types.MapNull(types.StringType),
+			Cwd:            types.StringNull(),
+			URL:            types.StringNull(),
+			HTTPHeaders:    types.MapNull(types.StringType),
+			EnvHTTPHeaders: types.MapNull(types.StringType),
+		}
+		modify(&s)
+		return s
+	}
+
+	testCases := []struct {
+		name   string
+		server codexMCPServerWriteModel
+		errors []string
+	}{
+		{
+			name: "stdio",
+			server: server(func(s *codexMCPServerWriteModel) {
+				s.Command = types.StringValue("npx")
+				s.Args = toTFStringList([]string{"-y", "docs"})
+				s.Env = env
+			}),
+		},
+		{
+			name: "http",
+			server: server(func(s *codexMCPServerWriteModel) {
+				s.URL = types.StringValue("https://mcp.example.com/mcp")
+				s.BearerTokenEnvVar = types.StringValue("DOCS_TOKEN")
+				s.HTTPHeaders = headers
+			}),
+		},
+		{
+			name:   "no transport",
+			server: server(func(*codexMCPServerWriteModel) {}),
+			errors: []string{`mcp_servers[0]`},
+		},
+		{
+			name: "both transports",
+			server: server(func(s *codexMCPServerWriteModel) {
+				s.Command = types.StringValue("npx")
+				s.URL = types.StringValue("https://mcp.example.com/mcp")
+			}),
+			errors: []string{`mcp_servers[0]`},
+		},
+		{
+			name: "http fields on stdio server",
+			server: server(func(s *codexMCPServerWriteModel) {
+				s.Command = types.StringValue("npx")
+				s.BearerTokenEnvVar = types.StringValue("DOCS_TOKEN")
+				s.HTTPHeaders = headers
+			}),
+			errors: []string{`mcp_servers[0].bearer_token_env_var`, `mcp_servers[0].http_headers`},
+		},
+		{
+			name: "stdio fields on http server",
+			server: server(func(s *codexMCPServerWriteModel) {
+				s.URL = types.StringValue("https://mcp.example.com/mcp")
+				s.Env = env
+				s.Cwd = types.StringValue("/tmp")
+			}),
+			errors: []string{`mcp_servers[0].env`, `mcp_servers[0].cwd`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateCodexMCPServer(&diags, path.Root("mcp_servers").AtListIndex(0), tc.server)
+
+			var got []string
+			for _, d := range diags.Errors() {
+				got = append(got, d.(diag.DiagnosticWithPath).Path().String())
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.errors) {
+				t.Errorf("Expected errors at %v, got %v", tc.errors, got)
+			}
+		})
+	}
+}
+
+func TestCodexConfigResource_codexMCPServerToMap(t *testing.T) {
+	ctx := context.Background()
+	s := codexMCPServerWriteModel{
+		ID:                types.StringValue("docs"),
+		Command:           types.StringNull(),
+		Env:               types.MapNull(types.StringType),
+		Cwd:               types.StringNull(),
+		URL:               types.StringValue("https://mcp.example.com/mcp"),
+		BearerTokenEnvVar: types.StringValue("DOCS_TOKEN"),
+		HTTPHeaders:       types.MapNull(types.StringType),
+		EnvHTTPHeaders:    mapToTypesMapString(map[string]string{"X-Team": "TEAM"}),
+		StartupTimeoutSec: types.Float64Value(2.5),
+		ToolTimeoutSec:    types.Float64Null(),
+		Enabled:           types.BoolValue(false),
+		DisabledTools:     toTFStringList([]string{"delete"}),
+	}
+
+	var diags diag.Diagnostics
+	row, err := codexMCPServerToMap(ctx, &diags, s, false)
+	if err != nil {
+		t.Fatalf("Failed to render server: %v", err)
+	}
+	b, err := configio.MarshalTOML(map[string]any{"mcp_servers": map[string]any{"docs": row}})
+	if err != nil {
+		t.Fatalf("Failed to encode server: %v", err)
+	}
+	var raw rawCodexConfig
+	if err := toml.Unmarshal(b, &raw); err != nil {
+		t.Fatalf("Failed to decode server: %v", err)
+	}
+
+	got := codexMCPServerFromConfig("docs", raw.MCPServers["docs"], types.MapNull(types.StringType), false)
+	if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", s) {
+		t.Errorf("Expected server to round-trip:\n%+v\ngot:\n%+v", s, got)
+	}
+}
+
+func TestCodexConfigResource_codexProfileToMap(t *testing.T) {
+	ctx := context.Background()
+	p := codexProfileWriteModel{
+		Name:                            types.StringValue("deep"),
+		Model:                           types.StringValue("o3"),
+		ModelProvider:                   types.StringNull(),
+		ModelContextWindow:              types.Int64Value(200000),
+		ModelMaxOutputTokens:            types.Int64Null(),
+		ApprovalPolicy:                  types.StringValue("on-request"),
+		SandboxMode:                     types.StringValue("workspace-write"),
+		FileOpener:                      types.StringNull(),
+		HideAgentReasoning:              types.BoolNull(),
+		ShowRawAgentReasoning:           types.BoolValue(true),
+		ModelReasoningEffort:            types.StringValue("high"),
+		ModelReasoningSummary:           types.StringValue("detailed"),
+		ModelVerbosity:                  types.StringNull(),
+		ModelSupportsReasoningSummaries: types.BoolValue(false),
+		ProjectDocMaxBytes:              types.Int64Value(65536),
+		SandboxWorkspaceWrite: &codexSandboxWorkspaceWriteModelR{
+			WritableRoots:       toTFStringList([]string{"/srv/cache"}),
+			NetworkAccess:       types.BoolValue(true),
+			ExcludeTmpdirEnvVar: types.BoolNull(),
+			ExcludeSlashTmp:     types.BoolNull(),
+		},
+		ShellEnvPolicy: &codexShellEnvPolicyModelR{
+			Inherit:               types.StringValue("core"),
+			IgnoreDefaultExcludes: types.BoolNull(),
+			Set:                   mapToTypesMapString(map[string]string{"CI": "1"}),
+		},
+	}
+
+	var diags diag.Diagnostics
+	row, err := codexProfileToMap(ctx, &diags, p)
+	if err != nil {
+		t.Fatalf("Failed to render profile: %v", err)
+	}
+	b, err := configio.MarshalTOML(map[string]any{"profiles": map[string]any{"deep": row}})
+	if err != nil {
+		t.Fatalf("Failed to encode profile: %v", err)
+	}
+	var raw rawCodexConfig
+	if err := toml.Unmarshal(b, &raw); err != nil {
+		t.Fatalf("Failed to decode profile: %v", err)
+	}
+
+	got := codexProfileFromConfig("deep", raw.Profiles["deep"])
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("Expected profile to round-trip:\n%+v\ngot:\n%+v", p, got)
+	}
+}
+
+func TestCodexConfigResource_codexAttributePath(t *testing.T) {
+	m := &codexConfigResourceModel{
+		MCPServers:     []codexMCPServerWriteModel{{ID: types.StringValue("docs")}},
+		ModelProviders: []codexModelProviderWriteModel{{ID: types.StringValue("openai")}, {ID: types.StringValue("azure")}},
+		Profiles:       []codexProfileWriteModel{{Name: types.StringValue("fast")}, {Name: types.StringValue("deep")}},
+	}
+
+	testCases := []struct {
+		key      []string
+		expected path.Path
+	}{
+		{[]string{"approval_policy"}, path.Root("approval_policy")},
+		{[]string{"history", "persistence"}, path.Root("history").AtName("persistence")},
+		{[]string{"tui", "theme"}, path.Root("tui").AtMapKey("theme")},
+		{[]string{"model_providers", "azure", "env_key"}, path.Root("model_providers").AtListIndex(1).AtName("env_key")},
+		{[]string{"mcp_servers", "docs", "env", "TOKEN"}, path.Root("mcp_servers").AtListIndex(0).AtName("env").AtMapKey("TOKEN")},
+		{[]string{"profiles", "deep", "shell_environment_policy", "inherit"}, path.Root("profiles").AtListIndex(1).AtName("shell_environment_policy").AtName("inherit")},
+		{[]string{"profiles", "missing", "model"}, path.Root("profiles")},
+	}
+
+	for _, tc := range testCases {
+		if got := codexAttributePath(m, tc.key); !got.Equal(tc.expected) {
+			t.Errorf("Expected %s for %v, got %s", tc.expected, tc.key, got)
+		}
+	}
+}
+
+func TestAccCodexConfigResource_validation(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, "config.toml")
+
+	config := func(body string) string {
+		return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_codex_config" "test" {
+  scope = "custom"
+  path  = %q
+%s
+}
+`, workDir, filePath, body)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+  mcp_servers {
+    id      = "docs"
+    command = "docs-server"
+  }
+
+  mcp_servers {
+    id      = "docs"
+    command = "other-server"
+  }
+`),
+				ExpectError: regexp.MustCompile(`Duplicate mcp_servers id`),
+			},
+			{
+				Config: config(`
+  profiles {
+    name            = "fast"
+    model_verbosity = "quiet"
+  }
+`),
+				ExpectError: regexp.MustCompile(`"quiet" is not a valid value`),
+			},
+			{
+				Config: config(`
+  model_providers {
+    id      = "azure"
+    env_key = ""
+  }
+`),
+				ExpectError: regexp.MustCompile(`env_key is empty`),
+			},
+			// References that do not resolve are only warned about
+			{
+				Config: config(`
+  profile        = "deep"
+  model_provider = "azure"
+`),
+				Check: resource.TestCheckResourceAttr("agentsmith_codex_config.test", "profile", "deep"),
+			},
+		},
+	})
+}
+
 func TestAccCodexConfigResource_HomeBasicAndDataSourceRoundTrip(t *testing.T) {
 	homeDir := t.TempDir()
 	workDir := t.TempDir()
@@ -63,3 +499,236 @@ data "agentsmith_codex" "this" {}
 		t.Fatalf("expected config.toml to exist: %v", err)
 	}
 }
+
+func TestAccCodexConfigResource_driftAndImport(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, "config.toml")
+
+	cfg := fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_codex_config" "test" {
+  scope           = "custom"
+  path            = %q
+  approval_policy = "on-request"
+
+  history {
+    persistence = "none"
+  }
+
+  mcp_servers {
+    id      = "docs"
+    command = "docs-server"
+    args    = ["--stdio"]
+  }
+
+  profiles {
+    name                   = "deep"
+    model_reasoning_effort = "high"
+
+    sandbox_workspace_write {
+      network_access = true
+    }
+  }
+}
+`, workDir, filePath)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_config.test", "approval_policy", "on-request"),
+					resource.TestCheckResourceAttr("agentsmith_codex_config.test", "mcp_servers.#", "1"),
+					resource.TestCheckResourceAttr("agentsmith_codex_config.test", "profiles.0.sandbox_workspace_write.network_access", "true"),
+				),
+			},
+			// Edits made outside Terraform show up as drift
+			{
+				PreConfig: func() {
+					content := "# Hand-written\napproval_policy = \"never\"\nunmanaged = true # keep me\n\n[history]\npersistence = \"none\"\n\n[mcp_servers.docs]\ncommand = \"docs-server\"\nargs = [\"--stdio\"]\n"
+					if err := os.WriteFile(filePath, []byte(content), 0600); err != nil {
+						t.Fatalf("Failed to write config.toml: %v", err)
+					}
+				},
+				Config:             cfg,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Applying again only touches the managed keys
+			{
+				Config: cfg,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_config.test", "approval_policy", "on-request"),
+					func(*terraform.State) error {
+						data, err := os.ReadFile(filePath)
+						if err != nil {
+							return err
+						}
+						if !strings.HasPrefix(string(data), "# Hand-written\napproval_policy = 'on-request'\nunmanaged = true # keep me\n") {
+							return fmt.Errorf("expected comments and layout to be kept, got:\n%s", data)
+						}
+						return nil
+					},
+				),
+			},
+			// Keys dropped from the configuration are removed from the file
+			{
+				Config: strings.Replace(cfg, "history {\n    persistence = \"none\"\n  }", "", 1),
+				Check: func(*terraform.State) error {
+					data, err := os.ReadFile(filePath)
+					if err != nil {
+						return err
+					}
+					if strings.Contains(string(data), "[history]") || !strings.Contains(string(data), "unmanaged = true") {
+						return fmt.Errorf("expected only the history table to be removed, got:\n%s", data)
+					}
+					return nil
+				},
+			},
+			// Import populates the configuration from the file
+			{
+				ResourceName:      "agentsmith_codex_config.test",
+				ImportState:       true,
+				ImportStateId:     filePath,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccCodexConfigResource_mergeEntries(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, "config.toml")
+	local := "\n[mcp_servers.local]\ncommand = \"local-server\"\n"
+
+	config := func(ownedTables string) string {
+		return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_codex_config" "test" {
+  scope        = "custom"
+  path         = %q
+  owned_tables = %s
+
+  mcp_servers {
+    id      = "docs"
+    command = "docs-server"
+  }
+
+  mcp_servers {
+    id                   = "remote"
+    url                  = "https://mcp.example.com/mcp"
+    bearer_token_env_var = "REMOTE_TOKEN"
+    tool_timeout_sec     = 30
+    enabled_tools        = ["search"]
+  }
+}
+`, workDir, filePath, ownedTables)
+	}
+	fileContains := func(substr string, want bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			if strings.Contains(string(data), substr) != want {
+				return fmt.Errorf("expected %q in config.toml to be %t, got:\n%s", substr, want, data)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("null"),
+			},
+			// Servers configured by hand are merged with, not drift
+			{
+				PreConfig: func() {
+					f, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0)
+					if err != nil {
+						t.Fatalf("Failed to open config.toml: %v", err)
+					}
+					defer f.Close()
+					if _, err := f.WriteString(local); err != nil {
+						t.Fatalf("Failed to write config.toml: %v", err)
+					}
+				},
+				Config:   config("null"),
+				PlanOnly: true,
+			},
+			{
+				Config: config("null"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_config.test", "mcp_servers.#", "2"),
+					resource.TestCheckResourceAttr("agentsmith_codex_config.test", "mcp_servers.1.url", "https://mcp.example.com/mcp"),
+					fileContains("[mcp_servers.local]", true),
+					fileContains("tool_timeout_sec = 30.0", true),
+				),
+			},
+			// Owned tables drop what is not configured
+			{
+				Config: config(`["mcp_servers"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_config.test", "mcp_servers.#", "2"),
+					fileContains("[mcp_servers.local]", false),
+					fileContains("[mcp_servers.docs]", true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCodexConfigResource_keepFileOnDestroy(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, "config.toml")
+	handWritten := "# Team defaults\nmodel = \"o3\"\n\n[mcp_servers.local]\ncommand = \"local-server\"\n"
+	if err := os.WriteFile(filePath, []byte(handWritten), 0600); err != nil {
+		t.Fatalf("Failed to write config.toml: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return fmt.Errorf("expected config.toml to be kept: %w", err)
+			}
+			if string(data) != handWritten {
+				return fmt.Errorf("expected only the keys written by the resource to be removed, got:\n%s", data)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_codex_config" "test" {
+  scope           = "custom"
+  path            = %q
+  approval_policy = "on-request"
+
+  mcp_servers {
+    id      = "docs"
+    command = "docs-server"
+  }
+}
+`, workDir, filePath),
+			},
+		},
+	})
+}
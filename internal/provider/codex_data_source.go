@@ -84,16 +84,7 @@ func (d *codexDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 					"model_supports_reasoning_summaries": schema.BoolAttribute{Description: "If true, forces reasoning to be set on requests to the current model.", Computed: true},
 					"project_doc_max_bytes":              schema.Int64Attribute{Description: "Maximum number of bytes to read from an `AGENTS.md` file. Defaults to 32 KiB.", Computed: true},
 					"notify":                             schema.ListAttribute{Description: "A command and arguments to execute for notifications.", ElementType: types.StringType, Computed: true},
-					"sandbox_workspace_write": schema.SingleNestedAttribute{
-						Description: "Specific settings for the `workspace-write` sandbox mode.",
-						Computed:    true,
-						Attributes: map[string]schema.Attribute{
-							"writable_roots":         schema.ListAttribute{Description: "A list of additional writable root paths beyond the defaults.", ElementType: types.StringType, Computed: true},
-							"network_access":         schema.BoolAttribute{Description: "If true, allows the command being run inside the sandbox to make outbound network requests. Default: false.", Computed: true},
-							"exclude_tmpdir_env_var": schema.BoolAttribute{Description: "If true, excludes the `$TMPDIR` environment variable from writable roots.", Computed: true},
-							"exclude_slash_tmp":      schema.BoolAttribute{Description: "If true, excludes the `/tmp` directory from writable roots.", Computed: true},
-						},
-					},
+					"sandbox_workspace_write":            codexSandboxWorkspaceWriteAttribute(),
 					"history": schema.SingleNestedAttribute{
 						Description: "Settings for command history persistence.",
 						Computed:    true,
@@ -102,17 +93,7 @@ func (d *codexDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 							"max_bytes":   schema.Int64Attribute{Description: "The maximum size of the history file in bytes.", Computed: true},
 						},
 					},
-					"shell_environment_policy": schema.SingleNestedAttribute{
-						Description: "Policy for managing environment variables passed to subprocesses.",
-						Computed:    true,
-						Attributes: map[string]schema.Attribute{
-							"inherit":                 schema.StringAttribute{Description: "The starting template for the environment: `all` (default), `core`, or `none`.", Computed: true},
-							"ignore_default_excludes": schema.BoolAttribute{Description: "If false (default), automatically removes variables containing `KEY`, `SECRET`, or `TOKEN`.", Computed: true},
-							"exclude":                 schema.ListAttribute{Description: "A list of case-insensitive glob patterns for environment variables to exclude.", ElementType: types.StringType, Computed: true},
-							"set":                     schema.MapAttribute{Description: "A map of key/value pairs to explicitly set or override.", ElementType: types.StringType, Computed: true},
-							"include_only":            schema.ListAttribute{Description: "If non-empty, acts as a whitelist of glob patterns for variables to keep.", ElementType: types.StringType, Computed: true},
-						},
-					},
+					"shell_environment_policy": codexShellEnvPolicyAttribute(),
 					"model_providers": schema.ListNestedAttribute{
 						Description: "A list of configured model providers.",
 						Computed:    true,
@@ -135,21 +116,43 @@ func (d *codexDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 						Description: "A list of configured MCP (Model-Context Protocol) servers for custom tools.",
 						Computed:    true,
 						NestedObject: schema.NestedAttributeObject{Attributes: map[string]schema.Attribute{
-							"id":      schema.StringAttribute{Description: "The unique identifier for the MCP server.", Computed: true},
-							"command": schema.StringAttribute{Description: "The command to execute to start the server.", Computed: true},
-							"args":    schema.ListAttribute{Description: "A list of arguments for the command.", ElementType: types.StringType, Computed: true},
-							"env":     schema.MapAttribute{Description: "A map of environment variables to set for the server process.", ElementType: types.StringType, Computed: true, Sensitive: true},
+							"id":                   schema.StringAttribute{Description: "The unique identifier for the MCP server.", Computed: true},
+							"command":              schema.StringAttribute{Description: "The command to execute to start a stdio server.", Computed: true},
+							"args":                 schema.ListAttribute{Description: "A list of arguments for the command.", ElementType: types.StringType, Computed: true},
+							"env":                  schema.MapAttribute{Description: "A map of environment variables to set for the server process.", ElementType: types.StringType, Computed: true, Sensitive: true},
+							"cwd":                  schema.StringAttribute{Description: "The working directory of the server process.", Computed: true},
+							"url":                  schema.StringAttribute{Description: "The URL of a streamable HTTP server.", Computed: true},
+							"bearer_token_env_var": schema.StringAttribute{Description: "The environment variable that holds the bearer token sent to an HTTP server.", Computed: true},
+							"http_headers":         schema.MapAttribute{Description: "A map of static HTTP headers sent to an HTTP server.", ElementType: types.StringType, Computed: true},
+							"env_http_headers":     schema.MapAttribute{Description: "A map of HTTP headers sent to an HTTP server, with values sourced from environment variables.", ElementType: types.StringType, Computed: true},
+							"startup_timeout_sec":  schema.Float64Attribute{Description: "How long in seconds to wait for the server to start. Default: 10.", Computed: true},
+							"tool_timeout_sec":     schema.Float64Attribute{Description: "How long in seconds to wait for a tool call to complete. Default: 60.", Computed: true},
+							"enabled":              schema.BoolAttribute{Description: "Whether the server is enabled. Default: true.", Computed: true},
+							"enabled_tools":        schema.ListAttribute{Description: "If set, only these tools of the server are exposed.", ElementType: types.StringType, Computed: true},
+							"disabled_tools":       schema.ListAttribute{Description: "Tools of the server that are not exposed.", ElementType: types.StringType, Computed: true},
 						}},
 					},
 					"profiles": schema.ListNestedAttribute{
 						Description: "A list of all configuration profiles defined in `config.toml`.",
 						Computed:    true,
 						NestedObject: schema.NestedAttributeObject{Attributes: map[string]schema.Attribute{
-							"name":            schema.StringAttribute{Description: "The name of the profile.", Computed: true},
-							"model":           schema.StringAttribute{Description: "The model associated with this profile.", Computed: true},
-							"model_provider":  schema.StringAttribute{Description: "The model provider associated with this profile.", Computed: true},
-							"approval_policy": schema.StringAttribute{Description: "The approval policy associated with this profile.", Computed: true},
-							"sandbox_mode":    schema.StringAttribute{Description: "The sandbox mode associated with this profile.", Computed: true},
+							"name":                               schema.StringAttribute{Description: "The name of the profile.", Computed: true},
+							"model":                              schema.StringAttribute{Description: "The model associated with this profile.", Computed: true},
+							"model_provider":                     schema.StringAttribute{Description: "The model provider associated with this profile.", Computed: true},
+							"model_context_window":               schema.Int64Attribute{Description: "The context window size for the model of this profile, in tokens.", Computed: true},
+							"model_max_output_tokens":            schema.Int64Attribute{Description: "The maximum number of output tokens for the model of this profile.", Computed: true},
+							"approval_policy":                    schema.StringAttribute{Description: "The approval policy associated with this profile.", Computed: true},
+							"sandbox_mode":                       schema.StringAttribute{Description: "The sandbox mode associated with this profile.", Computed: true},
+							"file_opener":                        schema.StringAttribute{Description: "The editor/URI scheme for hyperlinking file citations in this profile.", Computed: true},
+							"hide_agent_reasoning":               schema.BoolAttribute{Description: "If true, this profile suppresses the model's internal 'thinking' events.", Computed: true},
+							"show_raw_agent_reasoning":           schema.BoolAttribute{Description: "If true, this profile surfaces the model’s raw chain-of-thought, if available.", Computed: true},
+							"model_reasoning_effort":             schema.StringAttribute{Description: "The reasoning effort associated with this profile.", Computed: true},
+							"model_reasoning_summary":            schema.StringAttribute{Description: "The reasoning summary detail associated with this profile.", Computed: true},
+							"model_verbosity":                    schema.StringAttribute{Description: "The output verbosity associated with this profile.", Computed: true},
+							"model_supports_reasoning_summaries": schema.BoolAttribute{Description: "If true, this profile forces reasoning to be set on requests.", Computed: true},
+							"project_doc_max_bytes":              schema.Int64Attribute{Description: "Maximum number of bytes this profile reads from an `AGENTS.md` file.", Computed: true},
+							"sandbox_workspace_write":            codexSandboxWorkspaceWriteAttribute(),
+							"shell_environment_policy":           codexShellEnvPolicyAttribute(),
 						}},
 					},
 				},
@@ -168,6 +171,35 @@ func (d *codexDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 }
 
 // Read refreshes the Terraform state with the latest data.
+// codexSandboxWorkspaceWriteAttribute describes the `sandbox_workspace_write` table, at the top level and in profiles.
+func codexSandboxWorkspaceWriteAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Specific settings for the `workspace-write` sandbox mode.",
+		Computed:    true,
+		Attributes: map[string]schema.Attribute{
+			"writable_roots":         schema.ListAttribute{Description: "A list of additional writable root paths beyond the defaults.", ElementType: types.StringType, Computed: true},
+			"network_access":         schema.BoolAttribute{Description: "If true, allows the command being run inside the sandbox to make outbound network requests. Default: false.", Computed: true},
+			"exclude_tmpdir_env_var": schema.BoolAttribute{Description: "If true, excludes the `$TMPDIR` environment variable from writable roots.", Computed: true},
+			"exclude_slash_tmp":      schema.BoolAttribute{Description: "If true, excludes the `/tmp` directory from writable roots.", Computed: true},
+		},
+	}
+}
+
+// codexShellEnvPolicyAttribute describes the `shell_environment_policy` table, at the top level and in profiles.
+func codexShellEnvPolicyAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Policy for managing environment variables passed to subprocesses.",
+		Computed:    true,
+		Attributes: map[string]schema.Attribute{
+			"inherit":                 schema.StringAttribute{Description: "The starting template for the environment: `all` (default), `core`, or `none`.", Computed: true},
+			"ignore_default_excludes": schema.BoolAttribute{Description: "If false (default), automatically removes variables containing `KEY`, `SECRET`, or `TOKEN`.", Computed: true},
+			"exclude":                 schema.ListAttribute{Description: "A list of case-insensitive glob patterns for environment variables to exclude.", ElementType: types.StringType, Computed: true},
+			"set":                     schema.MapAttribute{Description: "A map of key/value pairs to explicitly set or override.", ElementType: types.StringType, Computed: true},
+			"include_only":            schema.ListAttribute{Description: "If non-empty, acts as a whitelist of glob patterns for variables to keep.", ElementType: types.StringType, Computed: true},
+		},
+	}
+}
+
 func (d *codexDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state codexDataSourceModel
 
@@ -231,7 +263,7 @@ func (d *codexDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	if base.Profile != "" {
 		state.ActiveProfile = types.StringValue(base.Profile)
 		if prof, ok := base.Profiles[base.Profile]; ok {
-			base = mergeCodexConfig(base, prof)
+			base = mergeCodexConfig(base, profileOverlay(prof))
 		}
 	} else {
 		state.ActiveProfile = types.StringNull()
@@ -375,9 +407,23 @@ type modelProviderConfig struct {
 }
 
 type mcpServerConfig struct {
+	// stdio transport
 	Command string            `toml:"command"`
 	Args    []string          `toml:"args"`
 	Env     map[string]string `toml:"env"`
+	Cwd     string            `toml:"cwd"`
+
+	// Streamable HTTP transport
+	URL               string            `toml:"url"`
+	BearerTokenEnvVar string            `toml:"bearer_token_env_var"`
+	HTTPHeaders       map[string]string `toml:"http_headers"`
+	EnvHTTPHeaders    map[string]string `toml:"env_http_headers"`
+
+	StartupTimeoutSec *float64 `toml:"startup_timeout_sec"`
+	ToolTimeoutSec    *float64 `toml:"tool_timeout_sec"`
+	Enabled           *bool    `toml:"enabled"`
+	EnabledTools      []string `toml:"enabled_tools"`
+	DisabledTools     []string `toml:"disabled_tools"`
 }
 
 // Merge cfg2 onto cfg1, returning a new config
@@ -540,6 +586,29 @@ func mergeShellEnv(a, b *shellEnvPolicy) *shellEnvPolicy {
 	return &out
 }
 
+// profileOverlay keeps only the keys a profile may set, so that tables such
+// as model_providers or mcp_servers nested under a profile are not applied.
+func profileOverlay(p rawCodexConfig) rawCodexConfig {
+	return rawCodexConfig{
+		Model:                           p.Model,
+		ModelProvider:                   p.ModelProvider,
+		ModelContextWindow:              p.ModelContextWindow,
+		ModelMaxOutputTokens:            p.ModelMaxOutputTokens,
+		ApprovalPolicy:                  p.ApprovalPolicy,
+		SandboxMode:                     p.SandboxMode,
+		SandboxWorkspaceWrite:           p.SandboxWorkspaceWrite,
+		FileOpener:                      p.FileOpener,
+		HideAgentReasoning:              p.HideAgentReasoning,
+		ShowRawAgentReasoning:           p.ShowRawAgentReasoning,
+		ModelReasoningEffort:            p.ModelReasoningEffort,
+		ModelReasoningSummary:           p.ModelReasoningSummary,
+		ModelVerbosity:                  p.ModelVerbosity,
+		ModelSupportsReasoningSummaries: p.ModelSupportsReasoningSummaries,
+		ProjectDocMaxBytes:              p.ProjectDocMaxBytes,
+		ShellEnvironmentPolicy:          p.ShellEnvironmentPolicy,
+	}
+}
+
 func mergeModelProvider(a, b modelProviderConfig) modelProviderConfig {
 	out := a
 	if b.Name != "" {
@@ -586,6 +655,36 @@ func mergeMCP(a, b mcpServerConfig) mcpServerConfig {
 	if len(b.Env) > 0 {
 		out.Env = b.Env
 	}
+	if b.Cwd != "" {
+		out.Cwd = b.Cwd
+	}
+	if b.URL != "" {
+		out.URL = b.URL
+	}
+	if b.BearerTokenEnvVar != "" {
+		out.BearerTokenEnvVar = b.BearerTokenEnvVar
+	}
+	if len(b.HTTPHeaders) > 0 {
+		out.HTTPHeaders = b.HTTPHeaders
+	}
+	if len(b.EnvHTTPHeaders) > 0 {
+		out.EnvHTTPHeaders = b.EnvHTTPHeaders
+	}
+	if b.StartupTimeoutSec != nil {
+		out.StartupTimeoutSec = b.StartupTimeoutSec
+	}
+	if b.ToolTimeoutSec != nil {
+		out.ToolTimeoutSec = b.ToolTimeoutSec
+	}
+	if b.Enabled != nil {
+		out.Enabled = b.Enabled
+	}
+	if len(b.EnabledTools) > 0 {
+		out.EnabledTools = b.EnabledTools
+	}
+	if len(b.DisabledTools) > 0 {
+		out.DisabledTools = b.DisabledTools
+	}
 	return out
 }
 
@@ -688,22 +787,7 @@ func populateEffectiveModel(dst *codexEffectiveModel, src *rawCodexConfig) {
 		dst.Notify = toTFStringList(src.Notify)
 	}
 
-	// Sandbox workspace write
-	if src.SandboxWorkspaceWrite != nil {
-		dst.SandboxWorkspaceWrite = &codexSandboxWorkspaceWriteModel{}
-		if len(src.SandboxWorkspaceWrite.WritableRoots) > 0 {
-			dst.SandboxWorkspaceWrite.WritableRoots = toTFStringList(src.SandboxWorkspaceWrite.WritableRoots)
-		}
-		if src.SandboxWorkspaceWrite.NetworkAccess != nil {
-			dst.SandboxWorkspaceWrite.NetworkAccess = types.BoolValue(*src.SandboxWorkspaceWrite.NetworkAccess)
-		}
-		if src.SandboxWorkspaceWrite.ExcludeTmpdirEnvVar != nil {
-			dst.SandboxWorkspaceWrite.ExcludeTmpdirEnvVar = types.BoolValue(*src.SandboxWorkspaceWrite.ExcludeTmpdirEnvVar)
-		}
-		if src.SandboxWorkspaceWrite.ExcludeSlashTmp != nil {
-			dst.SandboxWorkspaceWrite.ExcludeSlashTmp = types.BoolValue(*src.SandboxWorkspaceWrite.ExcludeSlashTmp)
-		}
-	}
+	dst.SandboxWorkspaceWrite = flattenSandboxWorkspaceWrite(src.SandboxWorkspaceWrite)
 
 	// History
 	if src.History != nil {
@@ -716,28 +800,7 @@ func populateEffectiveModel(dst *codexEffectiveModel, src *rawCodexConfig) {
 		}
 	}
 
-	// Shell env policy
-	if src.ShellEnvironmentPolicy != nil {
-		dst.ShellEnvironmentPolicy = &codexShellEnvPolicyModel{}
-		if src.ShellEnvironmentPolicy.Inherit != "" {
-			dst.ShellEnvironmentPolicy.Inherit = types.StringValue(src.ShellEnvironmentPolicy.Inherit)
-		}
-		if src.ShellEnvironmentPolicy.IgnoreDefaultExcludes != nil {
-			dst.ShellEnvironmentPolicy.IgnoreDefaultExcludes = types.BoolValue(*src.ShellEnvironmentPolicy.IgnoreDefaultExcludes)
-		}
-		if len(src.ShellEnvironmentPolicy.Exclude) > 0 {
-			dst.ShellEnvironmentPolicy.Exclude = toTFStringList(src.ShellEnvironmentPolicy.Exclude)
-		}
-		if len(src.ShellEnvironmentPolicy.Set) > 0 {
-			dst.ShellEnvironmentPolicy.Set = make(map[string]types.String, len(src.ShellEnvironmentPolicy.Set))
-			for k, v := range src.ShellEnvironmentPolicy.Set {
-				dst.ShellEnvironmentPolicy.Set[k] = types.StringValue(v)
-			}
-		}
-		if len(src.ShellEnvironmentPolicy.IncludeOnly) > 0 {
-			dst.ShellEnvironmentPolicy.IncludeOnly = toTFStringList(src.ShellEnvironmentPolicy.IncludeOnly)
-		}
-	}
+	dst.ShellEnvironmentPolicy = flattenShellEnvPolicy(src.ShellEnvironmentPolicy)
 
 	// Dynamic tables
 	dst.ModelProviders = flattenModelProviders(src)
@@ -761,18 +824,41 @@ type codexModelProviderModel struct {
 }
 
 type codexMCPServerModel struct {
-	ID      types.String   `tfsdk:"id"`
-	Command types.String   `tfsdk:"command"`
-	Args    []types.String `tfsdk:"args"`
-	Env     types.Map      `tfsdk:"env"`
+	ID                types.String   `tfsdk:"id"`
+	Command           types.String   `tfsdk:"command"`
+	Args              []types.String `tfsdk:"args"`
+	Env               types.Map      `tfsdk:"env"`
+	Cwd               types.String   `tfsdk:"cwd"`
+	URL               types.String   `tfsdk:"url"`
+	BearerTokenEnvVar types.String   `tfsdk:"bearer_token_env_var"`
+	HTTPHeaders       types.Map      `tfsdk:"http_headers"`
+	EnvHTTPHeaders    types.Map      `tfsdk:"env_http_headers"`
+	StartupTimeoutSec types.Float64  `tfsdk:"startup_timeout_sec"`
+	ToolTimeoutSec    types.Float64  `tfsdk:"tool_timeout_sec"`
+	Enabled           types.Bool     `tfsdk:"enabled"`
+	EnabledTools      []types.String `tfsdk:"enabled_tools"`
+	DisabledTools     []types.String `tfsdk:"disabled_tools"`
 }
 
 type codexProfileModel struct {
-	Name           types.String `tfsdk:"name"`
-	Model          types.String `tfsdk:"model"`
-	ModelProvider  types.String `tfsdk:"model_provider"`
-	ApprovalPolicy types.String `tfsdk:"approval_policy"`
-	SandboxMode    types.String `tfsdk:"sandbox_mode"`
+	Name                            types.String `tfsdk:"name"`
+	Model                           types.String `tfsdk:"model"`
+	ModelProvider                   types.String `tfsdk:"model_provider"`
+	ModelContextWindow              types.Int64  `tfsdk:"model_context_window"`
+	ModelMaxOutputTokens            types.Int64  `tfsdk:"model_max_output_tokens"`
+	ApprovalPolicy                  types.String `tfsdk:"approval_policy"`
+	SandboxMode                     types.String `tfsdk:"sandbox_mode"`
+	FileOpener                      types.String `tfsdk:"file_opener"`
+	HideAgentReasoning              types.Bool   `tfsdk:"hide_agent_reasoning"`
+	ShowRawAgentReasoning           types.Bool   `tfsdk:"show_raw_agent_reasoning"`
+	ModelReasoningEffort            types.String `tfsdk:"model_reasoning_effort"`
+	ModelReasoningSummary           types.String `tfsdk:"model_reasoning_summary"`
+	ModelVerbosity                  types.String `tfsdk:"model_verbosity"`
+	ModelSupportsReasoningSummaries types.Bool   `tfsdk:"model_supports_reasoning_summaries"`
+	ProjectDocMaxBytes              types.Int64  `tfsdk:"project_doc_max_bytes"`
+
+	SandboxWorkspaceWrite  *codexSandboxWorkspaceWriteModel `tfsdk:"sandbox_workspace_write"`
+	ShellEnvironmentPolicy *codexShellEnvPolicyModel        `tfsdk:"shell_environment_policy"`
 }
 
 func flattenModelProviders(src *rawCodexConfig) []codexModelProviderModel {
@@ -787,27 +873,15 @@ func flattenModelProviders(src *rawCodexConfig) []codexModelProviderModel {
 	out := make([]codexModelProviderModel, 0, len(ids))
 	for _, id := range ids {
 		mp := src.ModelProviders[id]
-		var q, h, eh types.Map
-		// Build maps lazily using helper wrapper; since we don't have ctx here, construct via types.MapValueMust
-		// Use types.MapValueFrom requires context, which we avoid. The zero value (null) is acceptable when empty.
-		if len(mp.QueryParams) > 0 {
-			q = mapToTypesMapString(mp.QueryParams)
-		}
-		if len(mp.HTTPHeaders) > 0 {
-			h = mapToTypesMapString(mp.HTTPHeaders)
-		}
-		if len(mp.EnvHTTPHeaders) > 0 {
-			eh = mapToTypesMapString(mp.EnvHTTPHeaders)
-		}
 		item := codexModelProviderModel{
 			ID:             types.StringValue(id),
 			Name:           types.StringValue(mp.Name),
 			BaseURL:        types.StringValue(mp.BaseURL),
 			EnvKey:         types.StringValue(mp.EnvKey),
 			WireAPI:        types.StringValue(mp.WireAPI),
-			QueryParams:    q,
-			HTTPHeaders:    h,
-			EnvHTTPHeaders: eh,
+			QueryParams:    mapStringValueOrNull(mp.QueryParams),
+			HTTPHeaders:    mapStringValueOrNull(mp.HTTPHeaders),
+			EnvHTTPHeaders: mapStringValueOrNull(mp.EnvHTTPHeaders),
 		}
 		if mp.EnvKeyIsSet != nil {
 			item.EnvKeyIsSet = types.BoolValue(*mp.EnvKeyIsSet)
@@ -838,15 +912,21 @@ func flattenMCPServers(src *rawCodexConfig) []codexMCPServerModel {
 	out := make([]codexMCPServerModel, 0, len(ids))
 	for _, id := range ids {
 		s := src.MCPServers[id]
-		var env types.Map
-		if len(s.Env) > 0 {
-			env = mapToTypesMapString(s.Env)
-		}
 		out = append(out, codexMCPServerModel{
-			ID:      types.StringValue(id),
-			Command: types.StringValue(s.Command),
-			Args:    toTFStringList(s.Args),
-			Env:     env,
+			ID:                types.StringValue(id),
+			Command:           stringValueOrNull(s.Command),
+			Args:              toTFStringListOrNil(s.Args),
+			Env:               mapStringValueOrNull(s.Env),
+			Cwd:               stringValueOrNull(s.Cwd),
+			URL:               stringValueOrNull(s.URL),
+			BearerTokenEnvVar: stringValueOrNull(s.BearerTokenEnvVar),
+			HTTPHeaders:       mapStringValueOrNull(s.HTTPHeaders),
+			EnvHTTPHeaders:    mapStringValueOrNull(s.EnvHTTPHeaders),
+			StartupTimeoutSec: float64PtrValue(s.StartupTimeoutSec),
+			ToolTimeoutSec:    float64PtrValue(s.ToolTimeoutSec),
+			Enabled:           boolPtrValue(s.Enabled),
+			EnabledTools:      toTFStringListOrNil(s.EnabledTools),
+			DisabledTools:     toTFStringListOrNil(s.DisabledTools),
 		})
 	}
 	return out
@@ -864,24 +944,60 @@ func flattenProfiles(src *rawCodexConfig) []codexProfileModel {
 	out := make([]codexProfileModel, 0, len(names))
 	for _, name := range names {
 		p := src.Profiles[name]
-		item := codexProfileModel{Name: types.StringValue(name)}
-		if p.Model != "" {
-			item.Model = types.StringValue(p.Model)
-		}
-		if p.ModelProvider != "" {
-			item.ModelProvider = types.StringValue(p.ModelProvider)
-		}
-		if p.ApprovalPolicy != "" {
-			item.ApprovalPolicy = types.StringValue(p.ApprovalPolicy)
-		}
-		if p.SandboxMode != "" {
-			item.SandboxMode = types.StringValue(p.SandboxMode)
-		}
-		out = append(out, item)
+		out = append(out, codexProfileModel{
+			Name:                            types.StringValue(name),
+			Model:                           stringValueOrNull(p.Model),
+			ModelProvider:                   stringValueOrNull(p.ModelProvider),
+			ModelContextWindow:              int64PtrValue(p.ModelContextWindow),
+			ModelMaxOutputTokens:            int64PtrValue(p.ModelMaxOutputTokens),
+			ApprovalPolicy:                  stringValueOrNull(p.ApprovalPolicy),
+			SandboxMode:                     stringValueOrNull(p.SandboxMode),
+			FileOpener:                      stringValueOrNull(p.FileOpener),
+			HideAgentReasoning:              boolPtrValue(p.HideAgentReasoning),
+			ShowRawAgentReasoning:           boolPtrValue(p.ShowRawAgentReasoning),
+			ModelReasoningEffort:            stringValueOrNull(p.ModelReasoningEffort),
+			ModelReasoningSummary:           stringValueOrNull(p.ModelReasoningSummary),
+			ModelVerbosity:                  stringValueOrNull(p.ModelVerbosity),
+			ModelSupportsReasoningSummaries: boolPtrValue(p.ModelSupportsReasoningSummaries),
+			ProjectDocMaxBytes:              int64PtrValue(p.ProjectDocMaxBytes),
+			SandboxWorkspaceWrite:           flattenSandboxWorkspaceWrite(p.SandboxWorkspaceWrite),
+			ShellEnvironmentPolicy:          flattenShellEnvPolicy(p.ShellEnvironmentPolicy),
+		})
 	}
 	return out
 }
 
+func flattenSandboxWorkspaceWrite(src *sandboxWorkspaceWrite) *codexSandboxWorkspaceWriteModel {
+	if src == nil {
+		return nil
+	}
+	return &codexSandboxWorkspaceWriteModel{
+		WritableRoots:       toTFStringListOrNil(src.WritableRoots),
+		NetworkAccess:       boolPtrValue(src.NetworkAccess),
+		ExcludeTmpdirEnvVar: boolPtrValue(src.ExcludeTmpdirEnvVar),
+		ExcludeSlashTmp:     boolPtrValue(src.ExcludeSlashTmp),
+	}
+}
+
+func flattenShellEnvPolicy(src *shellEnvPolicy) *codexShellEnvPolicyModel {
+	if src == nil {
+		return nil
+	}
+	dst := &codexShellEnvPolicyModel{
+		Inherit:               stringValueOrNull(src.Inherit),
+		IgnoreDefaultExcludes: boolPtrValue(src.IgnoreDefaultExcludes),
+		Exclude:               toTFStringListOrNil(src.Exclude),
+		IncludeOnly:           toTFStringListOrNil(src.IncludeOnly),
+	}
+	if len(src.Set) > 0 {
+		dst.Set = make(map[string]types.String, len(src.Set))
+		for k, v := range src.Set {
+			dst.Set[k] = types.StringValue(v)
+		}
+	}
+	return dst
+}
+
 // Build a types.Map[string]string without context; use Must constructor
 func mapToTypesMapString(in map[string]string) types.Map {
 	return types.MapValueMust(types.StringType, mapStringToAttrValue(in))
@@ -33,6 +33,10 @@ env_key = "OPENAI_API_KEY"
 [profiles.o3_high]
 model = "o3"
 approval_policy = "never"
+model_reasoning_effort = "high"
+
+[profiles.o3_high.sandbox_workspace_write]
+exclude_slash_tmp = true
 `
 	if err := os.WriteFile(filepath.Join(homeDir, "config.toml"), []byte(homeCfg), 0o644); err != nil {
 		t.Fatalf("write home config: %v", err)
@@ -51,6 +55,12 @@ command = "npx"
 args = ["-y", "mcp"]
 env = { API_KEY = "xyz" }
 
+[mcp_servers.docs]
+url = "https://mcp.example.com/mcp"
+bearer_token_env_var = "DOCS_TOKEN"
+tool_timeout_sec = 30
+enabled_tools = ["search"]
+
 [shell_environment_policy]
 inherit = "core"
 `
@@ -85,6 +95,8 @@ data "agentsmith_codex" "this" {}
 					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.approval_policy", "never"),
 					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.sandbox_mode", "workspace-write"),
 					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.sandbox_workspace_write.network_access", "true"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.sandbox_workspace_write.exclude_slash_tmp", "true"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.model_reasoning_effort", "high"),
 
 					// Model providers (from env override and home config)
 					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.model_providers.#", "1"),
@@ -94,13 +106,22 @@ data "agentsmith_codex" "this" {}
 					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.model_providers.0.env_key_is_set", "true"),
 
 					// MCP servers
-					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.#", "1"),
-					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.0.id", "test"),
-					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.0.command", "npx"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.#", "2"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.0.id", "docs"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.0.url", "https://mcp.example.com/mcp"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.0.bearer_token_env_var", "DOCS_TOKEN"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.0.tool_timeout_sec", "30"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.0.enabled_tools.0", "search"),
+					resource.TestCheckNoResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.0.command"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.1.id", "test"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.mcp_servers.1.command", "npx"),
 
 					// Profiles listing
 					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.profiles.#", "1"),
 					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.profiles.0.name", "o3_high"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.profiles.0.model_reasoning_effort", "high"),
+					resource.TestCheckResourceAttr("data.agentsmith_codex.this", "effective_config.profiles.0.sandbox_workspace_write.exclude_slash_tmp", "true"),
+					resource.TestCheckNoResourceAttr("data.agentsmith_codex.this", "effective_config.profiles.0.shell_environment_policy.inherit"),
 				),
 			},
 		},
@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &codexMCPServerResource{}
+	_ resource.ResourceWithConfigure      = &codexMCPServerResource{}
+	_ resource.ResourceWithImportState    = &codexMCPServerResource{}
+	_ resource.ResourceWithValidateConfig = &codexMCPServerResource{}
+)
+
+func NewCodexMCPServerResource() resource.Resource {
+	return &codexMCPServerResource{codexTable{table: "mcp_servers", typeName: "agentsmith_codex_mcp_server"}}
+}
+
+// codexMCPServerResource owns a single `[mcp_servers.<id>]` table of a Codex config.toml.
+type codexMCPServerResource struct {
+	codexTable
+}
+
+type codexMCPServerResourceModel struct {
+	codexTableFileModel
+	codexMCPServerWriteModel
+	ServerJSON              types.String `tfsdk:"server_json"`
+	AllowSensitiveEnvWrites types.Bool   `tfsdk:"allow_sensitive_env_writes"`
+}
+
+// codexMCPServerJSONAttributes are the attributes that server_json sets instead.
+var codexMCPServerJSONAttributes = []string{"command", "args", "env", "cwd", "url", "http_headers"}
+
+func (r *codexMCPServerResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_codex_mcp_server"
+}
+
+func (r *codexMCPServerResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attrs := codexMCPServerAttributes()
+	attrs["id"] = schema.StringAttribute{
+		Description:   "The unique identifier for the MCP server, used as the key of its `[mcp_servers.<id>]` table.",
+		Required:      true,
+		PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	attrs["server_json"] = schema.StringAttribute{
+		Description: "The transport of the server as a JSON object, such as the `json` output of the `agentsmith_mcp_stdio` and `agentsmith_mcp_remote` data sources. Its `command`, `args`, `env`, `cwd`, `url` and `headers` are written; other keys are dropped. Codex only supports stdio and streamable HTTP servers, so `sse` servers are rejected. Conflicts with `command`, `args`, `env`, `cwd`, `url` and `http_headers`.",
+		Optional:    true,
+		Validators:  []validator.String{jsonObject()},
+	}
+	attrs["allow_sensitive_env_writes"] = schema.BoolAttribute{Description: "If true, writes the `env` of the server to the config file. Otherwise `env` is left as it is in the file. Defaults to `false`.", Optional: true}
+	addCodexTableFileAttributes(attrs)
+
+	resp.Schema = schema.Schema{
+		Description: "Manages a single `[mcp_servers.<id>]` table in a Codex CLI `config.toml` file. The rest of the file, including its comments, layout and other MCP servers, is left alone, so several modules can contribute servers to the same file. It can be used alongside an `agentsmith_codex_config` that merges with `preserve_unknown`, as long as that resource does not also declare the server or list `mcp_servers` in `owned_tables`.",
+		Attributes:  attrs,
+	}
+}
+
+func (r *codexMCPServerResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config codexMCPServerResourceModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		return
+	}
+
+	if config.ServerJSON.IsUnknown() {
+		return
+	}
+	if !config.ServerJSON.IsNull() {
+		set := map[string]bool{
+			"command":      !config.Command.IsNull(),
+			"args":         config.Args != nil,
+			"env":          !config.Env.IsNull(),
+			"cwd":          !config.Cwd.IsNull(),
+			"url":          !config.URL.IsNull(),
+			"http_headers": !config.HTTPHeaders.IsNull(),
+		}
+		conflict := false
+		for _, attr := range codexMCPServerJSONAttributes {
+			if set[attr] {
+				resp.Diagnostics.AddAttributeError(path.Root(attr), "Conflicting MCP server transport", fmt.Sprintf("`%s` cannot be set together with `server_json`.", attr))
+				conflict = true
+			}
+		}
+		if conflict {
+			return
+		}
+		if err := codexMCPServerFromJSON(config.ServerJSON.ValueString(), &config.codexMCPServerWriteModel); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("server_json"), "Invalid MCP server JSON", err.Error())
+			return
+		}
+	}
+
+	validateCodexMCPServer(&resp.Diagnostics, path.Empty(), config.codexMCPServerWriteModel)
+}
+
+func (r *codexMCPServerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan codexMCPServerResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.writeMCPServer(ctx, &resp.Diagnostics, &plan)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexMCPServerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state codexMCPServerResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := state.ID.ValueString()
+	raw, filePath, found, err := r.read(state.codexTableFileModel, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read config.toml", err.Error())
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	writeEnv := boolOrDefault(state.AllowSensitiveEnvWrites, false)
+	server := codexMCPServerFromConfig(id, raw.MCPServers[id], state.Env, writeEnv)
+	if !state.ServerJSON.IsNull() {
+		// The transport stays in server_json. It is only rewritten, from the file, when the
+		// file no longer matches it.
+		var want codexMCPServerWriteModel
+		if err := codexMCPServerFromJSON(state.ServerJSON.ValueString(), &want); err != nil || !codexMCPTransportEqual(ctx, want, server, writeEnv) {
+			b, err := codexMCPServerTransportJSON(ctx, server, writeEnv)
+			if err != nil {
+				resp.Diagnostics.AddError("Failed to encode MCP server JSON", err.Error())
+				return
+			}
+			state.ServerJSON = types.StringValue(b)
+		}
+		server.Command, server.Args, server.Env = types.StringNull(), nil, types.MapNull(types.StringType)
+		server.Cwd, server.URL, server.HTTPHeaders = types.StringNull(), types.StringNull(), types.MapNull(types.StringType)
+	}
+	state.codexMCPServerWriteModel = server
+	state.ResolvedPath = types.StringValue(filePath)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexMCPServerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan codexMCPServerResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.writeMCPServer(ctx, &resp.Diagnostics, &plan)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexMCPServerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state codexMCPServerResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.remove(state.codexTableFileModel, state.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to remove MCP server from config.toml", err.Error())
+	}
+}
+
+func (r *codexMCPServerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: scope:id or custom:path:id (e.g., "home:docs")
+	r.importState(ctx, req, resp, "id")
+}
+
+func (r *codexMCPServerResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.configure(req, resp)
+}
+
+func (r *codexMCPServerResource) writeMCPServer(ctx context.Context, diags *diag.Diagnostics, plan *codexMCPServerResourceModel) {
+	server := plan.codexMCPServerWriteModel
+	if !plan.ServerJSON.IsNull() {
+		if err := codexMCPServerFromJSON(plan.ServerJSON.ValueString(), &server); err != nil {
+			diags.AddAttributeError(path.Root("server_json"), "Invalid MCP server JSON", err.Error())
+			return
+		}
+	}
+
+	writeEnv := boolOrDefault(plan.AllowSensitiveEnvWrites, false)
+	entry, err := codexMCPServerToMap(ctx, diags, server, writeEnv)
+	if err != nil {
+		return
+	}
+	var keep []string
+	if !writeEnv {
+		// env is not ours to write, so leave whatever the file has.
+		keep = append(keep, "env")
+	}
+	filePath, err := r.write(plan.codexTableFileModel, plan.ID.ValueString(), entry, keep...)
+	if err != nil {
+		diags.AddError("Failed to write config.toml", err.Error())
+		return
+	}
+
+	plan.ResolvedPath = types.StringValue(filePath)
+}
+
+// codexMCPServerFromJSON sets the transport attributes of s from an MCP server definition, such
+// as the `json` output of the agentsmith_mcp_stdio and agentsmith_mcp_remote data sources. The
+// transport is detected as in claudeMCPServerFromJSON.
+func codexMCPServerFromJSON(raw string, s *codexMCPServerWriteModel) error {
+	def, err := claudeMCPServerFromJSON(raw)
+	if err != nil {
+		return err
+	}
+	if def["type"] == "sse" {
+		return fmt.Errorf("Codex does not support sse servers; use a stdio or streamable HTTP server")
+	}
+
+	stringsOf := func(v any) []types.String {
+		items, _ := v.([]any)
+		var out []types.String
+		for _, item := range items {
+			out = append(out, types.StringValue(fmt.Sprint(item)))
+		}
+		return out
+	}
+	mapOf := func(v any) types.Map {
+		m, _ := v.(map[string]any)
+		if len(m) == 0 {
+			return types.MapNull(types.StringType)
+		}
+		out := make(map[string]string, len(m))
+		for k, v := range m {
+			out[k] = fmt.Sprint(v)
+		}
+		return mapStringValueOrNull(out)
+	}
+	optString := func(v any) types.String {
+		if str, ok := v.(string); ok {
+			return types.StringValue(str)
+		}
+		return types.StringNull()
+	}
+
+	s.Command = optString(def["command"])
+	s.Args = stringsOf(def["args"])
+	s.Env = mapOf(def["env"])
+	s.URL = optString(def["url"])
+	s.HTTPHeaders = mapOf(def["headers"])
+
+	// cwd is not part of claudeMCPServerFromJSON's dialect.
+	var extra struct {
+		Cwd *string `json:"cwd"`
+	}
+	_ = json.Unmarshal([]byte(raw), &extra)
+	s.Cwd = types.StringNull()
+	if extra.Cwd != nil && def["type"] == "stdio" {
+		s.Cwd = types.StringValue(*extra.Cwd)
+	}
+	return nil
+}
+
+// codexMCPServerTransportJSON encodes the transport attributes of s in the dialect read by
+// codexMCPServerFromJSON. env is only included when writeEnv is set.
+func codexMCPServerTransportJSON(ctx context.Context, s codexMCPServerWriteModel, writeEnv bool) (string, error) {
+	var diags diag.Diagnostics
+	row, err := codexMCPServerToMap(ctx, &diags, s, writeEnv)
+	if err != nil {
+		return "", err
+	}
+	out := map[string]any{"type": "stdio"}
+	if _, ok := row["url"]; ok {
+		out["type"] = "http"
+	}
+	for _, key := range []string{"command", "args", "env", "cwd", "url"} {
+		if v, ok := row[key]; ok {
+			out[key] = v
+		}
+	}
+	if v, ok := row["http_headers"]; ok {
+		out["headers"] = v
+	}
+	b, err := json.Marshal(out)
+	return string(b), err
+}
+
+// codexMCPTransportEqual reports whether a and b have the same transport attributes. env is only
+// compared when writeEnv is set, since it is not written otherwise.
+func codexMCPTransportEqual(ctx context.Context, a, b codexMCPServerWriteModel, writeEnv bool) bool {
+	var diags diag.Diagnostics
+	ra, errA := codexMCPServerToMap(ctx, &diags, a, writeEnv)
+	rb, errB := codexMCPServerToMap(ctx, &diags, b, writeEnv)
+	if errA != nil || errB != nil {
+		return false
+	}
+	transport := func(row map[string]any) map[string]any {
+		out := map[string]any{}
+		for _, k := range codexMCPServerJSONAttributes {
+			if v, ok := row[k]; ok {
+				out[k] = v
+			}
+		}
+		return out
+	}
+	ja, _ := json.Marshal(transport(ra))
+	jb, _ := json.Marshal(transport(rb))
+	return string(ja) == string(jb)
+}
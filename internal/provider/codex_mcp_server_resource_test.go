@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCodexMCPServerResource_basic(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, "config.toml")
+	handWritten := "[mcp_servers.local]\ncommand = \"local-mcp\"\n"
+	if err := os.WriteFile(filePath, []byte(handWritten), 0600); err != nil {
+		t.Fatalf("Failed to write config.toml: %v", err)
+	}
+
+	config := func(toolTimeout int) string {
+		return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+data "agentsmith_mcp_stdio" "fs" {
+  command = "mcp-filesystem-server"
+  args    = ["--safe-mode"]
+  env     = { FS_TOKEN = "secret" }
+}
+
+resource "agentsmith_codex_mcp_server" "fs" {
+  scope       = "custom"
+  path        = %q
+  id          = "filesystem"
+  server_json = data.agentsmith_mcp_stdio.fs.json
+}
+
+resource "agentsmith_codex_mcp_server" "docs" {
+  scope            = "custom"
+  path             = %q
+  id               = "docs"
+  url              = "https://mcp.example.com/mcp"
+  http_headers     = { "X-Team" = "platform" }
+  tool_timeout_sec = %d
+
+  depends_on = [agentsmith_codex_mcp_server.fs]
+}
+`, workDir, filePath, filePath, toolTimeout)
+	}
+
+	fileContains := func(want ...string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			if !strings.HasPrefix(string(data), handWritten) {
+				return fmt.Errorf("expected the local server to be kept, got:\n%s", data)
+			}
+			for _, w := range want {
+				if !strings.Contains(string(data), w) {
+					return fmt.Errorf("expected %q in config.toml, got:\n%s", w, data)
+				}
+			}
+			if strings.Contains(string(data), "secret") {
+				return fmt.Errorf("expected env not to be written, got:\n%s", data)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			if !strings.HasPrefix(string(data), handWritten) || strings.Contains(string(data), "filesystem") || strings.Contains(string(data), "docs") {
+				return fmt.Errorf("expected only the managed servers to be removed, got:\n%s", data)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "agentsmith_codex_mcp_server" "bad" {
+  scope       = "home"
+  id          = "bad"
+  command     = "mcp"
+  server_json = jsonencode({ url = "https://mcp.example.com/sse", transport = "sse" })
+}
+`,
+				ExpectError: regexp.MustCompile("cannot be set together with `server_json`"),
+			},
+			{
+				Config: config(60),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_mcp_server.fs", "resolved_path", filePath),
+					resource.TestCheckNoResourceAttr("agentsmith_codex_mcp_server.fs", "command"),
+					resource.TestCheckResourceAttr("agentsmith_codex_mcp_server.docs", "http_headers.X-Team", "platform"),
+					fileContains("[mcp_servers.filesystem]", "command = 'mcp-filesystem-server'", "[mcp_servers.docs]", "tool_timeout_sec = 60.0"),
+				),
+			},
+			{
+				Config: config(120),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_mcp_server.docs", "tool_timeout_sec", "120"),
+					fileContains("[mcp_servers.filesystem]", "tool_timeout_sec = 120.0"),
+				),
+			},
+			{
+				ResourceName:      "agentsmith_codex_mcp_server.docs",
+				ImportState:       true,
+				ImportStateId:     "custom:" + filePath + ":docs",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccCodexMCPServerResource_keepsEnv(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, "config.toml")
+	if err := os.WriteFile(filePath, []byte("[mcp_servers.fs]\ncommand = \"old\"\n\n[mcp_servers.fs.env]\nFS_TOKEN = \"hand-written\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config.toml: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "agentsmith_codex_mcp_server" "fs" {
+  scope   = "custom"
+  path    = %q
+  id      = "fs"
+  command = "mcp-filesystem-server"
+  env     = { FS_TOKEN = "from-terraform" }
+}
+`, filePath),
+				Check: func(*terraform.State) error {
+					data, err := os.ReadFile(filePath)
+					if err != nil {
+						return err
+					}
+					if !strings.Contains(string(data), `"hand-written"`) || strings.Contains(string(data), "from-terraform") || !strings.Contains(string(data), "'mcp-filesystem-server'") {
+						return fmt.Errorf("expected the hand-written env to be kept, got:\n%s", data)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &codexModelProviderResource{}
+	_ resource.ResourceWithConfigure      = &codexModelProviderResource{}
+	_ resource.ResourceWithImportState    = &codexModelProviderResource{}
+	_ resource.ResourceWithValidateConfig = &codexModelProviderResource{}
+)
+
+func NewCodexModelProviderResource() resource.Resource {
+	return &codexModelProviderResource{codexTable{table: "model_providers", typeName: "agentsmith_codex_model_provider"}}
+}
+
+// codexModelProviderResource owns a single `[model_providers.<id>]` table of a Codex config.toml.
+type codexModelProviderResource struct {
+	codexTable
+}
+
+type codexModelProviderResourceModel struct {
+	codexTableFileModel
+	codexModelProviderWriteModel
+}
+
+func (r *codexModelProviderResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_codex_model_provider"
+}
+
+func (r *codexModelProviderResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attrs := codexModelProviderAttributes()
+	attrs["id"] = schema.StringAttribute{
+		Description:   "The unique identifier for the model provider (e.g., `azure`), used as the key of its `[model_providers.<id>]` table.",
+		Required:      true,
+		PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	addCodexTableFileAttributes(attrs)
+
+	resp.Schema = schema.Schema{
+		Description: "Manages a single `[model_providers.<id>]` table in a Codex CLI `config.toml` file. The rest of the file, including its comments and layout, is left alone, so several modules can contribute model providers to the same file. It can be used alongside an `agentsmith_codex_config` that merges with `preserve_unknown`, as long as that resource does not also declare the provider or list `model_providers` in `owned_tables`.",
+		Attributes:  attrs,
+	}
+}
+
+func (r *codexModelProviderResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config codexModelProviderResourceModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		return
+	}
+
+	var d diag.Diagnostics
+	if entry, err := codexModelProviderToMap(ctx, &d, config.codexModelProviderWriteModel); err == nil {
+		r.validate(&resp.Diagnostics, config.ID.ValueString(), entry)
+	}
+}
+
+func (r *codexModelProviderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan codexModelProviderResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.writeModelProvider(ctx, &resp.Diagnostics, &plan)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexModelProviderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state codexModelProviderResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	raw, filePath, found, err := r.read(state.codexTableFileModel, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read config.toml", err.Error())
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.codexModelProviderWriteModel = codexModelProviderFromConfig(state.ID.ValueString(), raw.ModelProviders[state.ID.ValueString()])
+	state.ResolvedPath = types.StringValue(filePath)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexModelProviderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan codexModelProviderResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.writeModelProvider(ctx, &resp.Diagnostics, &plan)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexModelProviderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state codexModelProviderResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.remove(state.codexTableFileModel, state.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to remove model provider from config.toml", err.Error())
+	}
+}
+
+func (r *codexModelProviderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: scope:id or custom:path:id (e.g., "home:azure")
+	r.importState(ctx, req, resp, "id")
+}
+
+func (r *codexModelProviderResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.configure(req, resp)
+}
+
+func (r *codexModelProviderResource) writeModelProvider(ctx context.Context, diags *diag.Diagnostics, plan *codexModelProviderResourceModel) {
+	entry, err := codexModelProviderToMap(ctx, diags, plan.codexModelProviderWriteModel)
+	if err != nil {
+		return
+	}
+	filePath, err := r.write(plan.codexTableFileModel, plan.ID.ValueString(), entry)
+	if err != nil {
+		diags.AddError("Failed to write config.toml", err.Error())
+		return
+	}
+
+	plan.ResolvedPath = types.StringValue(filePath)
+}
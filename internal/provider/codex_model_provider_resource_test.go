@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCodexModelProviderResource_basic(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, "config.toml")
+	handWritten := "[model_providers.local]\nbase_url = \"http://localhost:11434/v1\"\n"
+	if err := os.WriteFile(filePath, []byte(handWritten), 0600); err != nil {
+		t.Fatalf("Failed to write config.toml: %v", err)
+	}
+
+	config := func(retries int) string {
+		return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_codex_model_provider" "azure" {
+  scope               = "custom"
+  path                = %q
+  id                  = "azure"
+  name                = "Azure OpenAI"
+  base_url            = "https://example.openai.azure.com/openai"
+  env_key             = "AZURE_OPENAI_API_KEY"
+  wire_api            = "responses"
+  query_params        = { "api-version" = "2025-04-01-preview" }
+  request_max_retries = %d
+}
+`, workDir, filePath, retries)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			if !strings.HasPrefix(string(data), handWritten) || strings.Contains(string(data), "azure") {
+				return fmt.Errorf("expected only the azure table to be removed, got:\n%s", data)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_model_provider.azure", "resolved_path", filePath),
+					resource.TestCheckResourceAttr("agentsmith_codex_model_provider.azure", "query_params.api-version", "2025-04-01-preview"),
+					func(*terraform.State) error {
+						data, err := os.ReadFile(filePath)
+						if err != nil {
+							return err
+						}
+						if !strings.HasPrefix(string(data), handWritten) || !strings.Contains(string(data), "[model_providers.azure]") {
+							return fmt.Errorf("expected the azure table next to the local one, got:\n%s", data)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: config(5),
+				Check:  resource.TestCheckResourceAttr("agentsmith_codex_model_provider.azure", "request_max_retries", "5"),
+			},
+			{
+				ResourceName:      "agentsmith_codex_model_provider.azure",
+				ImportState:       true,
+				ImportStateId:     "custom:" + filePath + ":azure",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
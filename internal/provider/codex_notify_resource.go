@@ -0,0 +1,373 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-agentsmith/internal/codex/configio"
+	"terraform-provider-agentsmith/internal/fileio"
+)
+
+var (
+	_ resource.Resource                   = &codexNotifyResource{}
+	_ resource.ResourceWithConfigure      = &codexNotifyResource{}
+	_ resource.ResourceWithImportState    = &codexNotifyResource{}
+	_ resource.ResourceWithValidateConfig = &codexNotifyResource{}
+)
+
+// codexNotifyPriorKey is the private state key holding the notify value that Create replaced,
+// so that Delete can put it back.
+const codexNotifyPriorKey = "prior_notify"
+
+func NewCodexNotifyResource() resource.Resource {
+	return &codexNotifyResource{config: codexTable{typeName: "agentsmith_codex_notify"}}
+}
+
+// codexNotifyResource writes a notifier script next to a Codex config.toml and owns the
+// `notify` key that runs it.
+type codexNotifyResource struct {
+	config codexTable
+}
+
+type codexNotifyResourceModel struct {
+	ID           types.String   `tfsdk:"id"`
+	Scope        types.String   `tfsdk:"scope"`
+	Name         types.String   `tfsdk:"name"`
+	Content      types.String   `tfsdk:"content"`
+	Args         []types.String `tfsdk:"args"`
+	ScriptPath   types.String   `tfsdk:"script_path"`
+	ResolvedPath types.String   `tfsdk:"resolved_path"`
+	fileWriteOptionsModel
+}
+
+func (r *codexNotifyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_codex_notify"
+}
+
+func (r *codexNotifyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a Codex CLI notifier: an executable script written next to `config.toml`, and the `notify` key of that file, which Codex runs with a JSON payload as its last argument when a turn completes. The rest of `config.toml` is left alone. Codex runs a single notifier, so use one of these per file, and do not also set `notify` on an `agentsmith_codex_config` for the same file. If the script or its `notify` entry is removed outside Terraform, the next apply writes both again; on destroy both are removed, and a `notify` value replaced on create is restored.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "A unique identifier for this notifier, composed of the scope and name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"scope": schema.StringAttribute{
+				Description: "The scope of the notifier. Must be either `home` (for `$CODEX_HOME`, by default `~/.codex`) or `project` (for `<workdir>/.codex`).",
+				Required:    true,
+				Validators:  []validator.String{stringOneOf("home", "project")},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The filename of the script (e.g., `notify.sh`), written in the same directory as `config.toml`.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content": schema.StringAttribute{
+				Description: "The content of the script.",
+				Required:    true,
+			},
+			"args": schema.ListAttribute{
+				Description: "Arguments passed to the script ahead of the JSON payload.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"script_path": schema.StringAttribute{
+				Description: "The absolute path of the script, as written to `notify`.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"resolved_path": schema.StringAttribute{
+				Description: "The fully resolved absolute path to the `config.toml` file.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0755")
+}
+
+func (r *codexNotifyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var name types.String
+	if diags := req.Config.GetAttribute(ctx, path.Root("name"), &name); diags.HasError() || name.IsNull() || name.IsUnknown() {
+		return
+	}
+
+	n := name.ValueString()
+	if n == "" || n == "." || n == ".." || strings.ContainsAny(n, `/\`) || n == "config.toml" {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Invalid notifier name", fmt.Sprintf("%q must be a filename other than config.toml, without a directory.", n))
+	}
+}
+
+func (r *codexNotifyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan codexNotifyResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prior := r.writeNotifier(&resp.Diagnostics, &plan)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if prior != nil {
+		data, err := json.Marshal(prior)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to record the replaced notify value", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, codexNotifyPriorKey, data)...)
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexNotifyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state codexNotifyResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configPath, scriptPath, err := r.paths(state.Scope.ValueString(), state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve notifier path", err.Error())
+		return
+	}
+
+	data, err := os.ReadFile(scriptPath)
+	if os.IsNotExist(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read notifier script", err.Error())
+		return
+	}
+
+	existing, err := configio.ReadTOMLMap(configPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read config.toml", err.Error())
+		return
+	}
+	// A notify key removed or repointed by hand means the notifier is gone, so the next plan
+	// writes both halves again.
+	notify, ok := codexNotifyCommand(existing)
+	if !ok || len(notify) == 0 || notify[0] != scriptPath {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// An empty args list stays empty rather than becoming null
+	args := toTFStringListOrNil(notify[1:])
+	if args == nil && state.Args != nil {
+		args = []types.String{}
+	}
+
+	state.Content = types.StringValue(string(data))
+	state.Args = args
+	state.ScriptPath = types.StringValue(scriptPath)
+	state.ResolvedPath = types.StringValue(configPath)
+	state.ID = types.StringValue(codexNotifyID(state.Scope.ValueString(), state.Name.ValueString()))
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexNotifyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan codexNotifyResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.writeNotifier(&resp.Diagnostics, &plan)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexNotifyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state codexNotifyResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configPath, scriptPath, err := r.paths(state.Scope.ValueString(), state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve notifier path", err.Error())
+		return
+	}
+
+	var prior any
+	data, d := req.Private.GetKey(ctx, codexNotifyPriorKey)
+	resp.Diagnostics.Append(d...)
+	if data != nil {
+		if err := json.Unmarshal(data, &prior); err != nil {
+			resp.Diagnostics.AddError("Failed to read the replaced notify value", err.Error())
+			return
+		}
+	}
+
+	if err := r.removeNotify(state, configPath, prior); err != nil {
+		resp.Diagnostics.AddError("Failed to remove notify from config.toml", err.Error())
+		return
+	}
+	if err := os.Remove(scriptPath); err != nil && !os.IsNotExist(err) {
+		resp.Diagnostics.AddError("Failed to delete notifier script", err.Error())
+	}
+}
+
+func (r *codexNotifyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: scope:name (e.g., "home:notify.sh")
+	scope, name, _ := strings.Cut(req.ID, ":")
+	if (scope != "home" && scope != "project") || name == "" {
+		resp.Diagnostics.AddError("Invalid import ID", "Import ID must be in format 'scope:name', where scope is 'home' or 'project' (e.g., 'home:notify.sh')")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("scope"), scope)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), codexNotifyID(scope, name))...)
+}
+
+func (r *codexNotifyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.config.configure(req, resp)
+}
+
+func codexNotifyID(scope, name string) string {
+	return fmt.Sprintf("codex-notify-%s-%s", scope, name)
+}
+
+// paths returns the config.toml of scope and the path of the script name next to it.
+func (r *codexNotifyResource) paths(scope, name string) (string, string, error) {
+	configPath, err := r.config.resolvePath(codexTableFileModel{Scope: types.StringValue(scope)})
+	if err != nil {
+		return "", "", err
+	}
+	return configPath, filepath.Join(filepath.Dir(configPath), name), nil
+}
+
+// configFile returns the options config.toml is written with. file_mode applies to the script;
+// config.toml is written with the usual Codex mode.
+func (m codexNotifyResourceModel) configFile() codexTableFileModel {
+	return codexTableFileModel{
+		CreateDirectories: m.CreateDirectories,
+		BackupOnWrite:     types.BoolValue(boolOrDefault(m.BackupOnWrite, false)),
+	}
+}
+
+// writeNotifier writes the script and points notify at it. It returns the notify value it
+// replaced, or nil when notify was unset or already ran this script.
+func (r *codexNotifyResource) writeNotifier(diags *diag.Diagnostics, plan *codexNotifyResourceModel) any {
+	configPath, scriptPath, err := r.paths(plan.Scope.ValueString(), plan.Name.ValueString())
+	if err != nil {
+		diags.AddError("Failed to resolve notifier path", err.Error())
+		return nil
+	}
+
+	// Write the script first, so notify never points at a missing file
+	if err := plan.writeScript(scriptPath, []byte(plan.Content.ValueString()), true); err != nil {
+		diags.AddError("Failed to write notifier script", err.Error())
+		return nil
+	}
+
+	notify := append([]string{scriptPath}, stringsFromList(plan.Args)...)
+	prior, err := r.writeNotify(*plan, configPath, notify)
+	if err != nil {
+		diags.AddError("Failed to write config.toml", err.Error())
+		return nil
+	}
+
+	plan.ScriptPath = types.StringValue(scriptPath)
+	plan.ResolvedPath = types.StringValue(configPath)
+	plan.ID = types.StringValue(codexNotifyID(plan.Scope.ValueString(), plan.Name.ValueString()))
+	return prior
+}
+
+// writeNotify sets notify in configPath, recording it for the notifier's owner. It returns the
+// notify value it replaced, unless that value already ran the same script.
+func (r *codexNotifyResource) writeNotify(m codexNotifyResourceModel, configPath string, notify []string) (any, error) {
+	defer fileio.Lock(configPath)()
+
+	existing, err := configio.ReadTOMLMap(configPath)
+	if err != nil {
+		return nil, err
+	}
+	prior := existing["notify"]
+	if command, ok := codexNotifyCommand(existing); ok && len(command) > 0 && command[0] == notify[0] {
+		prior = nil
+	}
+	merged := configio.MergePreserveUnknown(existing, map[string]any{"notify": notify}, r.config.owner(m.Name.ValueString()))
+	return prior, r.config.edit(m.configFile(), configPath, merged)
+}
+
+// removeNotify removes notify from configPath, along with anything else recorded for the
+// notifier's owner, and restores prior when it is set.
+func (r *codexNotifyResource) removeNotify(m codexNotifyResourceModel, configPath string, prior any) error {
+	defer fileio.Lock(configPath)()
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil
+	}
+	existing, err := configio.ReadTOMLMap(configPath)
+	if err != nil {
+		return err
+	}
+	remaining := configio.RemoveOwned(existing, r.config.owner(m.Name.ValueString()))
+	if _, ok := remaining["notify"]; !ok && prior != nil {
+		remaining["notify"] = prior
+	}
+	return r.config.edit(m.configFile(), configPath, remaining)
+}
+
+// codexNotifyCommand returns the notify key of a config.toml, and whether it is a list of
+// strings.
+func codexNotifyCommand(m map[string]any) ([]string, bool) {
+	items, ok := m["notify"].([]any)
+	if !ok {
+		return nil, false
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, s)
+	}
+	return out, true
+}
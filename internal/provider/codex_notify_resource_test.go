@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCodexNotifyResource_project(t *testing.T) {
+	workDir := t.TempDir()
+	configPath := filepath.Join(workDir, ".codex", "config.toml")
+	scriptPath := filepath.Join(workDir, ".codex", "notify.sh")
+	handWritten := "# Team defaults\nmodel = \"o3\"\n"
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("Failed to create .codex: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte(handWritten), 0600); err != nil {
+		t.Fatalf("Failed to write config.toml: %v", err)
+	}
+
+	config := func(args string) string {
+		return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_codex_notify" "test" {
+  scope   = "project"
+  name    = "notify.sh"
+  args    = %s
+  content = "#!/bin/sh\necho \"$@\" >> /tmp/codex-notify.log\n"
+}
+`, workDir, args)
+	}
+
+	notifyWired := func(want string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			info, err := os.Stat(scriptPath)
+			if err != nil {
+				return err
+			}
+			if info.Mode()&0100 == 0 {
+				return fmt.Errorf("expected the script to be executable, got mode %v", info.Mode())
+			}
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				return err
+			}
+			if !strings.HasPrefix(string(data), handWritten) || !strings.Contains(string(data), want) {
+				return fmt.Errorf("expected %q next to the hand-written settings, got:\n%s", want, data)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if _, err := os.Stat(scriptPath); !os.IsNotExist(err) {
+				return fmt.Errorf("expected the script to be removed, got: %v", err)
+			}
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				return err
+			}
+			if !strings.HasPrefix(string(data), handWritten) || strings.Contains(string(data), "notify =") {
+				return fmt.Errorf("expected only notify to be removed, got:\n%s", data)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "agentsmith_codex_notify" "bad" {
+  scope   = "home"
+  name    = "../notify.sh"
+  content = "#!/bin/sh\n"
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid notifier name"),
+			},
+			{
+				Config: config("null"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_notify.test", "script_path", scriptPath),
+					resource.TestCheckResourceAttr("agentsmith_codex_notify.test", "resolved_path", configPath),
+					notifyWired(fmt.Sprintf("notify = ['%s']", scriptPath)),
+				),
+			},
+			// An empty args list is kept rather than refreshed to null
+			{
+				Config: config("[]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_notify.test", "args.#", "0"),
+					notifyWired(fmt.Sprintf("notify = ['%s']", scriptPath)),
+				),
+			},
+			{
+				Config: config(`["--channel", "builds"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_notify.test", "args.#", "2"),
+					notifyWired(fmt.Sprintf("notify = ['%s', '--channel', 'builds']", scriptPath)),
+				),
+			},
+			{
+				// notify removed by hand is wired up again
+				PreConfig: func() {
+					if err := os.WriteFile(configPath, []byte(handWritten), 0600); err != nil {
+						t.Fatalf("Failed to write config.toml: %v", err)
+					}
+				},
+				Config: config(`["--channel", "builds"]`),
+				Check:  notifyWired("'--channel', 'builds'"),
+			},
+			{
+				ResourceName:      "agentsmith_codex_notify.test",
+				ImportState:       true,
+				ImportStateId:     "project:notify.sh",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccCodexNotifyResource_restoresPriorNotify(t *testing.T) {
+	workDir := t.TempDir()
+	configPath := filepath.Join(workDir, ".codex", "config.toml")
+	handWritten := "model = \"o3\"\nnotify = ['/usr/local/bin/desktop-notify', '--quiet']\n"
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("Failed to create .codex: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte(handWritten), 0600); err != nil {
+		t.Fatalf("Failed to write config.toml: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				return err
+			}
+			if string(data) != handWritten {
+				return fmt.Errorf("expected the replaced notify to be restored, got:\n%s", data)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_codex_notify" "test" {
+  scope   = "project"
+  name    = "notify.sh"
+  content = "#!/bin/sh\n"
+}
+`, workDir),
+				Check: func(*terraform.State) error {
+					data, err := os.ReadFile(configPath)
+					if err != nil {
+						return err
+					}
+					if strings.Contains(string(data), "desktop-notify") {
+						return fmt.Errorf("expected notify to run the script, got:\n%s", data)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &codexProfileResource{}
+	_ resource.ResourceWithConfigure      = &codexProfileResource{}
+	_ resource.ResourceWithImportState    = &codexProfileResource{}
+	_ resource.ResourceWithValidateConfig = &codexProfileResource{}
+)
+
+func NewCodexProfileResource() resource.Resource {
+	return &codexProfileResource{codexTable{table: "profiles", typeName: "agentsmith_codex_profile"}}
+}
+
+// codexProfileResource owns a single `[profiles.<name>]` table of a Codex config.toml.
+type codexProfileResource struct {
+	codexTable
+}
+
+type codexProfileResourceModel struct {
+	ID types.String `tfsdk:"id"`
+	codexTableFileModel
+	codexProfileWriteModel
+}
+
+func (r *codexProfileResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_codex_profile"
+}
+
+func (r *codexProfileResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attrs := codexProfileAttributes()
+	attrs["id"] = schema.StringAttribute{
+		Description:   "A unique identifier for this profile, composed of the resolved path and the profile name.",
+		Computed:      true,
+		PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+	}
+	attrs["name"] = schema.StringAttribute{
+		Description:   "The name of the profile, used as the key of its `[profiles.<name>]` table.",
+		Required:      true,
+		PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	addCodexTableFileAttributes(attrs)
+
+	resp.Schema = schema.Schema{
+		Description: "Manages a single `[profiles.<name>]` table in a Codex CLI `config.toml` file. The rest of the file, including its comments and layout, is left alone, so several modules can contribute profiles to the same file. It can be used alongside an `agentsmith_codex_config` that merges with `preserve_unknown`, as long as that resource does not also declare the profile or list `profiles` in `owned_tables`.",
+		Attributes:  attrs,
+		Blocks:      codexProfileBlocks(),
+	}
+}
+
+func (r *codexProfileResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config codexProfileResourceModel
+	// Blocks that are not known yet cannot be decoded; they are checked again on apply.
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		return
+	}
+
+	var d diag.Diagnostics
+	if entry, err := codexProfileToMap(ctx, &d, config.codexProfileWriteModel); err == nil {
+		r.validate(&resp.Diagnostics, config.Name.ValueString(), entry)
+	}
+}
+
+func (r *codexProfileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan codexProfileResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.writeProfile(ctx, &resp.Diagnostics, &plan)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexProfileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state codexProfileResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	raw, filePath, found, err := r.read(state.codexTableFileModel, state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read config.toml", err.Error())
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.codexProfileWriteModel = codexProfileFromConfig(state.Name.ValueString(), raw.Profiles[state.Name.ValueString()])
+	state.ResolvedPath = types.StringValue(filePath)
+	state.ID = types.StringValue(filePath + "#profiles." + state.Name.ValueString())
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexProfileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan codexProfileResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.writeProfile(ctx, &resp.Diagnostics, &plan)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *codexProfileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state codexProfileResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.remove(state.codexTableFileModel, state.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to remove profile from config.toml", err.Error())
+	}
+}
+
+func (r *codexProfileResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: scope:name or custom:path:name (e.g., "home:deep")
+	r.importState(ctx, req, resp, "name")
+}
+
+func (r *codexProfileResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.configure(req, resp)
+}
+
+func (r *codexProfileResource) writeProfile(ctx context.Context, diags *diag.Diagnostics, plan *codexProfileResourceModel) {
+	entry, err := codexProfileToMap(ctx, diags, plan.codexProfileWriteModel)
+	if err != nil {
+		return
+	}
+	filePath, err := r.write(plan.codexTableFileModel, plan.Name.ValueString(), entry)
+	if err != nil {
+		diags.AddError("Failed to write config.toml", err.Error())
+		return
+	}
+
+	plan.ResolvedPath = types.StringValue(filePath)
+	plan.ID = types.StringValue(filePath + "#profiles." + plan.Name.ValueString())
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccCodexProfileResource_alongsideConfig(t *testing.T) {
+	workDir := t.TempDir()
+	filePath := filepath.Join(workDir, "config.toml")
+	if err := os.WriteFile(filePath, []byte("# Hand-written\nmodel = \"o3\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config.toml: %v", err)
+	}
+
+	config := func(effort string) string {
+		return fmt.Sprintf(`
+provider "agentsmith" {
+  workdir = %q
+}
+
+resource "agentsmith_codex_config" "test" {
+  scope           = "custom"
+  path            = %q
+  approval_policy = "on-request"
+  profile         = agentsmith_codex_profile.deep.name
+}
+
+resource "agentsmith_codex_profile" "deep" {
+  scope                  = "custom"
+  path                   = %q
+  name                   = "deep"
+  model                  = "o3"
+  model_reasoning_effort = %q
+
+  shell_environment_policy {
+    inherit = "core"
+  }
+}
+`, workDir, filePath, filePath, effort)
+	}
+
+	readFile := func() string {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("Failed to read config.toml: %v", err)
+		}
+		return string(data)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if content := readFile(); strings.Contains(content, "[profiles.deep]") || !strings.HasPrefix(content, "# Hand-written\nmodel = \"o3\"\n") {
+				return fmt.Errorf("expected only the managed keys to be removed, got:\n%s", content)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      config("extreme"),
+				ExpectError: regexp.MustCompile(`"extreme" is not a valid value`),
+			},
+			{
+				Config: config("high"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_profile.deep", "resolved_path", filePath),
+					resource.TestCheckResourceAttr("agentsmith_codex_profile.deep", "id", filePath+"#profiles.deep"),
+					func(*terraform.State) error {
+						content := readFile()
+						for _, want := range []string{"# Hand-written\n", "approval_policy = 'on-request'", "profile = 'deep'", "[profiles.deep]", "model_reasoning_effort = 'high'", "[profiles.deep.shell_environment_policy]"} {
+							if !strings.Contains(content, want) {
+								return fmt.Errorf("expected %q in config.toml, got:\n%s", want, content)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			// Edits made outside Terraform show up as drift
+			{
+				PreConfig: func() {
+					content := strings.Replace(readFile(), "model_reasoning_effort = 'high'", "model_reasoning_effort = 'low'", 1)
+					if err := os.WriteFile(filePath, []byte(content), 0600); err != nil {
+						t.Fatalf("Failed to write config.toml: %v", err)
+					}
+				},
+				Config:             config("high"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config("medium"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("agentsmith_codex_profile.deep", "model_reasoning_effort", "medium"),
+					resource.TestCheckResourceAttr("agentsmith_codex_config.test", "approval_policy", "on-request"),
+				),
+			},
+			{
+				ResourceName:      "agentsmith_codex_profile.deep",
+				ImportState:       true,
+				ImportStateId:     "custom:" + filePath + ":deep",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
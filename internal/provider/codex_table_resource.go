@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	toml "github.com/pelletier/go-toml/v2"
+
+	"terraform-provider-agentsmith/internal/codex/configio"
+	"terraform-provider-agentsmith/internal/fileio"
+)
+
+// codexTableFileModel holds the attributes that locate and write the config.toml of resources
+// that own a single table in it.
+type codexTableFileModel struct {
+	Scope             types.String `tfsdk:"scope"`
+	Path              types.String `tfsdk:"path"`
+	ResolvedPath      types.String `tfsdk:"resolved_path"`
+	CreateDirectories types.Bool   `tfsdk:"create_directories"`
+	FileMode          types.String `tfsdk:"file_mode"`
+	BackupOnWrite     types.Bool   `tfsdk:"backup_on_write"`
+}
+
+func addCodexTableFileAttributes(attrs map[string]schema.Attribute) {
+	attrs["scope"] = schema.StringAttribute{
+		Description:   "The scope of the configuration file. Must be one of `home` (~/.codex/config.toml), `project` (<workdir>/.codex/config.toml), or `custom`.",
+		Required:      true,
+		Validators:    []validator.String{stringOneOf("home", "project", "custom")},
+		PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	attrs["path"] = schema.StringAttribute{
+		Description:   "The absolute path to the `config.toml` file. Required only when `scope` is `custom`.",
+		Optional:      true,
+		PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	attrs["resolved_path"] = schema.StringAttribute{
+		Description:   "The fully resolved absolute path to the `config.toml` file.",
+		Computed:      true,
+		PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+	}
+	attrs["create_directories"] = schema.BoolAttribute{Description: "If true, creates parent directories for the config file if they do not exist. Defaults to `true`.", Optional: true}
+	attrs["file_mode"] = schema.StringAttribute{Description: "The file mode to set on the `config.toml` file, in octal format (e.g., `0600`). Defaults to the mode of the existing file, or `0600` for a new file.", Optional: true, Validators: []validator.String{fileMode()}}
+	attrs["backup_on_write"] = schema.BoolAttribute{Description: "If true, creates a `.bak` file before writing changes. Defaults to `true`.", Optional: true}
+}
+
+// codexTable writes a single `[<table>.<key>]` table of a Codex config.toml, leaving the rest of
+// the file, its comments and its layout alone. The keys written are recorded in the
+// __agentsmith marker table under an owner per resource, so the table is safe to use alongside
+// an agentsmith_codex_config that merges with preserve_unknown.
+type codexTable struct {
+	client   *FileClient
+	table    string
+	typeName string
+}
+
+func (t *codexTable) configure(req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*FileClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *FileClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	t.client = client
+}
+
+func (t *codexTable) owner(key string) string {
+	return t.typeName + "." + key
+}
+
+func (t *codexTable) resolvePath(m codexTableFileModel) (string, error) {
+	workdir := ""
+	if t.client != nil {
+		workdir = t.client.workDir
+	}
+	return configio.ResolvePath(m.Scope.ValueString(), workdir, m.Path.ValueString())
+}
+
+// validate reports values of entry outside the documented sets at the attributes that set them.
+// References to other tables are not checked, since another resource may define them.
+func (t *codexTable) validate(diags *diag.Diagnostics, key string, entry map[string]any) {
+	for _, issue := range configio.Validate(map[string]any{t.table: map[string]any{key: entry}}) {
+		if issue.Reference || len(issue.Path) < 3 {
+			continue
+		}
+		diags.AddAttributeError(codexNestedAttributePath(path.Empty(), issue.Path[2:]), "Invalid Codex configuration", issue.Message)
+	}
+}
+
+// write replaces the table at key with entry, keeping the values of the keys in keep that entry
+// does not set. It returns the resolved path of the file.
+func (t *codexTable) write(m codexTableFileModel, key string, entry map[string]any, keep ...string) (string, error) {
+	filePath, err := t.resolvePath(m)
+	if err != nil {
+		return "", err
+	}
+	defer fileio.Lock(filePath)()
+
+	existing, err := configio.ReadTOMLMap(filePath)
+	if err != nil {
+		return filePath, err
+	}
+	if prior, ok := configio.LookupKeyPath(existing, []string{t.table, key}); ok && len(keep) > 0 {
+		entry = maps.Clone(entry)
+		for _, k := range keep {
+			if v, ok := prior.(map[string]any)[k]; ok {
+				if _, set := entry[k]; !set {
+					entry[k] = v
+				}
+			}
+		}
+	}
+	desired := map[string]any{t.table: map[string]any{key: entry}}
+	merged := configio.MergePreserveUnknown(existing, desired, t.owner(key), t.table+"."+key)
+	return filePath, t.edit(m, filePath, merged)
+}
+
+// read decodes the table at key, and reports whether the file has one.
+func (t *codexTable) read(m codexTableFileModel, key string) (rawCodexConfig, string, bool, error) {
+	var raw rawCodexConfig
+	filePath, err := t.resolvePath(m)
+	if err != nil {
+		return raw, "", false, err
+	}
+	existing, err := configio.ReadTOMLMap(filePath)
+	if err != nil {
+		return raw, filePath, false, err
+	}
+	entry, ok := configio.LookupKeyPath(existing, []string{t.table, key})
+	if !ok {
+		return raw, filePath, false, nil
+	}
+	b, err := configio.MarshalTOML(map[string]any{t.table: map[string]any{key: entry}})
+	if err != nil {
+		return raw, filePath, true, err
+	}
+	return raw, filePath, true, toml.Unmarshal(b, &raw)
+}
+
+// remove deletes the table at key, along with anything else recorded for its owner.
+func (t *codexTable) remove(m codexTableFileModel, key string) error {
+	filePath, err := t.resolvePath(m)
+	if err != nil {
+		return err
+	}
+	defer fileio.Lock(filePath)()
+
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		return nil
+	}
+	existing, err := configio.ReadTOMLMap(filePath)
+	if err != nil {
+		return err
+	}
+	remaining := configio.DeleteKeyPath(configio.RemoveOwned(existing, t.owner(key)), []string{t.table, key})
+	return t.edit(m, filePath, remaining)
+}
+
+// edit writes desired to filePath, editing the existing file in place.
+func (t *codexTable) edit(m codexTableFileModel, filePath string, desired map[string]any) error {
+	original, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	b, err := configio.EditTOML(original, desired)
+	if err != nil {
+		return err
+	}
+	return configio.AtomicWrite(filePath, b, m.FileMode.ValueString(), boolOrDefault(m.BackupOnWrite, true), boolOrDefault(m.CreateDirectories, true), "")
+}
+
+// importState parses an import ID of the form `<scope>:<key>`, or `custom:<path>:<key>`, into
+// the file attributes and keyAttr.
+func (t *codexTable) importState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse, keyAttr string) {
+	scope, rest, _ := strings.Cut(req.ID, ":")
+	filePath := ""
+	key := rest
+	if scope == "custom" {
+		i := strings.LastIndex(rest, ":")
+		if i < 0 {
+			resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("Import ID must be in format 'custom:<path>:<%s>'", keyAttr))
+			return
+		}
+		filePath, key = rest[:i], rest[i+1:]
+	}
+	if (scope != "home" && scope != "project" && scope != "custom") || key == "" {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("Import ID must be in format '<scope>:<%s>' or 'custom:<path>:<%s>', where scope is 'home' or 'project'", keyAttr, keyAttr))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("scope"), scope)...)
+	if filePath != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("path"), filePath)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(keyAttr), key)...)
+}
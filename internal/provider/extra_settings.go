@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// extraSettingsAttribute returns the `extra_settings` attribute, which carries settings that have
+// no schema attribute yet. document names the file the settings are merged into.
+func extraSettingsAttribute(document string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: fmt.Sprintf("A JSON object, usually built with `jsonencode`, deep-merged into %s. Use it for settings that have no attribute in this resource yet. Keys set here must not also be set by another attribute.", document),
+		Optional:    true,
+		Validators:  []validator.String{jsonObject()},
+	}
+}
+
+// parseExtraSettings decodes extra_settings. Null and unknown values yield a nil map. Whole
+// numbers are decoded as int64 so they are written back as integers.
+func parseExtraSettings(v types.String) (map[string]interface{}, error) {
+	if v.IsNull() || v.IsUnknown() {
+		return nil, nil
+	}
+	dec := json.NewDecoder(strings.NewReader(v.ValueString()))
+	dec.UseNumber()
+	var extra map[string]interface{}
+	if err := dec.Decode(&extra); err != nil {
+		return nil, fmt.Errorf("extra_settings is not valid JSON: %w", err)
+	}
+	if extra == nil {
+		return nil, fmt.Errorf("extra_settings must be a JSON object")
+	}
+	return normalizeJSONNumbers(extra).(map[string]interface{}), nil
+}
+
+func normalizeJSONNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalizeJSONNumbers(e)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = normalizeJSONNumbers(e)
+		}
+		return t
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// mergeExtraSettings deep-merges extra_settings into the document rendered from the typed
+// attributes. Keys set by both are rejected; validateExtraSettings reports them at plan time
+// when the configuration is known.
+func mergeExtraSettings(typed map[string]interface{}, extra types.String) (map[string]interface{}, error) {
+	extraMap, err := parseExtraSettings(extra)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts := extraSettingsConflicts(extraMap, typed); len(conflicts) > 0 {
+		return nil, fmt.Errorf("extra_settings sets keys that are managed by other attributes: %s", strings.Join(conflicts, ", "))
+	}
+	return deepMergeJSON(extraMap, typed), nil
+}
+
+// deepMergeJSON returns a copy of base with overlay merged into it. Objects present on both sides
+// are merged recursively; any other value in overlay replaces the one in base.
+func deepMergeJSON(base, overlay map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base)+len(overlay))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range overlay {
+		if ov, ok := v.(map[string]interface{}); ok {
+			if bv, ok := out[k].(map[string]interface{}); ok {
+				out[k] = deepMergeJSON(bv, ov)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// extraSettingsConflicts returns the dotted paths set by both extra and typed, sorted.
+func extraSettingsConflicts(extra, typed map[string]interface{}) []string {
+	var conflicts []string
+	var walk func(prefix string, e, t map[string]interface{})
+	walk = func(prefix string, e, t map[string]interface{}) {
+		for k, ev := range e {
+			tv, ok := t[k]
+			if !ok {
+				continue
+			}
+			em, eok := ev.(map[string]interface{})
+			tm, tok := tv.(map[string]interface{})
+			if eok && tok {
+				walk(prefix+k+".", em, tm)
+				continue
+			}
+			conflicts = append(conflicts, prefix+k)
+		}
+	}
+	walk("", extra, typed)
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// validateExtraSettings reports keys in extra_settings that are also set by typed attributes.
+// typed is the document rendered from the typed attributes alone.
+func validateExtraSettings(diags *diag.Diagnostics, extra types.String, typed map[string]interface{}) {
+	extraMap, err := parseExtraSettings(extra)
+	if err != nil || extraMap == nil {
+		return
+	}
+	if conflicts := extraSettingsConflicts(extraMap, typed); len(conflicts) > 0 {
+		diags.AddAttributeError(
+			path.Root("extra_settings"),
+			"Conflicting extra_settings",
+			fmt.Sprintf("extra_settings sets keys that are managed by other attributes: %s. Set them through their attributes or remove them from extra_settings.", strings.Join(conflicts, ", ")),
+		)
+	}
+}
+
+// stripExtraSettings returns a copy of doc without the values owned by extra, dropping objects
+// that only held such values. It is used before refreshing typed attributes so that they do not
+// pick up keys managed through extra_settings.
+func stripExtraSettings(doc, extra map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		ev, owned := extra[k]
+		if !owned {
+			out[k] = v
+			continue
+		}
+		em, eok := ev.(map[string]interface{})
+		dm, dok := v.(map[string]interface{})
+		if eok && dok {
+			if rest := stripExtraSettings(dm, em); len(rest) > 0 {
+				out[k] = rest
+			}
+		}
+	}
+	return out
+}
+
+// refreshExtraSettings projects doc onto the keys of the prior extra_settings so that Read
+// detects drift in them. The prior value is kept verbatim when it is semantically unchanged.
+func refreshExtraSettings(prior types.String, doc map[string]interface{}) types.String {
+	priorMap, err := parseExtraSettings(prior)
+	if err != nil || priorMap == nil {
+		return prior
+	}
+
+	current := projectJSON(priorMap, doc)
+	if jsonEqual(priorMap, current) {
+		return prior
+	}
+	b, err := json.Marshal(current)
+	if err != nil {
+		return prior
+	}
+	return types.StringValue(string(b))
+}
+
+// projectJSON returns the values of doc at the keys present in shape, descending into objects.
+func projectJSON(shape, doc map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for k, sv := range shape {
+		dv, ok := doc[k]
+		if !ok {
+			continue
+		}
+		sm, sok := sv.(map[string]interface{})
+		dm, dok := dv.(map[string]interface{})
+		if sok && dok {
+			out[k] = projectJSON(sm, dm)
+			continue
+		}
+		out[k] = dv
+	}
+	return out
+}
+
+// jsonEqual reports whether a and b encode to the same JSON.
+func jsonEqual(a, b interface{}) bool {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ab, bb)
+}
+
+// extraSettingsNullPaths returns the dotted paths of null values in extra, sorted. Formats
+// without a null value, such as TOML, reject them.
+func extraSettingsNullPaths(extra map[string]interface{}) []string {
+	var paths []string
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for k, v := range m {
+			switch t := v.(type) {
+			case nil:
+				paths = append(paths, prefix+k)
+			case map[string]interface{}:
+				walk(prefix+k+".", t)
+			}
+		}
+	}
+	walk("", extra)
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseExtraSettings(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       types.String
+		expected    map[string]interface{}
+		expectError bool
+	}{
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{
+			name:     "numbers",
+			value:    types.StringValue(`{"cleanup": 30, "ratio": 0.5, "nested": {"list": [1, 2]}}`),
+			expected: map[string]interface{}{"cleanup": int64(30), "ratio": 0.5, "nested": map[string]interface{}{"list": []interface{}{int64(1), int64(2)}}},
+		},
+		{name: "array", value: types.StringValue(`[1, 2]`), expectError: true},
+		{name: "json_null", value: types.StringValue(`null`), expectError: true},
+		{name: "invalid_json", value: types.StringValue(`{"a":`), expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extra, err := parseExtraSettings(tc.value)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(extra, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, extra)
+			}
+		})
+	}
+}
+
+func TestMergeExtraSettings(t *testing.T) {
+	typed := map[string]interface{}{
+		"model":       "opus",
+		"permissions": map[string]interface{}{"allow": []string{"Bash(npm test)"}},
+	}
+	extra := types.StringValue(`{"spinnerTipsEnabled": false, "permissions": {"additionalDirectories": ["../docs"]}, "sandbox": {"enabled": true}}`)
+
+	merged, err := mergeExtraSettings(typed, extra)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"model":"opus","permissions":{"additionalDirectories":["../docs"],"allow":["Bash(npm test)"]},"sandbox":{"enabled":true},"spinnerTipsEnabled":false}`
+	if b, _ := json.Marshal(merged); string(b) != expected {
+		t.Errorf("Expected %s, got %s", expected, b)
+	}
+	if _, ok := typed["sandbox"]; ok {
+		t.Error("Expected typed settings not to be modified")
+	}
+
+	// Keys set by both are rejected
+	if _, err := mergeExtraSettings(typed, types.StringValue(`{"permissions": {"allow": ["Read"]}}`)); err == nil {
+		t.Error("Expected error for conflicting key")
+	}
+}
+
+func TestExtraSettingsConflicts(t *testing.T) {
+	typed := map[string]interface{}{
+		"model": "opus",
+		"env":   map[string]interface{}{"FOO": "1"},
+		"permissions": map[string]interface{}{
+			"allow": []string{"Read"},
+		},
+	}
+	extra := map[string]interface{}{
+		"model":       "sonnet",
+		"env":         map[string]interface{}{"BAR": "2"},
+		"permissions": map[string]interface{}{"allow": []interface{}{"Edit"}, "defaultMode": "plan"},
+		"statusLine":  map[string]interface{}{"type": "command"},
+	}
+
+	conflicts := extraSettingsConflicts(extra, typed)
+	expected := []string{"model", "permissions.allow"}
+	if !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("Expected conflicts %v, got %v", expected, conflicts)
+	}
+
+	var diags diag.Diagnostics
+	b, _ := json.Marshal(extra)
+	validateExtraSettings(&diags, types.StringValue(string(b)), typed)
+	if diags.ErrorsCount() != 1 {
+		t.Errorf("Expected a single conflict error, got %v", diags.Errors())
+	}
+}
+
+func TestStripExtraSettings(t *testing.T) {
+	doc := map[string]interface{}{
+		"model":       "opus",
+		"sandbox":     map[string]interface{}{"enabled": true},
+		"permissions": map[string]interface{}{"allow": []interface{}{"Read"}, "additionalDirectories": []interface{}{"../docs"}},
+		"statusLine":  map[string]interface{}{"padding": 1.0},
+	}
+	extra := map[string]interface{}{
+		"sandbox":     map[string]interface{}{"enabled": true},
+		"permissions": map[string]interface{}{"additionalDirectories": []interface{}{"../docs"}},
+		"statusLine":  map[string]interface{}{"padding": int64(1)},
+	}
+
+	stripped := stripExtraSettings(doc, extra)
+	expected := map[string]interface{}{
+		"model":       "opus",
+		"permissions": map[string]interface{}{"allow": []interface{}{"Read"}},
+	}
+	if !reflect.DeepEqual(stripped, expected) {
+		t.Errorf("Expected %v, got %v", expected, stripped)
+	}
+	if _, ok := doc["sandbox"]; !ok {
+		t.Error("Expected the document not to be modified")
+	}
+}
+
+func TestRefreshExtraSettings(t *testing.T) {
+	prior := types.StringValue(`{
+  "sandbox": {"enabled": true},
+  "cleanup": 30
+}`)
+
+	testCases := []struct {
+		name     string
+		doc      map[string]interface{}
+		expected types.String
+	}{
+		{
+			name:     "unchanged_keeps_prior_formatting",
+			doc:      map[string]interface{}{"model": "opus", "cleanup": 30.0, "sandbox": map[string]interface{}{"enabled": true}},
+			expected: prior,
+		},
+		{
+			name:     "changed_value",
+			doc:      map[string]interface{}{"cleanup": 30.0, "sandbox": map[string]interface{}{"enabled": false, "other": 1.0}},
+			expected: types.StringValue(`{"cleanup":30,"sandbox":{"enabled":false}}`),
+		},
+		{
+			name:     "removed_key",
+			doc:      map[string]interface{}{"sandbox": map[string]interface{}{"enabled": true}},
+			expected: types.StringValue(`{"sandbox":{"enabled":true}}`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			refreshed := refreshExtraSettings(prior, tc.doc)
+			if !refreshed.Equal(tc.expected) {
+				t.Errorf("Expected %s, got %s", tc.expected, refreshed)
+			}
+		})
+	}
+
+	if refreshed := refreshExtraSettings(types.StringNull(), map[string]interface{}{"a": 1.0}); !refreshed.IsNull() {
+		t.Errorf("Expected null extra_settings to stay null, got %s", refreshed)
+	}
+}
+
+func TestExtraSettingsNullPaths(t *testing.T) {
+	extra := map[string]interface{}{
+		"a":   nil,
+		"b":   map[string]interface{}{"c": nil, "d": int64(1)},
+		"e":   []interface{}{nil},
+		"tui": map[string]interface{}{},
+	}
+
+	expected := []string{"a", "b.c"}
+	if paths := extraSettingsNullPaths(extra); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-agentsmith/internal/fileio"
+)
+
+// fileWriteOptionsModel holds the write options shared by every file-backed resource. It is
+// embedded in the resource models; the matching attributes come from fileWriteOptionsAttributes.
+type fileWriteOptionsModel struct {
+	CreateDirectories types.Bool   `tfsdk:"create_directories"`
+	FileMode          types.String `tfsdk:"file_mode"`
+	BackupOnWrite     types.Bool   `tfsdk:"backup_on_write"`
+}
+
+// addFileWriteOptionsAttributes adds the create_directories, file_mode and backup_on_write
+// attributes to attrs. Defaults are applied when writing so that imported resources, which have
+// no configuration to read them from, verify cleanly.
+func addFileWriteOptionsAttributes(attrs map[string]schema.Attribute, defaultMode string) {
+	attrs["create_directories"] = schema.BoolAttribute{
+		Description: "If true, creates parent directories for the file if they do not exist. Defaults to `true`.",
+		Optional:    true,
+	}
+	attrs["file_mode"] = schema.StringAttribute{
+		Description: "The file mode to set on the file, in octal format (e.g., `0600`). Defaults to the mode of the existing file, or `" + defaultMode + "` for a new file.",
+		Optional:    true,
+		Validators:  []validator.String{fileMode()},
+	}
+	attrs["backup_on_write"] = schema.BoolAttribute{
+		Description: "If true, copies the existing file to `<file>.bak` before writing changes. Defaults to `false`.",
+		Optional:    true,
+	}
+}
+
+// writeOptions resolves the configured options. When file_mode is unset, an existing file keeps
+// its mode and new files get defaultMode.
+func (m fileWriteOptionsModel) writeOptions(defaultMode os.FileMode) (fileio.WriteOptions, error) {
+	opts := fileio.WriteOptions{
+		Mode:       defaultMode,
+		KeepMode:   true,
+		Backup:     boolOrDefault(m.BackupOnWrite, false),
+		CreateDirs: boolOrDefault(m.CreateDirectories, true),
+	}
+	if !m.FileMode.IsNull() && !m.FileMode.IsUnknown() && m.FileMode.ValueString() != "" {
+		mode, err := fileio.ParseMode(m.FileMode.ValueString())
+		if err != nil {
+			return opts, err
+		}
+		opts.Mode, opts.KeepMode = mode, false
+	}
+	return opts, nil
+}
+
+// writeFile writes data to path atomically using the resolved options.
+func (m fileWriteOptionsModel) writeFile(path string, data []byte, defaultMode os.FileMode) error {
+	opts, err := m.writeOptions(defaultMode)
+	if err != nil {
+		return err
+	}
+	return fileio.AtomicWrite(path, data, opts)
+}
+
+// writeScript writes a script file, adding execute permission wherever the mode grants read
+// permission when executable is true. A mode kept from the existing file loses its execute
+// permission when executable is false.
+func (m fileWriteOptionsModel) writeScript(path string, data []byte, executable bool) error {
+	opts, err := m.writeOptions(0644)
+	if err != nil {
+		return err
+	}
+	if opts.KeepMode {
+		if info, err := os.Stat(path); err == nil {
+			opts.Mode, opts.KeepMode = info.Mode().Perm()&^0111, false
+		}
+	}
+	if executable {
+		opts.Mode |= (opts.Mode & 0444) >> 2
+	}
+	return fileio.AtomicWrite(path, data, opts)
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFileWriteOptionsModel_writeOptions(t *testing.T) {
+	testCases := []struct {
+		name         string
+		model        fileWriteOptionsModel
+		expectedMode os.FileMode
+		expectBackup bool
+		expectDirs   bool
+		expectKeep   bool
+		expectError  bool
+	}{
+		{
+			name:         "defaults",
+			model:        fileWriteOptionsModel{},
+			expectedMode: 0644,
+			expectDirs:   true,
+			expectKeep:   true,
+		},
+		{
+			name: "configured",
+			model: fileWriteOptionsModel{
+				CreateDirectories: types.BoolValue(false),
+				FileMode:          types.StringValue("0600"),
+				BackupOnWrite:     types.BoolValue(true),
+			},
+			expectedMode: 0600,
+			expectBackup: true,
+		},
+		{
+			name:        "invalid_mode",
+			model:       fileWriteOptionsModel{FileMode: types.StringValue("0999")},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := tc.model.writeOptions(0644)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if opts.Mode != tc.expectedMode || opts.KeepMode != tc.expectKeep || opts.Backup != tc.expectBackup || opts.CreateDirs != tc.expectDirs {
+				t.Errorf("Expected mode %o, keep mode %v, backup %v, create dirs %v, got %+v", tc.expectedMode, tc.expectKeep, tc.expectBackup, tc.expectDirs, opts)
+			}
+		})
+	}
+}
+
+func TestFileWriteOptionsModel_writeScript(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "hooks", "check.sh")
+
+	testCases := []struct {
+		name         string
+		model        fileWriteOptionsModel
+		executable   bool
+		expectedMode os.FileMode
+	}{
+		{name: "executable", executable: true, expectedMode: 0755},
+		{name: "not_executable", executable: false, expectedMode: 0644},
+		{name: "private_executable", model: fileWriteOptionsModel{FileMode: types.StringValue("0600")}, executable: true, expectedMode: 0700},
+		// Without file_mode the existing mode is kept, with execute permission following executable
+		{name: "kept_not_executable", executable: false, expectedMode: 0600},
+		{name: "kept_executable", executable: true, expectedMode: 0700},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.model.writeScript(filePath, []byte("#!/bin/sh\n"), tc.executable); err != nil {
+				t.Fatalf("Failed to write script: %v", err)
+			}
+			info, err := os.Stat(filePath)
+			if err != nil {
+				t.Fatalf("Failed to stat script: %v", err)
+			}
+			if info.Mode().Perm() != tc.expectedMode {
+				t.Errorf("Expected mode %o, got %o", tc.expectedMode, info.Mode().Perm())
+			}
+		})
+	}
+}
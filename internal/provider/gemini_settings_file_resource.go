@@ -15,8 +15,9 @@ import (
 )
 
 var (
-	_ resource.Resource              = &geminiSettingsFileResource{}
-	_ resource.ResourceWithConfigure = &geminiSettingsFileResource{}
+	_ resource.Resource                   = &geminiSettingsFileResource{}
+	_ resource.ResourceWithConfigure      = &geminiSettingsFileResource{}
+	_ resource.ResourceWithValidateConfig = &geminiSettingsFileResource{}
 )
 
 func NewGeminiSettingsFileResource() resource.Resource {
@@ -28,11 +29,13 @@ type geminiSettingsFileResource struct {
 }
 
 type geminiSettingsFileResourceModel struct {
-	Scope      types.String         `tfsdk:"scope"`
-	ProjectDir types.String         `tfsdk:"project_dir"`
-	Settings   *geminiSettingsModel `tfsdk:"settings"`
-	ID         types.String         `tfsdk:"id"`
-	Path       types.String         `tfsdk:"path"`
+	Scope         types.String         `tfsdk:"scope"`
+	ProjectDir    types.String         `tfsdk:"project_dir"`
+	Settings      *geminiSettingsModel `tfsdk:"settings"`
+	ID            types.String         `tfsdk:"id"`
+	Path          types.String         `tfsdk:"path"`
+	ExtraSettings types.String         `tfsdk:"extra_settings"`
+	fileWriteOptionsModel
 }
 
 func (r *geminiSettingsFileResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -199,6 +202,22 @@ func (r *geminiSettingsFileResource) Schema(_ context.Context, _ resource.Schema
 			},
 		},
 	}
+	resp.Schema.Attributes["extra_settings"] = extraSettingsAttribute("`settings.json`")
+	addFileWriteOptionsAttributes(resp.Schema.Attributes, "0644")
+}
+
+func (r *geminiSettingsFileResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config geminiSettingsFileResourceModel
+	// Settings that are not known yet cannot be decoded; they are checked again on apply.
+	if diags := req.Config.Get(ctx, &config); diags.HasError() || config.ExtraSettings.IsNull() {
+		return
+	}
+
+	typed, err := r.settingsModelToMap(ctx, config.Settings)
+	if err != nil {
+		return
+	}
+	validateExtraSettings(&resp.Diagnostics, config.ExtraSettings, typed)
 }
 
 func (r *geminiSettingsFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -218,7 +237,7 @@ func (r *geminiSettingsFileResource) Create(ctx context.Context, req resource.Cr
 	plan.ID = types.StringValue(path)
 	plan.Path = types.StringValue(path)
 
-	if err := r.writeSettingsFile(ctx, path, plan.Settings); err != nil {
+	if err := r.writeSettingsFile(ctx, path, &plan); err != nil {
 		resp.Diagnostics.AddError("Error writing settings file", err.Error())
 		return
 	}
@@ -255,6 +274,12 @@ func (r *geminiSettingsFileResource) Read(ctx context.Context, req resource.Read
 		}
 	}
 
+	// Keys owned by extra_settings are refreshed there and hidden from the typed attributes
+	if extra, err := parseExtraSettings(state.ExtraSettings); err == nil && extra != nil {
+		state.ExtraSettings = refreshExtraSettings(state.ExtraSettings, data)
+		data = stripExtraSettings(data, extra)
+	}
+
 	// Populate state from file content
 	state.Settings = &geminiSettingsModel{}
 	populateGeminiSettingsFromMap(ctx, &resp.Diagnostics, state.Settings, data)
@@ -283,7 +308,7 @@ func (r *geminiSettingsFileResource) Update(ctx context.Context, req resource.Up
 	plan.ID = types.StringValue(path)
 	plan.Path = types.StringValue(path)
 
-	if err := r.writeSettingsFile(ctx, path, plan.Settings); err != nil {
+	if err := r.writeSettingsFile(ctx, path, &plan); err != nil {
 		resp.Diagnostics.AddError("Error writing settings file", err.Error())
 		return
 	}
@@ -349,8 +374,12 @@ func (r *geminiSettingsFileResource) getSettingsPath(plan geminiSettingsFileReso
 	}
 }
 
-func (r *geminiSettingsFileResource) writeSettingsFile(ctx context.Context, path string, settings *geminiSettingsModel) error {
-	settingsMap, err := r.settingsModelToMap(ctx, settings)
+func (r *geminiSettingsFileResource) writeSettingsFile(ctx context.Context, path string, plan *geminiSettingsFileResourceModel) error {
+	typed, err := r.settingsModelToMap(ctx, plan.Settings)
+	if err != nil {
+		return err
+	}
+	settingsMap, err := mergeExtraSettings(typed, plan.ExtraSettings)
 	if err != nil {
 		return err
 	}
@@ -368,12 +397,7 @@ func (r *geminiSettingsFileResource) writeSettingsFile(ctx context.Context, path
 		return fmt.Errorf("failed to marshal settings to JSON: %w", err)
 	}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", dir, err)
-	}
-
-	return os.WriteFile(path, bytes, 0644)
+	return plan.writeFile(path, bytes, 0644)
 }
 
 // settingsModelToMap converts the Terraform model to a map for JSON marshaling.
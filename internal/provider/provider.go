@@ -119,6 +119,7 @@ func (p *agentsmithProvider) Configure(ctx context.Context, req provider.Configu
 func (p *agentsmithProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewClaudeDataSource,
+		NewClaudeHookRunDataSource,
 		NewCodexDataSource,
 		NewGeminiDataSource,
 		NewMcpStdioDataSource,
@@ -131,8 +132,16 @@ func (p *agentsmithProvider) Resources(_ context.Context) []func() resource.Reso
 	return []func() resource.Resource{
 		NewGeminiSettingsFileResource,
 		NewCodexConfigResource,
+		NewCodexProfileResource,
+		NewCodexModelProviderResource,
+		NewCodexMCPServerResource,
+		NewCodexNotifyResource,
 		NewClaudeSettingsResource,
+		NewClaudeManagedSettingsResource,
+		NewClaudePermissionRuleResource,
 		NewClaudeGlobalConfigResource,
+		NewClaudeProjectConfigResource,
+		NewClaudeMCPJSONResource,
 		NewClaudeSubagentResource,
 		NewClaudeCommandResource,
 		NewClaudeHookResource,
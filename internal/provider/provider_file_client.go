@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"terraform-provider-agentsmith/internal/fileio"
 )
 
 // FileClient provides an interface for CRUD operations on a file within a specific working directory.
@@ -31,7 +33,7 @@ func (c *FileClient) resolvePath(path string) string {
 // Create writes content to a new file within the working directory.
 func (c *FileClient) Create(path, content string) error {
 	fullPath := c.resolvePath(path)
-	return os.WriteFile(fullPath, []byte(content), 0644)
+	return fileio.AtomicWrite(fullPath, []byte(content), fileio.WriteOptions{Mode: 0644})
 }
 
 // Read reads the content of an existing file within the working directory.
@@ -44,10 +46,10 @@ func (c *FileClient) Read(path string) (string, error) {
 	return string(data), nil
 }
 
-// Update overwrites an existing file with new content within the working directory.
+// Update overwrites an existing file with new content within the working directory, keeping its mode.
 func (c *FileClient) Update(path, newContent string) error {
 	fullPath := c.resolvePath(path)
-	return os.WriteFile(fullPath, []byte(newContent), 0644)
+	return fileio.AtomicWrite(fullPath, []byte(newContent), fileio.WriteOptions{Mode: 0644, KeepMode: true})
 }
 
 // Delete removes a file from the working directory.
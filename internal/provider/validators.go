@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"terraform-provider-agentsmith/internal/fileio"
+)
+
+var _ validator.String = stringOneOfValidator{}
+
+// stringOneOfValidator validates that a string attribute is one of a fixed set of values.
+type stringOneOfValidator struct {
+	values []string
+}
+
+// stringOneOf returns a validator which ensures the configured value matches one of values.
+// Null and unknown values are not validated.
+func stringOneOf(values ...string) validator.String {
+	return stringOneOfValidator{values: values}
+}
+
+func (v stringOneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", strings.Join(v.values, ", "))
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	for _, allowed := range v.values {
+		if value == allowed {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+	)
+}
+
+var _ validator.String = fileModeValidator{}
+
+// fileModeValidator validates that a string attribute is an octal permission mode.
+type fileModeValidator struct{}
+
+// fileMode returns a validator which ensures the configured value is an octal mode such as `0644`.
+func fileMode() validator.String {
+	return fileModeValidator{}
+}
+
+func (v fileModeValidator) Description(_ context.Context) string {
+	return "value must be an octal file mode between 0000 and 0777"
+}
+
+func (v fileModeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v fileModeValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := fileio.ParseMode(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.String = jsonObjectValidator{}
+
+// jsonObjectValidator validates that a string attribute holds a JSON object.
+type jsonObjectValidator struct{}
+
+// jsonObject returns a validator which ensures the configured value is a JSON-encoded object.
+func jsonObject() validator.String {
+	return jsonObjectValidator{}
+}
+
+func (v jsonObjectValidator) Description(_ context.Context) string {
+	return "value must be a JSON object"
+}
+
+func (v jsonObjectValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonObjectValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(req.ConfigValue.ValueString()), &obj); err != nil || obj == nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, e.g. jsonencode({ ... }).", req.Path, v.Description(ctx)),
+		)
+	}
+}
+
+var _ validator.Map = mapKeysNoneOfValidator{}
+
+// mapKeysNoneOfValidator validates that a map attribute does not use any of a set of keys, for
+// example keys that are managed by dedicated attributes.
+type mapKeysNoneOfValidator struct {
+	keys []string
+}
+
+// mapKeysNoneOf returns a validator which ensures the configured map has none of keys.
+func mapKeysNoneOf(keys ...string) validator.Map {
+	return mapKeysNoneOfValidator{keys: keys}
+}
+
+func (v mapKeysNoneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("map keys must not be any of: %s", strings.Join(v.keys, ", "))
+}
+
+func (v mapKeysNoneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v mapKeysNoneOfValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, key := range v.keys {
+		if _, ok := req.ConfigValue.Elements()[key]; ok {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtMapKey(key),
+				"Invalid Attribute Value",
+				fmt.Sprintf("Attribute %s %s, got: %q. Set it through its own attribute.", req.Path, v.Description(ctx), key),
+			)
+		}
+	}
+}
+
+var _ validator.String = stringMatchesValidator{}
+
+// stringMatchesValidator validates that a string attribute matches a regular expression.
+type stringMatchesValidator struct {
+	pattern     *regexp.Regexp
+	description string
+}
+
+// stringMatches returns a validator which ensures the configured value matches pattern.
+// description explains the expected format in diagnostics.
+func stringMatches(pattern *regexp.Regexp, description string) validator.String {
+	return stringMatchesValidator{pattern: pattern, description: description}
+}
+
+func (v stringMatchesValidator) Description(_ context.Context) string {
+	return v.description
+}
+
+func (v stringMatchesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringMatchesValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueString(); !v.pattern.MatchString(value) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+		)
+	}
+}
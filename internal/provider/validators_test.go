@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestStringOneOfValidator(t *testing.T) {
+	v := stringOneOf("PreToolUse", "PostToolUse")
+
+	testCases := []struct {
+		name        string
+		value       types.String
+		expectError bool
+	}{
+		{
+			name:  "allowed_value",
+			value: types.StringValue("PreToolUse"),
+		},
+		{
+			name:        "disallowed_value",
+			value:       types.StringValue("preToolUse"),
+			expectError: true,
+		},
+		{
+			name:  "null_value",
+			value: types.StringNull(),
+		},
+		{
+			name:  "unknown_value",
+			value: types.StringUnknown(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("event"), ConfigValue: tc.value}
+			resp := &validator.StringResponse{}
+			v.ValidateString(context.Background(), req, resp)
+
+			if tc.expectError && !resp.Diagnostics.HasError() {
+				t.Error("Expected error but got none")
+			}
+			if !tc.expectError && resp.Diagnostics.HasError() {
+				t.Errorf("Unexpected error: %v", resp.Diagnostics.Errors())
+			}
+		})
+	}
+}
+
+func TestFileModeValidator(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       types.String
+		expectError bool
+	}{
+		{name: "leading_zero", value: types.StringValue("0600")},
+		{name: "no_leading_zero", value: types.StringValue("755")},
+		{name: "not_octal", value: types.StringValue("0689"), expectError: true},
+		{name: "symbolic", value: types.StringValue("rw-r--r--"), expectError: true},
+		{name: "null_value", value: types.StringNull()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("file_mode"), ConfigValue: tc.value}
+			resp := &validator.StringResponse{}
+			fileMode().ValidateString(context.Background(), req, resp)
+
+			if tc.expectError != resp.Diagnostics.HasError() {
+				t.Errorf("Expected error %v, got %v", tc.expectError, resp.Diagnostics.Errors())
+			}
+		})
+	}
+}
+
+func TestJSONObjectValidator(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       types.String
+		expectError bool
+	}{
+		{name: "object", value: types.StringValue(`{"sandbox": {"enabled": true}}`)},
+		{name: "empty_object", value: types.StringValue(`{}`)},
+		{name: "array", value: types.StringValue(`["a"]`), expectError: true},
+		{name: "null_literal", value: types.StringValue(`null`), expectError: true},
+		{name: "invalid_json", value: types.StringValue(`{sandbox = true}`), expectError: true},
+		{name: "unknown_value", value: types.StringUnknown()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("extra_settings"), ConfigValue: tc.value}
+			resp := &validator.StringResponse{}
+			jsonObject().ValidateString(context.Background(), req, resp)
+
+			if tc.expectError != resp.Diagnostics.HasError() {
+				t.Errorf("Expected error %v, got %v", tc.expectError, resp.Diagnostics.Errors())
+			}
+		})
+	}
+}
+
+func TestMapKeysNoneOfValidator(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       types.Map
+		expectError bool
+	}{
+		{name: "allowed_keys", value: types.MapValueMust(types.StringType, map[string]attr.Value{"permissionMode": types.StringValue("plan")})},
+		{name: "reserved_key", value: types.MapValueMust(types.StringType, map[string]attr.Value{"tools": types.StringValue("Read")}), expectError: true},
+		{name: "null_value", value: types.MapNull(types.StringType)},
+		{name: "unknown_value", value: types.MapUnknown(types.StringType)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.MapRequest{Path: path.Root("extra_frontmatter"), ConfigValue: tc.value}
+			resp := &validator.MapResponse{}
+			mapKeysNoneOf("name", "tools").ValidateMap(context.Background(), req, resp)
+
+			if tc.expectError != resp.Diagnostics.HasError() {
+				t.Errorf("Expected error %v, got %v", tc.expectError, resp.Diagnostics.Errors())
+			}
+		})
+	}
+}
+
+func TestStringMatchesValidator(t *testing.T) {
+	v := claudeCommandNamespace()
+
+	testCases := []struct {
+		name        string
+		value       types.String
+		expectError bool
+	}{
+		{
+			name:  "single_segment",
+			value: types.StringValue("frontend"),
+		},
+		{
+			name:  "nested_segments",
+			value: types.StringValue("frontend/react_v2"),
+		},
+		{
+			name:        "trailing_slash",
+			value:       types.StringValue("frontend/"),
+			expectError: true,
+		},
+		{
+			name:        "parent_directory",
+			value:       types.StringValue("../outside"),
+			expectError: true,
+		},
+		{
+			name:  "null_value",
+			value: types.StringNull(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("namespace"), ConfigValue: tc.value}
+			resp := &validator.StringResponse{}
+			v.ValidateString(context.Background(), req, resp)
+
+			if tc.expectError && !resp.Diagnostics.HasError() {
+				t.Error("Expected error but got none")
+			}
+			if !tc.expectError && resp.Diagnostics.HasError() {
+				t.Errorf("Unexpected error: %v", resp.Diagnostics.Errors())
+			}
+		})
+	}
+}